@@ -19,7 +19,9 @@ import (
 	"log"
 	"log/slog"
 	"strings"
+	"time"
 
+	"github.com/aerospike/aerospike-backup-cli/cmd/backup/cmd/xdr"
 	"github.com/aerospike/aerospike-backup-cli/internal/backup"
 	"github.com/aerospike/aerospike-backup-cli/internal/config"
 	"github.com/aerospike/aerospike-backup-cli/internal/flags"
@@ -51,6 +53,7 @@ type Cmd struct {
 	flagsAws          *flags.AwsS3
 	flagsGcp          *flags.GcpStorage
 	flagsAzure        *flags.AzureBlob
+	flagsSftp         *flags.Sftp
 	flagsLocal        *flags.Local
 
 	// backup flags.
@@ -76,6 +79,7 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 		flagsAws:          flags.NewAwsS3(flags.OperationBackup),
 		flagsGcp:          flags.NewGcpStorage(flags.OperationBackup),
 		flagsAzure:        flags.NewAzureBlob(flags.OperationBackup),
+		flagsSftp:         flags.NewSftp(flags.OperationBackup),
 		flagsLocal:        flags.NewLocal(flags.OperationBackup),
 		// First init default logger.
 		Logger: logging.NewDefaultLogger(),
@@ -94,18 +98,21 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 	rootCmd.SilenceUsage = true
 
 	// Add sub command
-	// xdrCmd := xdr.NewCmd(
-	// 	c.flagsApp,
-	// 	c.flagsAerospike,
-	// 	c.flagsClientPolicy,
-	// 	c.flagsCompression,
-	// 	c.flagsEncryption,
-	// 	c.flagsSecretAgent,
-	// 	c.flagsAws,
-	// 	c.flagsGcp,
-	// 	c.flagsAzure,
-	// )
-	// rootCmd.AddCommand(xdrCmd)
+	xdrCmd := xdr.NewCmd(
+		c.flagsApp,
+		c.flagsAerospike,
+		c.flagsClientPolicy,
+		c.flagsCompression,
+		c.flagsEncryption,
+		c.flagsSecretAgent,
+		c.flagsAws,
+		c.flagsGcp,
+		c.flagsAzure,
+		c.flagsSftp,
+		c.flagsLocal,
+	)
+	rootCmd.AddCommand(xdrCmd)
+	rootCmd.AddCommand(newSchemaCmd())
 
 	appFlagSet := c.flagsApp.NewFlagSet()
 	aerospikeFlagSet := c.flagsAerospike.NewFlagSet(asFlags.DefaultWrapHelpString)
@@ -118,6 +125,7 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 	awsFlagSet := c.flagsAws.NewFlagSet()
 	gcpFlagSet := c.flagsGcp.NewFlagSet()
 	azureFlagSet := c.flagsAzure.NewFlagSet()
+	sftpFlagSet := c.flagsSftp.NewFlagSet()
 	localFlagSet := c.flagsLocal.NewFlagSet()
 
 	// App flags.
@@ -134,6 +142,7 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 	rootCmd.PersistentFlags().AddFlagSet(awsFlagSet)
 	rootCmd.PersistentFlags().AddFlagSet(gcpFlagSet)
 	rootCmd.PersistentFlags().AddFlagSet(azureFlagSet)
+	rootCmd.PersistentFlags().AddFlagSet(sftpFlagSet)
 	rootCmd.PersistentFlags().AddFlagSet(localFlagSet)
 
 	// Deprecated fields.
@@ -156,6 +165,7 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 		awsFlagSet,
 		gcpFlagSet,
 		azureFlagSet,
+		sftpFlagSet,
 		localFlagSet,
 	)
 
@@ -228,6 +238,16 @@ func (c *Cmd) newServiceConfig() (*config.BackupServiceConfig, error) {
 			return nil, fmt.Errorf("failed to load config file %s: %w", app.ConfigFilePath, err)
 		}
 
+		if serviceConfig.BackupXDR != nil {
+			return nil, fmt.Errorf("config file %s contains backup-xdr section, use xdr command to run xdr backup",
+				app.ConfigFilePath)
+		}
+
+		// Placeholders are expanded once, so all paths get the same date.
+		if err = serviceConfig.ExpandPathTemplates(time.Now()); err != nil {
+			return nil, err
+		}
+
 		return serviceConfig, nil
 	}
 
@@ -243,12 +263,17 @@ func (c *Cmd) newServiceConfig() (*config.BackupServiceConfig, error) {
 		c.flagsAws.GetAwsS3(),
 		c.flagsGcp.GetGcpStorage(),
 		c.flagsAzure.GetAzureBlob(),
+		c.flagsSftp.GetSftp(),
 		c.flagsLocal.GetLocal(),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = serviceConfig.ExpandPathTemplates(time.Now()); err != nil {
+		return nil, err
+	}
+
 	return serviceConfig, nil
 }
 
@@ -268,6 +293,7 @@ func newHelpFunction(
 	awsFlagSet,
 	gcpFlagSet,
 	azureFlagSet,
+	sftpFlagSet,
 	localFlagSet *pflag.FlagSet,
 ) func() {
 	return func() {
@@ -275,9 +301,8 @@ func newHelpFunction(
 		fmt.Println(strings.Repeat("-", len(welcomeMessage)))
 		fmt.Println("\nUsage:")
 		fmt.Println("  abs-backup-cli [flags]")
-
-		// Printing hint for xdr command.
-		//	fmt.Println("  abs-backup-cli xdr [flags]")
+		fmt.Println("  abs-backup-cli schema")
+		fmt.Println("  abs-backup-cli xdr [flags]")
 
 		// Print section: App Flags
 		fmt.Println("\nGeneral Flags:")
@@ -317,7 +342,8 @@ func newHelpFunction(
 
 		// Print section: AWS Flags
 		fmt.Println("\nAWS Storage Flags:\n" +
-			"For S3, the storage bucket name must be set with the --s3-bucket-name flag.\n" +
+			"For S3, the storage bucket name must be set with the --s3-bucket-name flag,\n" +
+			"or with an s3://bucket/path URL in --directory.\n" +
 			"--directory path will only contain the folder name.\n" +
 			"--s3-endpoint-override is used for MinIO storage instead of AWS.\n" +
 			"Any AWS parameter can be retrieved from Secret Agent.")
@@ -325,20 +351,33 @@ func newHelpFunction(
 
 		// Print section: GCP Flags
 		fmt.Println("\nGCP Storage Flags:\n" +
-			"For GCP storage, the bucket name must be set with --gcp-bucket-name flag.\n" +
+			"For GCP storage, the bucket name must be set with --gcp-bucket-name flag,\n" +
+			"or with a gs://bucket/path URL in --directory.\n" +
 			"--directory path will only contain the folder name.\n" +
 			"The flag --gcp-endpoint-override  is optional, and is used for tests or any other GCP emulator.\n" +
+			"For authentication, use --gcp-key-path, or application default credentials are used.\n" +
 			"Any GCP parameter can be retrieved from Secret Agent.")
 		gcpFlagSet.PrintDefaults()
 
 		// Print section: Azure Flags
 		fmt.Println("\nAzure Storage Flags:\n" +
-			"For Azure storage, the container name must be set with --azure-container-name flag.\n" +
+			"For Azure storage, the container name must be set with --azure-container-name flag,\n" +
+			"or with an az://container/path URL in --directory.\n" +
 			"--directory path will only contain folder name.\n" +
 			"The flag --azure-endpoint is also mandatory, as each storage account has different service address.\n" +
 			"For authentication, use --azure-account-name and --azure-account-key, or \n" +
-			"--azure-tenant-id, --azure-client-id and --azure-client-secret.\n" +
+			"--azure-tenant-id, --azure-client-id and --azure-client-secret, or --azure-sas-token.\n" +
+			"For managed identity, workload identity and chained credentials, set --azure-auth-mode.\n" +
 			"Any Azure parameter can be retrieved from Secret Agent.")
 		azureFlagSet.PrintDefaults()
+
+		// Print section: SFTP Flags
+		fmt.Println("\nSFTP Storage Flags:\n" +
+			"For SFTP storage, the host and the user must be set with --sftp-host and --sftp-user flags.\n" +
+			"--directory path is a path on the host, relative paths start from the user home directory.\n" +
+			"For authentication, use --sftp-key-file, --sftp-use-agent or --sftp-password.\n" +
+			"The host key is verified with the known_hosts file.\n" +
+			"Any SFTP parameter can be retrieved from Secret Agent.")
+		sftpFlagSet.PrintDefaults()
 	}
 }
@@ -0,0 +1,138 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xdr
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/backup"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/flags"
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const cleanupMessage = "Remove the DC and unblock MRT writes left on each node of the cluster\n" +
+	"by an interrupted XDR backup. Don't run it while the XDR backup is running, as it stops the backup."
+
+// newCleanupCmd returns a sub command that undoes changes left by an interrupted xdr backup.
+func (c *Cmd) newCleanupCmd() *cobra.Command {
+	cleanupCmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Clean up after an interrupted XDR backup",
+		Long:  cleanupMessage,
+		Args:  cobra.NoArgs,
+		RunE:  c.runCleanup,
+	}
+
+	c.flagsXDRCleanup = flags.NewXDRCleanup()
+	xdrCleanupFlagSet := c.flagsXDRCleanup.NewFlagSet()
+
+	cleanupCmd.Flags().AddFlagSet(xdrCleanupFlagSet)
+
+	helpFunc := newCleanupHelpFunction(xdrCleanupFlagSet)
+
+	cleanupCmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+
+	cleanupCmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+
+	return cleanupCmd
+}
+
+func (c *Cmd) runCleanup(cmd *cobra.Command, _ []string) error {
+	// If no flags were passed, show help.
+	if cmd.Flags().NFlag() == 0 {
+		if err := cmd.Help(); err != nil {
+			log.Println(err)
+
+			return err
+		}
+
+		return nil
+	}
+
+	cleanupParams, err := c.newCleanupServiceConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize app: %w", err)
+	}
+
+	logger, err := logging.NewLogger(cleanupParams.App.LogLevel, cleanupParams.App.Verbose, cleanupParams.App.LogJSON)
+	if err != nil {
+		log.Println(err)
+
+		return err
+	}
+
+	cleanup, err := backup.NewCleanupService(cmd.Context(), cleanupParams, logger)
+	if err != nil {
+		logger.Error("xdr cleanup initialization failed", slog.Any("error", err))
+
+		return err
+	}
+
+	if err = cleanup.Run(cmd.Context()); err != nil {
+		logger.Error("xdr cleanup failed", slog.Any("error", err))
+
+		return err
+	}
+
+	return nil
+}
+
+// newCleanupServiceConfig returns a new *config.XDRCleanupServiceConfig based on the flags or config file.
+// With a config file, the namespace, the DC and the state file are taken from the backup-xdr section.
+func (c *Cmd) newCleanupServiceConfig() (*config.XDRCleanupServiceConfig, error) {
+	app := c.flagsApp.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		serviceConfig, err := config.DecodeBackupServiceConfig(app.ConfigFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", app.ConfigFilePath, err)
+		}
+
+		if serviceConfig.BackupXDR == nil {
+			return nil, fmt.Errorf("config file %s doesn't contain backup-xdr section", app.ConfigFilePath)
+		}
+
+		return serviceConfig.ToXDRCleanupServiceConfig(c.flagsXDRCleanup.GetXDRCleanup()), nil
+	}
+
+	return config.NewXDRCleanupServiceConfig(
+		app,
+		c.flagsAerospike.NewAerospikeConfig(),
+		c.flagsClientPolicy.GetClientPolicy(),
+		c.flagsXDRCleanup.GetXDRCleanup(),
+		c.flagsSecretAgent.GetSecretAgent(),
+	), nil
+}
+
+func newCleanupHelpFunction(xdrCleanupFlagSet *pflag.FlagSet) func() {
+	return func() {
+		fmt.Println(cleanupMessage)
+		fmt.Println("\nUsage:")
+		fmt.Println("  abs-backup-cli xdr cleanup [flags]")
+		fmt.Println("\nXDR Cleanup Flags:")
+		fmt.Println("Aerospike Client and Secret Agent flags of the main documentation are valid for XDR cleanup." +
+			"\nWith --config, the namespace, the DC and the state file are taken from the backup-xdr section.")
+		xdrCleanupFlagSet.PrintDefaults()
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xdr
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/backup"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/flags"
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const statusMessage = "Report the state of the XDR backup DC on each node of the cluster.\n" +
+	"Exits with a non-zero code if the DC is unhealthy, so it can be used as a liveness probe."
+
+// newStatusCmd returns a sub command that reports the status of an xdr backup.
+func (c *Cmd) newStatusCmd() *cobra.Command {
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report the status of an XDR backup",
+		Long:  statusMessage,
+		Args:  cobra.NoArgs,
+		RunE:  c.runStatus,
+	}
+
+	c.flagsXDRStatus = flags.NewXDRStatus()
+	xdrStatusFlagSet := c.flagsXDRStatus.NewFlagSet()
+
+	statusCmd.Flags().AddFlagSet(xdrStatusFlagSet)
+
+	helpFunc := newStatusHelpFunction(xdrStatusFlagSet)
+
+	statusCmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+
+	statusCmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+
+	return statusCmd
+}
+
+func (c *Cmd) runStatus(cmd *cobra.Command, _ []string) error {
+	// If no flags were passed, show help.
+	if cmd.Flags().NFlag() == 0 {
+		if err := cmd.Help(); err != nil {
+			log.Println(err)
+
+			return err
+		}
+
+		return nil
+	}
+
+	statusParams, err := c.newStatusServiceConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize app: %w", err)
+	}
+
+	logger, err := logging.NewLogger(statusParams.App.LogLevel, statusParams.App.Verbose, statusParams.App.LogJSON)
+	if err != nil {
+		log.Println(err)
+
+		return err
+	}
+
+	status, err := backup.NewStatusService(cmd.Context(), statusParams, logger)
+	if err != nil {
+		logger.Error("xdr status initialization failed", slog.Any("error", err))
+
+		return err
+	}
+
+	if err = status.Run(cmd.Context()); err != nil {
+		logger.Error("xdr status check failed", slog.Any("error", err))
+
+		return err
+	}
+
+	return nil
+}
+
+// newStatusServiceConfig returns a new *config.XDRStatusServiceConfig based on the flags or config file.
+// With a config file, the namespace and the DC are taken from the backup-xdr section.
+func (c *Cmd) newStatusServiceConfig() (*config.XDRStatusServiceConfig, error) {
+	app := c.flagsApp.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		serviceConfig, err := config.DecodeBackupServiceConfig(app.ConfigFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", app.ConfigFilePath, err)
+		}
+
+		if serviceConfig.BackupXDR == nil {
+			return nil, fmt.Errorf("config file %s doesn't contain backup-xdr section", app.ConfigFilePath)
+		}
+
+		return serviceConfig.ToXDRStatusServiceConfig(c.flagsXDRStatus.GetXDRStatus()), nil
+	}
+
+	return config.NewXDRStatusServiceConfig(
+		app,
+		c.flagsAerospike.NewAerospikeConfig(),
+		c.flagsClientPolicy.GetClientPolicy(),
+		c.flagsXDRStatus.GetXDRStatus(),
+		c.flagsSecretAgent.GetSecretAgent(),
+	), nil
+}
+
+func newStatusHelpFunction(xdrStatusFlagSet *pflag.FlagSet) func() {
+	return func() {
+		fmt.Println(statusMessage)
+		fmt.Println("\nUsage:")
+		fmt.Println("  abs-backup-cli xdr status [flags]")
+		fmt.Println("\nXDR Status Flags:")
+		fmt.Println("Aerospike Client and Secret Agent flags of the main documentation are valid for XDR status." +
+			"\nWith --config, the namespace and the DC are taken from the backup-xdr section.")
+		xdrStatusFlagSet.PrintDefaults()
+	}
+}
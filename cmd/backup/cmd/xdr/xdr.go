@@ -19,6 +19,7 @@ import (
 	"log"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/aerospike/aerospike-backup-cli/internal/backup"
 	"github.com/aerospike/aerospike-backup-cli/internal/config"
@@ -43,10 +44,15 @@ type Cmd struct {
 	flagsAws          *flags.AwsS3
 	flagsGcp          *flags.GcpStorage
 	flagsAzure        *flags.AzureBlob
+	flagsSftp         *flags.Sftp
 	flagsLocal        *flags.Local
 
 	// Xdr flags
 	flagsBackupXDR *flags.BackupXDR
+	// Xdr status flags
+	flagsXDRStatus *flags.XDRStatus
+	// Xdr cleanup flags
+	flagsXDRCleanup *flags.XDRCleanup
 }
 
 // NewCmd return initialized xdr command.
@@ -60,6 +66,7 @@ func NewCmd(
 	flagsAws *flags.AwsS3,
 	flagsGcp *flags.GcpStorage,
 	flagsAzure *flags.AzureBlob,
+	flagsSftp *flags.Sftp,
 	flagsLocal *flags.Local,
 ) *cobra.Command {
 	c := &Cmd{
@@ -72,6 +79,7 @@ func NewCmd(
 		flagsAws:          flagsAws,
 		flagsGcp:          flagsGcp,
 		flagsAzure:        flagsAzure,
+		flagsSftp:         flagsSftp,
 		flagsLocal:        flagsLocal,
 	}
 
@@ -88,6 +96,9 @@ func NewCmd(
 	// XDR flags.
 	xdrCmd.Flags().AddFlagSet(backupXDRFlagSet)
 
+	xdrCmd.AddCommand(c.newStatusCmd())
+	xdrCmd.AddCommand(c.newCleanupCmd())
+
 	// Beautify help and usage.
 	helpFunc := newHelpFunction(backupXDRFlagSet)
 
@@ -115,16 +126,63 @@ func (c *Cmd) run(cmd *cobra.Command, _ []string) error {
 		return nil
 	}
 
+	// Init app.
+	asbParams, err := c.newServiceConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize app: %w", err)
+	}
+
 	// Init logger.
-	logger, err := logging.NewLogger(c.flagsApp.LogLevel, c.flagsApp.Verbose, c.flagsApp.LogJSON)
+	logger, err := logging.NewLogger(asbParams.App.LogLevel, asbParams.App.Verbose, asbParams.App.LogJSON)
 	if err != nil {
 		log.Println(err)
 
 		return err
 	}
 
-	// Init app.
-	asbParams, err := config.NewBackupServiceConfig(
+	asb, err := backup.NewService(cmd.Context(), asbParams, logger)
+	if err != nil {
+		logger.Error("backup initialization failed", slog.Any("error", err))
+
+		return err
+	}
+
+	if err = asb.Run(cmd.Context()); err != nil {
+		logger.Error("backup failed", slog.Any("error", err))
+
+		return err
+	}
+
+	return nil
+}
+
+// newServiceConfig returns a new *config.BackupServiceConfig based on the flags or config file.
+func (c *Cmd) newServiceConfig() (*config.BackupServiceConfig, error) {
+	app := c.flagsApp.GetApp()
+	// If we have a config file, load serviceConfig from it.
+	if app != nil && app.ConfigFilePath != "" {
+		serviceConfig, err := config.DecodeBackupServiceConfig(app.ConfigFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", app.ConfigFilePath, err)
+		}
+
+		if serviceConfig.BackupXDR == nil {
+			return nil, fmt.Errorf("config file %s doesn't contain backup-xdr section", app.ConfigFilePath)
+		}
+
+		// The backup section is used only for scan backup.
+		serviceConfig.Backup = nil
+		serviceConfig.Jobs = nil
+
+		// Placeholders are expanded once, so all paths get the same date.
+		if err = serviceConfig.ExpandPathTemplates(time.Now()); err != nil {
+			return nil, err
+		}
+
+		return serviceConfig, nil
+	}
+
+	serviceConfig, err := config.NewBackupServiceConfig(
 		c.flagsApp.GetApp(),
 		c.flagsAerospike.NewAerospikeConfig(),
 		c.flagsClientPolicy.GetClientPolicy(),
@@ -136,26 +194,18 @@ func (c *Cmd) run(cmd *cobra.Command, _ []string) error {
 		c.flagsAws.GetAwsS3(),
 		c.flagsGcp.GetGcpStorage(),
 		c.flagsAzure.GetAzureBlob(),
+		c.flagsSftp.GetSftp(),
 		c.flagsLocal.GetLocal(),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to initialize app: %w", err)
-	}
-
-	asb, err := backup.NewService(cmd.Context(), asbParams, logger)
-	if err != nil {
-		logger.Error("backup initialization failed", slog.Any("error", err))
-
-		return err
+		return nil, err
 	}
 
-	if err = asb.Run(cmd.Context()); err != nil {
-		logger.Error("backup failed", slog.Any("error", err))
-
-		return err
+	if err = serviceConfig.ExpandPathTemplates(time.Now()); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return serviceConfig, nil
 }
 
 func newHelpFunction(backupXDRFlagSet *pflag.FlagSet) func() {
@@ -164,6 +214,8 @@ func newHelpFunction(backupXDRFlagSet *pflag.FlagSet) func() {
 		fmt.Println(strings.Repeat("-", len(welcomeMessage)))
 		fmt.Println("\nUsage:")
 		fmt.Println("  abs-backup-cli xdr [flags]")
+		fmt.Println("  abs-backup-cli xdr status [flags]")
+		fmt.Println("  abs-backup-cli xdr cleanup [flags]")
 		// Print section: XDR Flags
 		fmt.Println("\nXDR Backup Flags:")
 		fmt.Println("This sections replace Backup Flags section in main documentation." +
@@ -0,0 +1,147 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/flags"
+	"github.com/aerospike/aerospike-backup-cli/internal/inspect"
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const inspectMessage = "Report the time range, records and namespaces of .asbx files of an XDR backup,\n" +
+	"with a histogram of changes over time. Use it to choose the --point-in-time of a restore."
+
+// newInspectCmd returns a sub command that inspects .asbx files.
+func (c *Cmd) newInspectCmd() *cobra.Command {
+	inspectCmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect .asbx files of an XDR backup",
+		Long:  inspectMessage,
+		Args:  cobra.NoArgs,
+		RunE:  c.runInspect,
+	}
+
+	c.flagsInspect = flags.NewInspect()
+	inspectFlagSet := c.flagsInspect.NewFlagSet()
+
+	inspectCmd.Flags().AddFlagSet(inspectFlagSet)
+
+	helpFunc := newInspectHelpFunction(inspectFlagSet)
+
+	inspectCmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+
+	inspectCmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+
+	return inspectCmd
+}
+
+func (c *Cmd) runInspect(cmd *cobra.Command, _ []string) error {
+	// If no flags were passed, show help.
+	if cmd.Flags().NFlag() == 0 {
+		if err := cmd.Help(); err != nil {
+			return fmt.Errorf("failed to load help: %w", err)
+		}
+
+		return nil
+	}
+
+	inspectParams, err := c.newInspectServiceConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize app: %w", err)
+	}
+
+	logger, err := logging.NewLogger(inspectParams.App.LogLevel, inspectParams.App.Verbose, inspectParams.App.LogJSON)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	// After initialization replace logger.
+	c.Logger = logger
+
+	service, err := inspect.NewService(inspectParams, logger)
+	if err != nil {
+		logger.Error("inspect initialization failed", slog.Any("error", err))
+
+		return err
+	}
+
+	if err = service.Run(cmd.Context()); err != nil {
+		logger.Error("inspect failed", slog.Any("error", err))
+
+		return err
+	}
+
+	return nil
+}
+
+// newInspectServiceConfig returns a new *config.InspectServiceConfig based on the flags or config file.
+// With a config file, storage, compression, encryption and secret agent settings are taken from it.
+func (c *Cmd) newInspectServiceConfig() (*config.InspectServiceConfig, error) {
+	app := c.flagsApp.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		serviceConfig, err := config.DecodeRestoreServiceConfig(app.ConfigFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", app.ConfigFilePath, err)
+		}
+
+		return config.NewInspectServiceConfig(
+			serviceConfig.App,
+			c.flagsInspect.GetInspect(),
+			serviceConfig.Compression,
+			serviceConfig.Encryption,
+			serviceConfig.SecretAgent,
+			serviceConfig.AwsS3,
+			serviceConfig.GcpStorage,
+			serviceConfig.AzureBlob,
+			serviceConfig.Sftp,
+			serviceConfig.Local,
+		), nil
+	}
+
+	return config.NewInspectServiceConfig(
+		app,
+		c.flagsInspect.GetInspect(),
+		c.flagsCompression.GetCompression(),
+		c.flagsEncryption.GetEncryption(),
+		c.flagsSecretAgent.GetSecretAgent(),
+		c.flagsAws.GetAwsS3(),
+		c.flagsGcp.GetGcpStorage(),
+		c.flagsAzure.GetAzureBlob(),
+		c.flagsSftp.GetSftp(),
+		c.flagsLocal.GetLocal(),
+	), nil
+}
+
+func newInspectHelpFunction(inspectFlagSet *pflag.FlagSet) func() {
+	return func() {
+		fmt.Println(inspectMessage)
+		fmt.Println("\nUsage:")
+		fmt.Println("  abs-restore-cli inspect [flags]")
+		fmt.Println("\nInspect Flags:")
+		fmt.Println("Compression and storage flags of the main documentation are valid for inspect." +
+			"\nEncrypted backups are not supported. With --config, they are taken from the configuration file.")
+		inspectFlagSet.PrintDefaults()
+	}
+}
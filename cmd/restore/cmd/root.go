@@ -51,10 +51,14 @@ type Cmd struct {
 	flagsAws          *flags.AwsS3
 	flagsGcp          *flags.GcpStorage
 	flagsAzure        *flags.AzureBlob
+	flagsSftp         *flags.Sftp
+	flagsLocal        *flags.Local
 
 	// Restore flags.
 	flagsRestore *flags.Restore
 	flagsCommon  *flags.Common
+	// Inspect flags.
+	flagsInspect *flags.Inspect
 
 	Logger *slog.Logger
 }
@@ -75,6 +79,8 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 		flagsAws:          flags.NewAwsS3(flags.OperationRestore),
 		flagsGcp:          flags.NewGcpStorage(flags.OperationRestore),
 		flagsAzure:        flags.NewAzureBlob(flags.OperationRestore),
+		flagsSftp:         flags.NewSftp(flags.OperationRestore),
+		flagsLocal:        flags.NewLocal(flags.OperationRestore),
 		// First init default logger.
 		Logger: logging.NewDefaultLogger(),
 	}
@@ -92,6 +98,10 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 	rootCmd.PersistentFlags().SortFlags = false
 	rootCmd.SilenceUsage = true
 
+	// Add sub command
+	rootCmd.AddCommand(newSchemaCmd())
+	rootCmd.AddCommand(c.newInspectCmd())
+
 	appFlagSet := c.flagsApp.NewFlagSet()
 	aerospikeFlagSet := c.flagsAerospike.NewFlagSet(asFlags.DefaultWrapHelpString)
 	clientPolicyFlagSet := c.flagsClientPolicy.NewFlagSet()
@@ -103,6 +113,8 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 	awsFlagSet := c.flagsAws.NewFlagSet()
 	gcpFlagSet := c.flagsGcp.NewFlagSet()
 	azureFlagSet := c.flagsAzure.NewFlagSet()
+	sftpFlagSet := c.flagsSftp.NewFlagSet()
+	localFlagSet := c.flagsLocal.NewFlagSet()
 
 	// App flags.
 	rootCmd.PersistentFlags().AddFlagSet(appFlagSet)
@@ -118,6 +130,8 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 	rootCmd.PersistentFlags().AddFlagSet(awsFlagSet)
 	rootCmd.PersistentFlags().AddFlagSet(gcpFlagSet)
 	rootCmd.PersistentFlags().AddFlagSet(azureFlagSet)
+	rootCmd.PersistentFlags().AddFlagSet(sftpFlagSet)
+	rootCmd.PersistentFlags().AddFlagSet(localFlagSet)
 
 	// Deprecated fields.
 	if err := rootCmd.Flags().MarkDeprecated("nice", "use --bandwidth instead"); err != nil {
@@ -139,6 +153,8 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 		awsFlagSet,
 		gcpFlagSet,
 		azureFlagSet,
+		sftpFlagSet,
+		localFlagSet,
 	)
 
 	rootCmd.SetUsageFunc(func(_ *cobra.Command) error {
@@ -229,6 +245,8 @@ func (c *Cmd) newServiceConfig() (*config.RestoreServiceConfig, error) {
 		c.flagsAws.GetAwsS3(),
 		c.flagsGcp.GetGcpStorage(),
 		c.flagsAzure.GetAzureBlob(),
+		c.flagsSftp.GetSftp(),
+		c.flagsLocal.GetLocal(),
 	)
 	if err != nil {
 		return nil, err
@@ -252,7 +270,9 @@ func newHelpFunction(
 	secretAgentFlagSet,
 	awsFlagSet,
 	gcpFlagSet,
-	azureFlagSet *pflag.FlagSet,
+	azureFlagSet,
+	sftpFlagSet,
+	localFlagSet *pflag.FlagSet,
 ) func() {
 	return func() {
 		fmt.Println(welcomeMessage)
@@ -267,6 +287,8 @@ func newHelpFunction(
 
 		fmt.Println("\nUsage:")
 		fmt.Println("  abs-restore-cli [flags]")
+		fmt.Println("  abs-restore-cli schema")
+		fmt.Println("  abs-restore-cli inspect")
 
 		// Print section: App Flags
 		fmt.Println("\nGeneral Flags:")
@@ -300,9 +322,14 @@ func newHelpFunction(
 			"Example: abs-backup-cli --azure-account-name secret:resource1:azaccount")
 		secretAgentFlagSet.PrintDefaults()
 
+		// Print section: Local Flags
+		fmt.Println("\nLocal Storage Flags:")
+		localFlagSet.PrintDefaults()
+
 		// Print section: AWS Flags
 		fmt.Println("\nAWS Storage Flags:\n" +
-			"For S3, the storage bucket name must be set with the --s3-bucket-name flag.\n" +
+			"For S3, the storage bucket name must be set with the --s3-bucket-name flag,\n" +
+			"or with an s3://bucket/path URL in --directory.\n" +
 			"--directory path will only contain the folder name.\n" +
 			"--s3-endpoint-override is used for MinIO storage instead of AWS.\n" +
 			"Any AWS parameter can be retrieved from Secret Agent.")
@@ -310,20 +337,33 @@ func newHelpFunction(
 
 		// Print section: GCP Flags
 		fmt.Println("\nGCP Storage Flags:\n" +
-			"For GCP storage, the bucket name must be set with --gcp-bucket-name flag.\n" +
+			"For GCP storage, the bucket name must be set with --gcp-bucket-name flag,\n" +
+			"or with a gs://bucket/path URL in --directory.\n" +
 			"--directory path will only contain the folder name.\n" +
 			"The flag --gcp-endpoint-override  is optional, and is used for tests or any other GCP emulator.\n" +
+			"For authentication, use --gcp-key-path, or application default credentials are used.\n" +
 			"Any GCP parameter can be retrieved from Secret Agent.")
 		gcpFlagSet.PrintDefaults()
 
 		// Print section: Azure Flags
 		fmt.Println("\nAzure Storage Flags:\n" +
-			"For Azure storage, the container name must be set with --azure-container-name flag.\n" +
+			"For Azure storage, the container name must be set with --azure-container-name flag,\n" +
+			"or with an az://container/path URL in --directory.\n" +
 			"--directory path will only contain folder name.\n" +
 			"The flag --azure-endpoint is also mandatory, as each storage account has different service address.\n" +
 			"For authentication, use --azure-account-name and --azure-account-key, or \n" +
-			"--azure-tenant-id, --azure-client-id and --azure-client-secret.\n" +
+			"--azure-tenant-id, --azure-client-id and --azure-client-secret, or --azure-sas-token.\n" +
+			"For managed identity, workload identity and chained credentials, set --azure-auth-mode.\n" +
 			"Any Azure parameter can be retrieved from Secret Agent.")
 		azureFlagSet.PrintDefaults()
+
+		// Print section: SFTP Flags
+		fmt.Println("\nSFTP Storage Flags:\n" +
+			"For SFTP storage, the host and the user must be set with --sftp-host and --sftp-user flags.\n" +
+			"--directory path is a path on the host, relative paths start from the user home directory.\n" +
+			"For authentication, use --sftp-key-file, --sftp-use-agent or --sftp-password.\n" +
+			"The host key is verified with the known_hosts file.\n" +
+			"Any SFTP parameter can be retrieved from Secret Agent.")
+		sftpFlagSet.PrintDefaults()
 	}
 }
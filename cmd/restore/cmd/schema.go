@@ -0,0 +1,52 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const schemaMessage = "Print the JSON Schema of the abs-restore-cli YAML configuration file to stdout."
+
+// newSchemaCmd returns a sub command that prints JSON Schema for the --config file.
+func newSchemaCmd() *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print configuration file JSON Schema",
+		Long:  schemaMessage,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			schema, err := config.RestoreSchema()
+			if err != nil {
+				return fmt.Errorf("failed to generate schema: %w", err)
+			}
+
+			fmt.Println(string(schema))
+
+			return nil
+		},
+	}
+
+	schemaCmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		fmt.Println(schemaMessage)
+		fmt.Println("\nUsage:")
+		fmt.Println("  abs-restore-cli schema > restore-schema.json")
+	})
+
+	return schemaCmd
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/aerospike/aerospike-backup-cli/internal/logging"
 	"github.com/aerospike/aerospike-backup-cli/internal/models"
 	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/aerospike-backup-cli/internal/throttle"
 	"github.com/aerospike/aerospike-client-go/v8"
 	"github.com/aerospike/backup-go"
 	bModels "github.com/aerospike/backup-go/models"
@@ -37,6 +38,14 @@ const (
 
 var xdrSupportedVersion = asinfo.AerospikeVersion{Major: 8}
 
+// xdrInfoClient is the part of the info client that is used to clean up after an interrupted XDR backup.
+type xdrInfoClient interface {
+	GetNodesNames() []string
+	GetStats(ctx context.Context, nodeName, dc, namespace string) (asinfo.Stats, error)
+	StopXDR(ctx context.Context, nodeName, dc string) error
+	UnBlockMRTWrites(ctx context.Context, nodeName, namespace string) error
+}
+
 // Service represents a struct that encapsulates components for backup and logging functionalities.
 // It is responsible for managing backup clients, configurations,
 // and related components with additional operational parameters.
@@ -46,8 +55,28 @@ type Service struct {
 	backupConfigXDR *backup.ConfigBackupXDR
 
 	writer backup.Writer
+	// destination is the resolved directory or file of the backup, it is printed in the report.
+	destination string
 	// reader is used to read a state file.
 	reader backup.StreamingReader
+	// throttle is set when limits are changed by a schedule.
+	throttle *throttle.Controller
+
+	// rotation is set for continuous xdr backup.
+	rotation *rotation
+	// xdrStateFile is removed when xdr backup is finished.
+	xdrStateFile string
+
+	// rack is set for backup that is pinned to a rack.
+	rack *rackBackup
+	// rackJobs is the number of started jobs of a rack-pinned backup, it is used as a file prefix.
+	rackJobs int
+	// shard is set for a shard of a distributed backup, its marker is written when the backup is finished.
+	shard *shardBackup
+
+	// jobs are set for multi-job backup.
+	jobs         []*job
+	parallelJobs int
 
 	// Additional params.
 	isEstimate       bool
@@ -66,7 +95,11 @@ func NewService(
 	logger *slog.Logger,
 ) (*Service, error) {
 	// Validations.
-	if err := params.Backup.Validate(); err != nil {
+	if params.IsMultiJob() {
+		if err := models.ValidateBackupJobs(params.Jobs, params.Backup.ParallelJobs); err != nil {
+			return nil, err
+		}
+	} else if err := params.Backup.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -76,48 +109,58 @@ func NewService(
 
 	if err := config.ValidateStorages(
 		true,
+		params.StoragePaths(),
 		params.AwsS3,
 		params.GcpStorage,
 		params.AzureBlob,
+		params.Sftp,
 		params.Local,
 	); err != nil {
 		return nil, err
 	}
 
+	if err := config.ValidateRetention(params.Backup, params.AwsS3, params.AzureBlob); err != nil {
+		return nil, err
+	}
+
 	if err := params.SecretAgent.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Initializations.
-	backupConfig, backupXDRConfig, err := config.NewBackupConfigs(params, logger)
-	if err != nil {
+	if err := params.Throttle.Validate(); err != nil {
 		return nil, err
 	}
 
-	secretAgent := config.NewSecretAgent(backupConfig, backupXDRConfig)
+	if params.IsMultiJob() {
+		return newMultiJobService(ctx, params, logger)
+	}
 
-	// We don't need a writer for estimates.
-	var writer backup.Writer
-	if params.SkipWriterInit() {
-		writer, err = storage.NewBackupWriter(ctx, params, secretAgent, logger)
-		if err != nil {
-			return nil, err
-		}
+	logger.Info("resolved backup destination", slog.String("destination", params.Destination()))
 
-		// For --remove-artifacts we shouldn't start backup.
-		if writer == nil {
-			return nil, nil
-		}
+	// Initializations.
+	if err := resolveState(ctx, params, logger); err != nil {
+		return nil, err
 	}
 
-	reader, err := storage.NewStateReader(ctx, params, secretAgent, logger)
+	backupConfig, backupXDRConfig, err := config.NewBackupConfigs(params, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize state reader: %w", err)
+		return nil, err
+	}
+
+	secretAgent := config.NewSecretAgent(backupConfig, backupXDRConfig)
+
+	// For --remove-artifacts the directory is cleaned on writer initialization, the cluster is not needed.
+	if params.Backup != nil && params.Backup.RemoveArtifacts && !params.Backup.Estimate {
+		_, err = storage.NewBackupWriter(ctx, params, secretAgent, logger)
+		return nil, err
 	}
 
 	var racks string
 	if params.Backup != nil {
 		racks = params.Backup.PreferRacks
+		if params.Backup.BackupRack != "" {
+			racks = params.Backup.BackupRack
+		}
 	}
 
 	aerospikeClient, err := storage.NewAerospikeClient(
@@ -140,16 +183,52 @@ func NewService(
 		return nil, err
 	}
 
-	logger.Info("initializing backup client", slog.String("id", idBackup))
+	backupClient, err := newBackupClient(aerospikeClient, infoPolicy, retryInfoPolicy, logger)
+	if err != nil {
+		return nil, err
+	}
 
-	backupClient, err := backup.NewClient(
-		aerospikeClient,
-		backup.WithLogger(logger),
-		backup.WithID(idBackup),
-		backup.WithInfoPolicies(infoPolicy, retryInfoPolicy),
-	)
+	// Storage is checked before the writer, because the writer can clean the backup directory.
+	if storage.ShouldPreflight(params) {
+		if err = storage.Preflight(ctx, params, secretAgent, backupClient, logger); err != nil {
+			return nil, err
+		}
+
+		// For --preflight-only we shouldn't start backup.
+		if params.Backup.PreflightOnly {
+			logger.Info("storage preflight passed")
+			return nil, nil
+		}
+	}
+
+	// We don't need a writer for estimates.
+	var writer backup.Writer
+	if params.SkipWriterInit() && !params.IsRotateXDR() {
+		writer, err = storage.NewBackupWriter(ctx, params, secretAgent, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reader, err := storage.NewStateReader(ctx, params, secretAgent, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create backup client: %w", err)
+		return nil, fmt.Errorf("failed to initialize state reader: %w", err)
+	}
+
+	var throttleController *throttle.Controller
+	if backupXDRConfig == nil && params.Backup != nil {
+		throttleController = throttle.NewController(
+			params.Throttle, params.Backup.RecordsPerSecond, params.Backup.Bandwidth, logger)
+		writer = throttle.NewWriter(writer, throttleController)
+		throttleController.WatchCluster(throttle.NewInfoStats(aerospikeClient, infoPolicy, params.Backup.Namespace))
+	}
+
+	var xdrRotation *rotation
+	if params.IsRotateXDR() {
+		xdrRotation, err = newRotation(ctx, params, secretAgent, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize xdr rotation: %w", err)
+		}
 	}
 
 	asb := &Service{
@@ -157,19 +236,62 @@ func NewService(
 		backupConfig:    backupConfig,
 		backupConfigXDR: backupXDRConfig,
 		writer:          writer,
+		destination:     params.Destination(),
 		reader:          reader,
+		throttle:        throttleController,
+		rotation:        xdrRotation,
 		logger:          logger,
 		isLogJSON:       params.App.LogJSON,
 	}
 
+	if params.BackupXDR != nil {
+		asb.xdrStateFile = xdrStatePath(
+			params.BackupXDR.CleanupStateFile, params.BackupXDR.DC, params.BackupXDR.Namespace)
+	}
+
 	if params.Backup != nil {
 		asb.isEstimate = params.Backup.Estimate
 		asb.estimatesSamples = params.Backup.EstimateSamples
 	}
 
+	if params.IsShard() && !params.IsXDR() {
+		asb.shard, err = newShardBackup(ctx, params, secretAgent, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Backup != nil && params.Backup.BackupRack != "" && backupXDRConfig == nil {
+		asb.rack, err = newRackBackup(aerospikeClient, infoPolicy, params.Backup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return asb, nil
 }
 
+func newBackupClient(
+	aerospikeClient *aerospike.Client,
+	infoPolicy *aerospike.InfoPolicy,
+	retryInfoPolicy *bModels.RetryPolicy,
+	logger *slog.Logger,
+) (*backup.Client, error) {
+	logger.Info("initializing backup client", slog.String("id", idBackup))
+
+	backupClient, err := backup.NewClient(
+		aerospikeClient,
+		backup.WithLogger(logger),
+		backup.WithID(idBackup),
+		backup.WithInfoPolicies(infoPolicy, retryInfoPolicy),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup client: %w", err)
+	}
+
+	return backupClient, nil
+}
+
 func initXdr(
 	ctx context.Context,
 	params *config.BackupServiceConfig,
@@ -222,6 +344,20 @@ func initXdr(
 
 			return true, nil
 		}
+
+		// Undo changes left by an interrupted backup, and save the state of this one.
+		cleanupClient := &statusInfoClient{
+			Client:  infoClient,
+			cluster: aerospikeClient.Cluster(),
+			policy:  infoPolicy,
+		}
+
+		statePath := xdrStatePath(params.BackupXDR.CleanupStateFile, backupXDRConfig.DC, backupXDRConfig.Namespace)
+
+		if err = recoverXDR(ctx, cleanupClient, statePath, backupXDRConfig.DC, backupXDRConfig.Namespace,
+			logger); err != nil {
+			return false, err
+		}
 	}
 
 	return false, nil
@@ -237,6 +373,8 @@ func (s *Service) Run(ctx context.Context) error {
 	}
 
 	switch {
+	case len(s.jobs) > 0:
+		return s.runJobs(ctx)
 	case s.isEstimate:
 		s.logger.Info("calculating backup estimate")
 		// Calculating estimates.
@@ -246,31 +384,20 @@ func (s *Service) Run(ctx context.Context) error {
 		}
 
 		logging.ReportEstimate(estimates, s.isLogJSON, s.logger)
-	case s.backupConfigXDR != nil:
-		s.logger.Info("starting xdr backup")
-		// Running xdr backup.
-		hXdr, err := s.backupClient.BackupXDR(ctx, s.backupConfigXDR, s.writer)
-		if err != nil {
-			return fmt.Errorf("failed to start xdr backup: %w", err)
+	case s.rotation != nil:
+		if err := s.runRotation(ctx); err != nil {
+			return err
 		}
-		// Backup indexes and udfs.
-		h, err := s.backupClient.Backup(ctx, s.backupConfig, s.writer, s.reader)
-		if err != nil {
-			return fmt.Errorf("failed to start backup of indexes and udfs: %w", err)
-		}
-
-		go logging.PrintBackupEstimate(ctx, hXdr.GetStats(), hXdr.GetMetrics, s.logger)
 
-		if err = hXdr.Wait(ctx); err != nil {
-			return fmt.Errorf("failed to xdr backup: %w", err)
-		}
-
-		if err = h.Wait(ctx); err != nil {
-			return fmt.Errorf("failed to backup indexes and udfs: %w", err)
+		return removeXDRState(s.xdrStateFile)
+	case s.rack != nil:
+		return s.runRack(ctx)
+	case s.backupConfigXDR != nil:
+		if _, err := s.runXDR(ctx, s.backupConfigXDR, s.writer, s.destination); err != nil {
+			return err
 		}
 
-		stats := bModels.SumBackupStats(h.GetStats(), hXdr.GetStats())
-		logging.ReportBackup(stats, true, s.isLogJSON, s.logger)
+		return removeXDRState(s.xdrStateFile)
 	default:
 		s.logger.Info("starting scan backup")
 		// Running ordinary backup.
@@ -279,19 +406,63 @@ func (s *Service) Run(ctx context.Context) error {
 			return fmt.Errorf("failed to start backup: %w", errHumanize(err))
 		}
 
+		s.throttle.AddRecordCounter(h.GetStats().GetReadRecords)
+		go s.throttle.Run(ctx)
 		go logging.PrintBackupEstimate(ctx, h.GetStats(), h.GetMetrics, s.logger)
 
 		if err = h.Wait(ctx); err != nil {
 			return fmt.Errorf("failed to backup: %w", err)
 		}
 
-		logging.ReportBackup(h.GetStats(), false, s.isLogJSON, s.logger)
+		s.throttle.Report()
+		logging.ReportBackup(h.GetStats(), s.destination, false, s.isLogJSON, s.logger)
+
+		if s.shard != nil {
+			return s.shard.finish(ctx, h.GetStats(), s.logger)
+		}
 	}
 
 	return nil
 }
 
-func stopXDR(ctx context.Context, infoClient *asinfo.Client, dc, namespace string) error {
+// runXDR runs xdr backup with the backup of indexes and udfs to the writer of the destination.
+// Returns stats of the xdr backup.
+func (s *Service) runXDR(
+	ctx context.Context, backupConfigXDR *backup.ConfigBackupXDR, writer backup.Writer, destination string,
+) (*bModels.BackupStats, error) {
+	s.logger.Info("starting xdr backup")
+	// Running xdr backup.
+	hXdr, err := s.backupClient.BackupXDR(ctx, backupConfigXDR, writer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start xdr backup: %w", err)
+	}
+	// Backup indexes and udfs.
+	h, err := s.backupClient.Backup(ctx, s.backupConfig, writer, s.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start backup of indexes and udfs: %w", err)
+	}
+
+	// Stop printing estimates when this backup is finished.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go logging.PrintBackupEstimate(runCtx, hXdr.GetStats(), hXdr.GetMetrics, s.logger)
+
+	if err = hXdr.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("failed to xdr backup: %w", err)
+	}
+
+	if err = h.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("failed to backup indexes and udfs: %w", err)
+	}
+
+	stats := bModels.SumBackupStats(h.GetStats(), hXdr.GetStats())
+	logging.ReportBackup(stats, destination, true, s.isLogJSON, s.logger)
+
+	return hXdr.GetStats(), nil
+}
+
+func stopXDR(ctx context.Context, infoClient xdrInfoClient, dc, namespace string) error {
 	nodes := infoClient.GetNodesNames()
 
 	var errs []error
@@ -315,7 +486,7 @@ func stopXDR(ctx context.Context, infoClient *asinfo.Client, dc, namespace strin
 	return nil
 }
 
-func unblockMrt(ctx context.Context, infoClient *asinfo.Client, namespace string) error {
+func unblockMrt(ctx context.Context, infoClient xdrInfoClient, namespace string) error {
 	nodes := infoClient.GetNodesNames()
 
 	var errs []error
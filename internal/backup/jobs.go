@@ -0,0 +1,236 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/aerospike-backup-cli/internal/throttle"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// job contains initialized components of a single job of a multi-job backup.
+type job struct {
+	name         string
+	backupConfig *backup.ConfigBackup
+	writer       backup.Writer
+	// destination is the resolved directory or file of the job.
+	destination string
+	// reader is used to read a state file.
+	reader   backup.StreamingReader
+	throttle *throttle.Controller
+	logger   *slog.Logger
+}
+
+// newMultiJobService initializes a Service that runs each job from the config file.
+// All jobs share one aerospike client, while each job gets its own writer.
+func newMultiJobService(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	jobs := make([]*job, 0, len(params.Jobs))
+	jobParams := make([]*config.BackupServiceConfig, 0, len(params.Jobs))
+
+	var secretAgent *backup.SecretAgentConfig
+
+	for _, j := range params.Jobs {
+		p := params.ForJob(j)
+		jobLogger := logger.With(slog.String("job", j.Name))
+		jobLogger.Info("resolved backup destination", slog.String("destination", p.Destination()))
+
+		if err := resolveState(ctx, p, jobLogger); err != nil {
+			return nil, fmt.Errorf("job %s: %w", j.Name, err)
+		}
+
+		backupConfig, _, err := config.NewBackupConfigs(p, jobLogger)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", j.Name, err)
+		}
+
+		secretAgent = backupConfig.SecretAgentConfig
+
+		jobs = append(jobs, &job{
+			name:         j.Name,
+			backupConfig: backupConfig,
+			destination:  p.Destination(),
+			logger:       jobLogger,
+		})
+		jobParams = append(jobParams, p)
+	}
+
+	aerospikeClient, err := storage.NewAerospikeClient(
+		params.ClientConfig,
+		params.ClientPolicy,
+		params.Backup.PreferRacks,
+		0,
+		logger,
+		secretAgent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aerospike client: %w", err)
+	}
+
+	infoPolicy, retryInfoPolicy := getInfoPolicies(params)
+
+	backupClient, err := newBackupClient(aerospikeClient, infoPolicy, retryInfoPolicy, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Storages of all jobs are checked before any writer is created, because writers can clean directories.
+	preflightOnly := true
+
+	for i, j := range jobs {
+		p := jobParams[i]
+		if !storage.ShouldPreflight(p) {
+			preflightOnly = false
+			continue
+		}
+
+		if err = storage.Preflight(ctx, p, j.backupConfig.SecretAgentConfig, backupClient, j.logger); err != nil {
+			return nil, fmt.Errorf("job %s: %w", j.name, err)
+		}
+
+		preflightOnly = preflightOnly && p.Backup.PreflightOnly
+	}
+
+	// For --preflight-only we shouldn't start backup.
+	if preflightOnly {
+		logger.Info("storage preflight passed")
+		return nil, nil
+	}
+
+	started := jobs[:0]
+
+	for i, j := range jobs {
+		p := jobParams[i]
+
+		// For --preflight-only we shouldn't start the job.
+		if p.Backup.PreflightOnly {
+			continue
+		}
+
+		writer, err := storage.NewBackupWriter(ctx, p, j.backupConfig.SecretAgentConfig, j.logger)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", j.name, err)
+		}
+
+		// For --remove-artifacts we shouldn't start the job.
+		if writer == nil {
+			continue
+		}
+
+		reader, err := storage.NewStateReader(ctx, p, j.backupConfig.SecretAgentConfig, j.logger)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: failed to initialize state reader: %w", j.name, err)
+		}
+
+		// Each job has its own limits, the same as without a schedule.
+		j.throttle = throttle.NewController(p.Throttle, p.Backup.RecordsPerSecond, p.Backup.Bandwidth, j.logger)
+		j.throttle.WatchCluster(throttle.NewInfoStats(aerospikeClient, infoPolicy, j.backupConfig.Namespace))
+		j.writer = throttle.NewWriter(writer, j.throttle)
+		j.reader = reader
+
+		started = append(started, j)
+	}
+
+	if len(started) == 0 {
+		return nil, nil
+	}
+
+	return &Service{
+		backupClient: backupClient,
+		jobs:         started,
+		parallelJobs: params.Backup.ParallelJobs,
+		logger:       logger,
+		isLogJSON:    params.App.LogJSON,
+	}, nil
+}
+
+// runJobs runs all jobs, at most parallelJobs at a time.
+// A failed job doesn't stop the others, all errors are returned together after the report.
+func (s *Service) runJobs(ctx context.Context) error {
+	s.logger.Info("starting multi-job backup",
+		slog.Int("jobs", len(s.jobs)),
+		slog.Int("parallel_jobs", s.parallelJobs),
+	)
+
+	results := make([]logging.BackupJobResult, len(s.jobs))
+	sem := make(chan struct{}, s.parallelJobs)
+
+	var wg sync.WaitGroup
+
+	for i, j := range s.jobs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stats, err := s.runJob(ctx, j)
+			results[i] = logging.BackupJobResult{Name: j.name, Destination: j.destination, Stats: stats, Err: err}
+		}()
+	}
+
+	wg.Wait()
+
+	logging.ReportBackupJobs(results, s.isLogJSON, s.logger)
+
+	errs := make([]error, 0, len(results))
+
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", r.Name, r.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Service) runJob(ctx context.Context, j *job) (*bModels.BackupStats, error) {
+	j.logger.Info("starting scan backup")
+
+	h, err := s.backupClient.Backup(ctx, j.backupConfig, j.writer, j.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start backup: %w", errHumanize(err))
+	}
+
+	// Stop printing the estimate and the throttle schedule when the job is finished.
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	j.throttle.AddRecordCounter(h.GetStats().GetReadRecords)
+	go j.throttle.Run(jobCtx)
+	go logging.PrintBackupEstimate(jobCtx, h.GetStats(), h.GetMetrics, j.logger)
+
+	if err = h.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("failed to backup: %w", err)
+	}
+
+	j.throttle.Report()
+	j.logger.Info("job finished")
+
+	return h.GetStats(), nil
+}
@@ -0,0 +1,477 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+)
+
+const (
+	// rackCheckInterval is the interval of checks that the node of a running job is in the cluster.
+	rackCheckInterval = 5 * time.Second
+	// infoReplicas returns replica bitmaps of each namespace of the node.
+	infoReplicas = "replicas"
+)
+
+// errNodeLost is returned when a node of the backup rack is not in the cluster.
+var errNodeLost = errors.New("node of the backup rack is lost")
+
+// rackCluster is the part of the cluster that is used by rack-pinned backup.
+type rackCluster interface {
+	// RackNodes returns names of active nodes of the rack for the namespace.
+	RackNodes(namespace string, rack int) []string
+	// NodePartitions returns partitions of the namespace that have a replica on the node.
+	NodePartitions(node, namespace string) ([]int, error)
+	IsActive(node string) bool
+	// WaitForMigrations waits until migrations of the cluster are finished.
+	WaitForMigrations(timeout time.Duration) error
+}
+
+// aerospikeRackCluster implements rackCluster for an aerospike cluster.
+type aerospikeRackCluster struct {
+	cluster *aerospike.Cluster
+	policy  *aerospike.InfoPolicy
+}
+
+func (c *aerospikeRackCluster) RackNodes(namespace string, rack int) []string {
+	nodes := make([]string, 0)
+
+	for _, node := range c.cluster.GetNodes() {
+		if !node.IsActive() {
+			continue
+		}
+
+		if r, err := node.Rack(namespace); err == nil && r == rack {
+			nodes = append(nodes, node.GetName())
+		}
+	}
+
+	slices.Sort(nodes)
+
+	return nodes
+}
+
+func (c *aerospikeRackCluster) NodePartitions(nodeName, namespace string) ([]int, error) {
+	node, err := c.cluster.GetNodeByName(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := node.RequestInfo(c.policy, infoReplicas)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReplicas(resp[infoReplicas], namespace)
+}
+
+func (c *aerospikeRackCluster) IsActive(nodeName string) bool {
+	node, err := c.cluster.GetNodeByName(nodeName)
+
+	return err == nil && node.IsActive()
+}
+
+func (c *aerospikeRackCluster) WaitForMigrations(timeout time.Duration) error {
+	if err := c.cluster.WaitUntilMigrationIsFinished(timeout); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// rackBackup contains settings of a backup that is pinned to a rack.
+type rackBackup struct {
+	cluster     rackCluster
+	namespace   string
+	rack        int
+	fallback    string
+	waitTimeout time.Duration
+}
+
+// rackJob is a part of the backup that is read from a node.
+type rackJob struct {
+	// node is empty for partitions that are read from other racks.
+	node       string
+	partitions []int
+}
+
+func newRackBackup(client *aerospike.Client, infoPolicy *aerospike.InfoPolicy, b *models.Backup) (*rackBackup, error) {
+	rack, err := strconv.Atoi(strings.TrimSpace(b.BackupRack))
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup rack %s: %w", b.BackupRack, err)
+	}
+
+	fallback := b.RackFallback
+	if fallback == "" {
+		fallback = models.RackFallbackFail
+	}
+
+	return &rackBackup{
+		cluster:     &aerospikeRackCluster{cluster: client.Cluster(), policy: infoPolicy},
+		namespace:   b.Namespace,
+		rack:        rack,
+		fallback:    fallback,
+		waitTimeout: time.Duration(b.RackWaitTimeout) * time.Millisecond,
+	}, nil
+}
+
+// plan assigns partitions to the nodes of the backup rack that hold their replicas.
+// Returns partitions that don't have a replica on the rack.
+func (r *rackBackup) plan(partitions []int) ([]rackJob, []int, error) {
+	nodes := r.cluster.RackNodes(r.namespace, r.rack)
+	if len(nodes) == 0 {
+		return nil, partitions, nil
+	}
+
+	left := slices.Clone(partitions)
+	jobs := make([]rackJob, 0, len(nodes))
+
+	for _, node := range nodes {
+		replicas, err := r.cluster.NodePartitions(node, r.namespace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get partitions of node %s: %w", node, err)
+		}
+
+		var owned []int
+
+		left = slices.DeleteFunc(left, func(p int) bool {
+			if _, ok := slices.BinarySearch(replicas, p); ok {
+				owned = append(owned, p)
+				return true
+			}
+
+			return false
+		})
+
+		if len(owned) > 0 {
+			jobs = append(jobs, rackJob{node: node, partitions: owned})
+		}
+	}
+
+	return jobs, left, nil
+}
+
+// waitForMigrations waits for migrations after a node of the rack is lost.
+func (r *rackBackup) waitForMigrations(logger *slog.Logger) error {
+	logger.Info("waiting for migrations to finish", slog.Duration("timeout", r.waitTimeout))
+
+	if err := r.cluster.WaitForMigrations(r.waitTimeout); err != nil {
+		return fmt.Errorf("failed to wait for migrations: %w", err)
+	}
+
+	return nil
+}
+
+// runRack backs up partitions from the nodes of the backup rack, one node after another.
+// When a node is lost, the fallback policy is applied: the backup fails, waits for migrations
+// and continues from the nodes left in the rack, or reads the partitions from other racks.
+//
+//nolint:gocyclo // The fallback policy is applied in one place.
+func (s *Service) runRack(ctx context.Context) error {
+	r := s.rack
+
+	s.logger.Info("starting rack-pinned backup",
+		slog.Int("rack", r.rack),
+		slog.String("fallback", r.fallback),
+	)
+
+	pending := make([]int, backup.MaxPartitions)
+	for i := range pending {
+		pending[i] = i
+	}
+
+	results := make([]logging.BackupNodeResult, 0)
+	// waited prevents waiting again, if no job was finished after the last wait.
+	waited := false
+
+	go s.throttle.Run(ctx)
+
+	for len(pending) > 0 {
+		jobs, missing, err := r.plan(pending)
+		if err != nil {
+			return err
+		}
+
+		if len(missing) > 0 {
+			s.logger.Warn("partitions don't have a replica on the backup rack",
+				slog.Int("rack", r.rack),
+				slog.Int("partitions", len(missing)),
+			)
+
+			switch {
+			case r.fallback == models.RackFallbackOtherRack:
+				jobs = append(jobs, rackJob{partitions: missing})
+			case r.fallback == models.RackFallbackWait && !waited:
+				if err = r.waitForMigrations(s.logger); err != nil {
+					return err
+				}
+
+				waited = true
+
+				continue
+			default:
+				return fmt.Errorf("%d partitions don't have a replica on backup rack %d", len(missing), r.rack)
+			}
+		}
+
+		replan := false
+
+		for _, j := range jobs {
+			result, err := s.runRackJob(ctx, j, len(results) == 0)
+
+			switch {
+			case errors.Is(err, errNodeLost) && r.fallback == models.RackFallbackWait && !waited:
+				if err = r.waitForMigrations(s.logger); err != nil {
+					return err
+				}
+
+				waited, replan = true, true
+			case err != nil:
+				return err
+			default:
+				results = append(results, *result)
+				pending = slices.DeleteFunc(pending, func(p int) bool {
+					_, ok := slices.BinarySearch(j.partitions, p)
+					return ok
+				})
+				waited = false
+
+				// A node was lost during the job, wait before the next one.
+				if result.Fallback && r.fallback == models.RackFallbackWait {
+					if err = r.waitForMigrations(s.logger); err != nil {
+						return err
+					}
+
+					waited, replan = true, true
+				}
+			}
+
+			if replan {
+				break
+			}
+		}
+	}
+
+	s.throttle.Report()
+	logging.ReportBackupRack(results, s.destination, s.isLogJSON, s.logger)
+
+	return nil
+}
+
+// runRackJob backs up partitions of the job. Indexes and udfs are backed up only with metadata.
+// If the node is lost before the job, errNodeLost is returned, unless the fallback is other-rack.
+// If the node is lost during the job, it fails with the fail policy, otherwise the client reads
+// the partitions from other replicas and the result is marked as fallback.
+func (s *Service) runRackJob(ctx context.Context, j rackJob, metadata bool) (*logging.BackupNodeResult, error) {
+	r := s.rack
+	result := &logging.BackupNodeResult{Node: j.node, Rack: r.rack, Partitions: len(j.partitions)}
+
+	switch {
+	case j.node == "":
+		result.Rack = -1
+		result.Fallback = true
+	case !r.cluster.IsActive(j.node):
+		if r.fallback != models.RackFallbackOtherRack {
+			return nil, fmt.Errorf("%w: %s", errNodeLost, j.node)
+		}
+
+		s.logger.Warn("node of the backup rack is lost, reading its partitions from other racks",
+			slog.String("node", j.node))
+
+		result.Fallback = true
+	}
+
+	backupConfig, err := s.rackJobConfig(j, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := s.logger.With(slog.String("node", j.node))
+	logger.Info("starting node backup", slog.Int("partitions", len(j.partitions)))
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	h, err := s.backupClient.Backup(jobCtx, backupConfig, s.writer, s.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start backup: %w", errHumanize(err))
+	}
+
+	s.throttle.AddRecordCounter(h.GetStats().GetReadRecords)
+	go logging.PrintBackupEstimate(jobCtx, h.GetStats(), h.GetMetrics, logger)
+
+	var lost atomic.Bool
+
+	if j.node != "" && !result.Fallback {
+		go s.watchRackNode(jobCtx, j.node, &lost, cancel, logger)
+	}
+
+	if err = h.Wait(jobCtx); err != nil {
+		if cause := context.Cause(jobCtx); errors.Is(cause, errNodeLost) {
+			err = cause
+		}
+
+		return nil, fmt.Errorf("failed to backup from node %s: %w", j.node, err)
+	}
+
+	if lost.Load() {
+		result.Fallback = true
+	}
+
+	result.Stats = h.GetStats()
+
+	return result, nil
+}
+
+// watchRackNode checks that the node is in the cluster until ctx is done.
+// With the fail policy, a lost node cancels the job.
+func (s *Service) watchRackNode(
+	ctx context.Context, node string, lost *atomic.Bool, cancel context.CancelCauseFunc, logger *slog.Logger,
+) {
+	ticker := time.NewTicker(rackCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if s.rack.cluster.IsActive(node) {
+			continue
+		}
+
+		if s.rack.fallback == models.RackFallbackFail {
+			cancel(fmt.Errorf("%w: %s", errNodeLost, node))
+			return
+		}
+
+		logger.Warn("node of the backup rack is lost, its partitions are read from other replicas")
+		lost.Store(true)
+
+		return
+	}
+}
+
+// rackJobConfig returns the backup config for the partitions of the job.
+// Files of each job have their own prefix, so they don't overwrite files of previous jobs.
+func (s *Service) rackJobConfig(j rackJob, metadata bool) (*backup.ConfigBackup, error) {
+	filters, err := partitionFilters(j.partitions, s.backupConfig.ParallelRead)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rackJobs++
+
+	c := *s.backupConfig
+	c.PartitionFilters = filters
+	c.ParallelRead = len(filters)
+	c.OutputFilePrefix = fmt.Sprintf("%s%d_", s.backupConfig.OutputFilePrefix, s.rackJobs)
+
+	if !metadata {
+		c.NoIndexes = true
+		c.NoUDFs = true
+	}
+
+	return &c, nil
+}
+
+// partitionFilters splits sorted partitions to at most parallel filters.
+func partitionFilters(partitions []int, parallel int) ([]*aerospike.PartitionFilter, error) {
+	parallel = max(min(parallel, len(partitions)), 1)
+	filters := make([]*aerospike.PartitionFilter, 0, parallel)
+
+	for i := range parallel {
+		ids := partitions[i*len(partitions)/parallel : (i+1)*len(partitions)/parallel]
+
+		filter, err := backup.NewPartitionFilterByIDs(ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create partition filter: %w", err)
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// parseReplicas parses the replicas info response and returns sorted partitions of the namespace,
+// for which the node holds any replica.
+// The response format is <namespace>:<regime>,<replicas count>,<bitmap>[,<bitmap>...];...
+// where each bitmap is base64 encoded and has a bit for each partition.
+func parseReplicas(resp, namespace string) ([]int, error) {
+	for _, ns := range strings.Split(strings.TrimSpace(resp), ";") {
+		name, value, ok := strings.Cut(ns, ":")
+		if !ok || name != namespace {
+			continue
+		}
+
+		values := strings.Split(value, ",")
+		if len(values) < 2 {
+			return nil, fmt.Errorf("invalid replicas response for namespace %s: %s", namespace, value)
+		}
+
+		count, err := strconv.Atoi(values[1])
+		if err != nil || len(values) != count+2 {
+			return nil, fmt.Errorf("invalid replicas count for namespace %s: %s", namespace, values[1])
+		}
+
+		owned := make([]bool, backup.MaxPartitions)
+
+		for _, encoded := range values[2:] {
+			bitmap, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode replicas bitmap of namespace %s: %w", namespace, err)
+			}
+
+			if len(bitmap) != backup.MaxPartitions/8 {
+				return nil, fmt.Errorf("invalid replicas bitmap size %d of namespace %s", len(bitmap), namespace)
+			}
+
+			for p := range owned {
+				if bitmap[p>>3]&(0x80>>(p&7)) != 0 {
+					owned[p] = true
+				}
+			}
+		}
+
+		partitions := make([]int, 0)
+
+		for p, ok := range owned {
+			if ok {
+				partitions = append(partitions, p)
+			}
+		}
+
+		return partitions, nil
+	}
+
+	return nil, fmt.Errorf("namespace %s not found in replicas response", namespace)
+}
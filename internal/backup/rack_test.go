@@ -0,0 +1,162 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRackCluster has two racks, nodes of a rack hold all partitions between them.
+type fakeRackCluster struct {
+	racks      map[int][]string
+	partitions map[string][]int
+	lost       map[string]bool
+}
+
+func (f *fakeRackCluster) RackNodes(_ string, rack int) []string {
+	return slices.DeleteFunc(slices.Clone(f.racks[rack]), func(node string) bool { return f.lost[node] })
+}
+
+func (f *fakeRackCluster) NodePartitions(node, _ string) ([]int, error) {
+	return f.partitions[node], nil
+}
+
+func (f *fakeRackCluster) IsActive(node string) bool {
+	return !f.lost[node]
+}
+
+func (f *fakeRackCluster) WaitForMigrations(_ time.Duration) error {
+	return nil
+}
+
+func partitionRange(begin, end int) []int {
+	result := make([]int, 0, end-begin)
+	for p := begin; p < end; p++ {
+		result = append(result, p)
+	}
+
+	return result
+}
+
+func newFakeRackCluster() *fakeRackCluster {
+	return &fakeRackCluster{
+		racks: map[int][]string{1: {"A1", "A2"}, 2: {"B1"}},
+		partitions: map[string][]int{
+			"A1": partitionRange(0, 2048),
+			"A2": partitionRange(2048, 4096),
+			"B1": partitionRange(0, 4096),
+		},
+		lost: map[string]bool{},
+	}
+}
+
+func TestRackBackup_Plan(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeRackCluster()
+	r := &rackBackup{cluster: cluster, namespace: testNamespace, rack: 1}
+
+	jobs, missing, err := r.plan(partitionRange(0, backup.MaxPartitions))
+	require.NoError(t, err)
+	require.Empty(t, missing)
+	require.Len(t, jobs, 2)
+	require.Equal(t, "A1", jobs[0].node)
+	require.Len(t, jobs[0].partitions, 2048)
+	require.Equal(t, "A2", jobs[1].node)
+	require.Equal(t, 2048, jobs[1].partitions[0])
+
+	// Partitions of the lost node don't have a replica on the rack until migrations are finished.
+	cluster.lost["A2"] = true
+
+	jobs, missing, err = r.plan(partitionRange(2000, 2100))
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, partitionRange(2000, 2048), jobs[0].partitions)
+	require.Equal(t, partitionRange(2048, 2100), missing)
+
+	// No nodes of the rack are in the cluster.
+	r.rack = 3
+
+	jobs, missing, err = r.plan([]int{1, 2})
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	require.Equal(t, []int{1, 2}, missing)
+}
+
+func TestRunRackJob_NodeLost(t *testing.T) {
+	t.Parallel()
+
+	cluster := newFakeRackCluster()
+	cluster.lost["A2"] = true
+
+	s := &Service{
+		rack:   &rackBackup{cluster: cluster, namespace: testNamespace, rack: 1, fallback: models.RackFallbackWait},
+		logger: slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	}
+
+	_, err := s.runRackJob(context.Background(), rackJob{node: "A2", partitions: []int{1}}, true)
+	require.ErrorIs(t, err, errNodeLost)
+}
+
+func TestPartitionFilters(t *testing.T) {
+	t.Parallel()
+
+	filters, err := partitionFilters(partitionRange(0, 10), 4)
+	require.NoError(t, err)
+	require.Len(t, filters, 4)
+
+	// Parallel is reduced to the number of partitions.
+	filters, err = partitionFilters([]int{5, 7}, 8)
+	require.NoError(t, err)
+	require.Len(t, filters, 2)
+}
+
+func TestParseReplicas(t *testing.T) {
+	t.Parallel()
+
+	master := make([]byte, backup.MaxPartitions/8)
+	prole := make([]byte, backup.MaxPartitions/8)
+	// Partition 0 is master, partitions 9 and 4095 are replicas.
+	master[0] = 0x80
+	prole[1] = 0x40
+	prole[511] = 0x01
+
+	encode := base64.StdEncoding.EncodeToString
+	resp := fmt.Sprintf("other:0,1,%s;%s:3,2,%s,%s", encode(prole), testNamespace, encode(master), encode(prole))
+
+	partitions, err := parseReplicas(resp, testNamespace)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 9, 4095}, partitions)
+
+	_, err = parseReplicas(resp, "missing")
+	require.ErrorContains(t, err, "namespace missing not found")
+
+	_, err = parseReplicas(testNamespace+":3,2,AAAA", testNamespace)
+	require.ErrorContains(t, err, "invalid replicas count")
+
+	_, err = parseReplicas(testNamespace+":3,1,AAAA", testNamespace)
+	require.ErrorContains(t, err, "invalid replicas bitmap size 3")
+}
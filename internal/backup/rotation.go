@@ -0,0 +1,320 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/checkpoint"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/backup-go"
+)
+
+// scanRewind is used for the first segment of a backup with a scan, as older changes are in the scan backup.
+const scanRewind = "1"
+
+// rotation contains the state of a continuous xdr backup.
+// Each run is written to a separate segment directory, and the checkpoint is saved when it is finished,
+// so a crash loses only the current segment, and the next start continues from the checkpoint.
+type rotation struct {
+	interval time.Duration
+	// rewind is used for the first segment.
+	rewind      string
+	params      *config.BackupServiceConfig
+	secretAgent *backup.SecretAgentConfig
+	// writer writes the checkpoint to the backup directory.
+	writer     backup.Writer
+	checkpoint *checkpoint.Checkpoint
+	// scanConfig is set if a scan backup must be taken after the next segment.
+	scanConfig *backup.ConfigBackup
+}
+
+func newRotation(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) (*rotation, error) {
+	var (
+		cp  *checkpoint.Checkpoint
+		err error
+	)
+
+	// With --remove-files the previous backup is removed, so we start from scratch.
+	if !params.BackupXDR.RemoveFiles {
+		cp, err = readCheckpoint(ctx, params, sa, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case cp == nil:
+		cp = checkpoint.New(params.BackupXDR.Namespace)
+	case cp.Namespace != params.BackupXDR.Namespace:
+		return nil, fmt.Errorf("checkpoint in %s belongs to namespace %s",
+			params.BackupXDR.Directory, cp.Namespace)
+	default:
+		logger.Info("continuing xdr backup from checkpoint",
+			slog.Time("checkpoint", cp.Time),
+			slog.Int("segments", len(cp.Segments)),
+		)
+	}
+
+	writer, err := storage.NewCheckpointWriter(ctx, params, sa, params.BackupXDR.RemoveFiles, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint writer: %w", err)
+	}
+
+	r := &rotation{
+		interval:    params.BackupXDR.RotateInterval,
+		rewind:      params.BackupXDR.Rewind,
+		params:      params,
+		secretAgent: sa,
+		writer:      writer,
+		checkpoint:  cp,
+	}
+
+	// The scan backup is taken once, an interrupted one is taken again after a restart.
+	if params.BackupXDR.Scan && cp.Scan == nil {
+		r.rewind = scanRewind
+		r.scanConfig = config.NewXDRScanConfig(params)
+	}
+
+	return r, nil
+}
+
+func readCheckpoint(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) (*checkpoint.Checkpoint, error) {
+	reader, err := newDirectoryReader(ctx, params, sa, params.BackupXDR.Directory, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint reader: %w", err)
+	}
+
+	return checkpoint.Read(ctx, reader, params.BackupXDR.Directory)
+}
+
+// newDirectoryReader returns a reader of the backup directory in the backup storage.
+func newDirectoryReader(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	directory string,
+	logger *slog.Logger,
+) (backup.StreamingReader, error) {
+	restoreParams := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common: models.Common{
+				Directory: directory,
+			},
+		},
+		AwsS3:      params.AwsS3,
+		GcpStorage: params.GcpStorage,
+		AzureBlob:  params.AzureBlob,
+		Sftp:       params.Sftp,
+		Local:      params.Local,
+	}
+
+	return storage.NewCheckpointReader(ctx, restoreParams, sa, logger)
+}
+
+// segmentParams returns a copy of the configuration, that writes to the segment directory.
+func (r *rotation) segmentParams(segment string) *config.BackupServiceConfig {
+	backupXDR := *r.params.BackupXDR
+	backupXDR.Directory = path.Join(backupXDR.Directory, segment)
+	// Each segment has a new directory.
+	backupXDR.RemoveFiles = false
+
+	params := *r.params
+	params.BackupXDR = &backupXDR
+
+	return &params
+}
+
+// scanParams returns a copy of the configuration, that writes the scan backup to the scan directory.
+// Files of an interrupted scan backup are removed.
+func (r *rotation) scanParams() *config.BackupServiceConfig {
+	params := *r.params
+	params.Backup = &models.Backup{
+		Common: models.Common{
+			Directory: path.Join(r.params.BackupXDR.Directory, checkpoint.ScanDirectory),
+			Namespace: r.params.BackupXDR.Namespace,
+		},
+		RemoveFiles: true,
+	}
+
+	return &params
+}
+
+// nextSegment returns a segment started at start, and a rewind to run it with.
+// The first segment is started with the configured rewind, next ones continue from the checkpoint.
+func (r *rotation) nextSegment(start time.Time, rewind string) (checkpoint.Segment, string) {
+	segment := checkpoint.Segment{
+		Directory: checkpoint.SegmentName(start),
+		From:      r.checkpoint.Time,
+		To:        start,
+	}
+
+	if !r.checkpoint.Time.IsZero() {
+		return segment, r.checkpoint.Rewind(start)
+	}
+
+	if seconds, err := strconv.ParseInt(rewind, 10, 64); err == nil {
+		segment.From = start.Add(-time.Duration(seconds) * time.Second)
+	}
+
+	return segment, rewind
+}
+
+// runRotation runs xdr backup every rotation interval, until the context is canceled.
+func (s *Service) runRotation(ctx context.Context) error {
+	s.logger.Info("starting continuous xdr backup", slog.Duration("rotate_interval", s.rotation.interval))
+
+	for {
+		start := time.Now()
+
+		if err := s.runSegment(ctx, start); err != nil {
+			return err
+		}
+
+		// Changes made before start are in the segment, so the scan backup started later covers them.
+		if s.rotation.scanConfig != nil {
+			if err := s.runScan(ctx, start); err != nil {
+				return err
+			}
+		}
+
+		timer := time.NewTimer(time.Until(start.Add(s.rotation.interval)))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.logger.Info("continuous xdr backup stopped", slog.Time("checkpoint", s.rotation.checkpoint.Time))
+
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// runSegment runs xdr backup to a new segment, and saves the checkpoint when it is finished.
+// Changes made before start are shipped by xdr before the backup is finished,
+// so the start time becomes the new checkpoint.
+func (s *Service) runSegment(ctx context.Context, start time.Time) error {
+	segment, rewind := s.rotation.nextSegment(start, s.rotation.rewind)
+
+	s.logger.Info("starting xdr backup segment",
+		slog.String("segment", segment.Directory),
+		slog.String("rewind", rewind),
+	)
+
+	segmentParams := s.rotation.segmentParams(segment.Directory)
+
+	writer, err := storage.NewBackupWriter(ctx, segmentParams, s.rotation.secretAgent, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create writer for segment %s: %w", segment.Directory, err)
+	}
+
+	backupConfigXDR := *s.backupConfigXDR
+	backupConfigXDR.Rewind = rewind
+
+	stats, err := s.runXDR(ctx, &backupConfigXDR, writer, segmentParams.Destination())
+	if err != nil {
+		return fmt.Errorf("failed to backup segment %s: %w", segment.Directory, err)
+	}
+
+	segment.Finished = time.Now()
+	segment.Records = stats.GetReadRecords()
+	s.rotation.checkpoint.Add(segment)
+
+	if err = checkpoint.Write(ctx, s.rotation.writer, s.rotation.checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	s.logger.Info("saved xdr checkpoint",
+		slog.String("segment", segment.Directory),
+		slog.Time("checkpoint", s.rotation.checkpoint.Time),
+		slog.Uint64("records", segment.Records),
+	)
+
+	return nil
+}
+
+// runScan runs the scan backup and saves the handover point to the checkpoint and the scan directory.
+// Changes made after handover are restored from the following segments.
+func (s *Service) runScan(ctx context.Context, handover time.Time) error {
+	s.logger.Info("starting scan backup for point-in-time recovery", slog.Time("handover", handover))
+
+	scanParams := s.rotation.scanParams()
+
+	writer, err := storage.NewBackupWriter(ctx, scanParams, s.rotation.secretAgent, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create writer for scan backup: %w", err)
+	}
+
+	h, err := s.backupClient.Backup(ctx, s.rotation.scanConfig, writer, s.reader)
+	if err != nil {
+		return fmt.Errorf("failed to start scan backup: %w", errHumanize(err))
+	}
+
+	// Stop printing estimates when the scan backup is finished.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go logging.PrintBackupEstimate(runCtx, h.GetStats(), h.GetMetrics, s.logger)
+
+	if err = h.Wait(ctx); err != nil {
+		return fmt.Errorf("failed to scan backup: %w", err)
+	}
+
+	logging.ReportBackup(h.GetStats(), scanParams.Destination(), false, s.isLogJSON, s.logger)
+
+	scan := &checkpoint.Scan{
+		Directory: checkpoint.ScanDirectory,
+		Handover:  handover,
+		Finished:  time.Now(),
+	}
+
+	if err = checkpoint.WriteHandover(ctx, writer, scan); err != nil {
+		return fmt.Errorf("failed to save handover: %w", err)
+	}
+
+	s.rotation.checkpoint.Scan = scan
+
+	if err = checkpoint.Write(ctx, s.rotation.writer, s.rotation.checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	s.rotation.scanConfig = nil
+
+	s.logger.Info("saved scan backup handover",
+		slog.Time("handover", scan.Handover),
+		slog.Time("finished", scan.Finished),
+	)
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/checkpoint"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRotationParams(dir string, removeFiles bool) *config.BackupServiceConfig {
+	return &config.BackupServiceConfig{
+		BackupXDR: &models.BackupXDR{
+			Directory:      dir,
+			Namespace:      testNamespace,
+			RemoveFiles:    removeFiles,
+			Rewind:         "all",
+			RotateInterval: time.Minute,
+		},
+	}
+}
+
+func TestRotation_NextSegment(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	r := &rotation{checkpoint: checkpoint.New(testNamespace)}
+
+	// The first segment uses the configured rewind.
+	segment, rewind := r.nextSegment(start, "all")
+	require.Equal(t, "all", rewind)
+	require.Equal(t, "20240501T100000Z", segment.Directory)
+	require.True(t, segment.From.IsZero())
+	require.Equal(t, start, segment.To)
+
+	segment, rewind = r.nextSegment(start, "60")
+	require.Equal(t, "60", rewind)
+	require.Equal(t, start.Add(-time.Minute), segment.From)
+
+	// Next segments continue from the checkpoint.
+	r.checkpoint.Add(segment)
+
+	next := start.Add(15 * time.Minute)
+	segment, rewind = r.nextSegment(next, "all")
+	require.Equal(t, "901", rewind)
+	require.Equal(t, start, segment.From)
+	require.Equal(t, next, segment.To)
+}
+
+func TestRotation_SegmentParams(t *testing.T) {
+	t.Parallel()
+
+	r := &rotation{params: newTestRotationParams("backup", true)}
+
+	params := r.segmentParams("20240501T100000Z")
+	require.Equal(t, path.Join("backup", "20240501T100000Z"), params.BackupXDR.Directory)
+	require.False(t, params.BackupXDR.RemoveFiles)
+	// The original config is not changed.
+	require.Equal(t, "backup", r.params.BackupXDR.Directory)
+	require.True(t, r.params.BackupXDR.RemoveFiles)
+}
+
+func TestRotation_ScanParams(t *testing.T) {
+	t.Parallel()
+
+	r := &rotation{params: newTestRotationParams("backup", false)}
+
+	params := r.scanParams()
+	require.False(t, params.IsXDR())
+	require.Equal(t, path.Join("backup", checkpoint.ScanDirectory), params.Backup.Directory)
+	require.Equal(t, testNamespace, params.Backup.Namespace)
+	// Files of an interrupted scan backup are removed.
+	require.True(t, params.Backup.ShouldClearTarget())
+	require.Nil(t, r.params.Backup)
+}
+
+func TestNewRotation_Scan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	params := newTestRotationParams(dir, false)
+	params.BackupXDR.Scan = true
+
+	// The first segment doesn't rewind, as existing records are in the scan backup.
+	r, err := newRotation(ctx, params, nil, logger)
+	require.NoError(t, err)
+	require.NotNil(t, r.scanConfig)
+	require.Equal(t, testNamespace, r.scanConfig.Namespace)
+	require.Equal(t, scanRewind, r.rewind)
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	segment, _ := r.nextSegment(start, r.rewind)
+	r.checkpoint.Add(segment)
+	require.NoError(t, checkpoint.Write(ctx, r.writer, r.checkpoint))
+
+	// The scan backup was interrupted, so it is taken again.
+	r, err = newRotation(ctx, params, nil, logger)
+	require.NoError(t, err)
+	require.NotNil(t, r.scanConfig)
+
+	r.checkpoint.Scan = &checkpoint.Scan{Directory: checkpoint.ScanDirectory, Handover: start, Finished: start}
+	require.NoError(t, checkpoint.Write(ctx, r.writer, r.checkpoint))
+
+	// The scan backup is finished.
+	r, err = newRotation(ctx, params, nil, logger)
+	require.NoError(t, err)
+	require.Nil(t, r.scanConfig)
+	require.NotNil(t, r.checkpoint.Scan)
+}
+
+func TestNewRotation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	// New backup.
+	r, err := newRotation(ctx, newTestRotationParams(dir, false), nil, logger)
+	require.NoError(t, err)
+	require.True(t, r.checkpoint.Time.IsZero())
+	require.Equal(t, time.Minute, r.interval)
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	segment, _ := r.nextSegment(start, "all")
+	r.checkpoint.Add(segment)
+	require.NoError(t, checkpoint.Write(ctx, r.writer, r.checkpoint))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, segment.Directory), 0o755))
+
+	// Restart continues from the checkpoint.
+	r, err = newRotation(ctx, newTestRotationParams(dir, false), nil, logger)
+	require.NoError(t, err)
+	require.Equal(t, start, r.checkpoint.Time.UTC())
+	require.Len(t, r.checkpoint.Segments, 1)
+
+	// Checkpoint of another namespace.
+	params := newTestRotationParams(dir, false)
+	params.BackupXDR.Namespace = "other"
+	_, err = newRotation(ctx, params, nil, logger)
+	require.ErrorContains(t, err, "belongs to namespace test")
+
+	// Remove files starts from scratch.
+	r, err = newRotation(ctx, newTestRotationParams(dir, true), nil, logger)
+	require.NoError(t, err)
+	require.True(t, r.checkpoint.Time.IsZero())
+	require.NoDirExists(t, filepath.Join(dir, segment.Directory))
+
+	reader, err := local.NewReader(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	cp, err := checkpoint.Read(ctx, reader, dir)
+	require.NoError(t, err)
+	require.Nil(t, cp)
+}
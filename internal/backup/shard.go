@@ -0,0 +1,152 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/shard"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage/objectpath"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// shardBackup contains settings of a shard of a distributed backup.
+type shardBackup struct {
+	shard     int
+	shards    int
+	namespace string
+	// writer writes the completion marker.
+	writer backup.Writer
+}
+
+// newShardBackup checks the backup directory and returns settings of the shard.
+// The directory can contain files of other shards, but not of this one, unless the backup is continued.
+func newShardBackup(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) (*shardBackup, error) {
+	s, shards, err := params.Backup.ParseShard()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := newDirectoryReader(ctx, params, sa, params.Backup.Directory, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shard marker reader: %w", err)
+	}
+
+	markers, err := shard.Read(ctx, reader, params.Backup.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkShardMarkers(markers, s, shards, params.Backup.Directory); err != nil {
+		return nil, err
+	}
+
+	if !params.IsContinue() {
+		objects, err := reader.ListObjects(ctx, params.Backup.Directory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", params.Backup.Directory, err)
+		}
+
+		prefix := params.Backup.OutputFilePrefix + shard.FilePrefix(s, shards)
+		if err = checkShardFiles(objects, prefix, s, shards, params.Backup.Directory); err != nil {
+			return nil, err
+		}
+	}
+
+	writer, err := storage.NewShardMarkerWriter(ctx, params, sa, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shard marker writer: %w", err)
+	}
+
+	begin, count := shard.Partitions(s, shards)
+	logger.Info("backing up shard of distributed backup",
+		slog.String("shard", params.Backup.Shard),
+		slog.Int("partition_begin", begin),
+		slog.Int("partition_count", count),
+		slog.Int("finished_shards", len(markers)),
+	)
+
+	return &shardBackup{
+		shard:     s,
+		shards:    shards,
+		namespace: params.Backup.Namespace,
+		writer:    writer,
+	}, nil
+}
+
+// checkShardMarkers checks that the directory doesn't contain another distributed backup
+// and that the shard is not finished yet.
+func checkShardMarkers(markers []shard.Marker, s, shards int, dir string) error {
+	for _, m := range markers {
+		if m.Shards != shards {
+			return fmt.Errorf("directory %s contains shard %d/%d of another distributed backup", dir, m.Shard, m.Shards)
+		}
+
+		if m.Shard == s {
+			return fmt.Errorf("shard %d/%d is already finished in %s, remove %s to back it up again",
+				s, shards, dir, shard.MarkerName(s, shards))
+		}
+	}
+
+	return nil
+}
+
+// checkShardFiles checks that the directory doesn't contain files of an interrupted backup of the shard.
+func checkShardFiles(objects []string, prefix string, s, shards int, dir string) error {
+	for _, object := range objects {
+		if strings.HasPrefix(objectpath.BaseName(object), prefix) {
+			return fmt.Errorf("directory %s already contains files of shard %d/%d: %s", dir, s, shards, object)
+		}
+	}
+
+	return nil
+}
+
+// finish writes the completion marker of the shard.
+func (s *shardBackup) finish(ctx context.Context, stats *bModels.BackupStats, logger *slog.Logger) error {
+	begin, count := shard.Partitions(s.shard, s.shards)
+
+	marker := &shard.Marker{
+		Shard:          s.shard,
+		Shards:         s.shards,
+		Namespace:      s.namespace,
+		PartitionBegin: begin,
+		PartitionCount: count,
+		Records:        stats.GetReadRecords(),
+		Files:          stats.GetFileCount(),
+		Finished:       time.Now().UTC(),
+	}
+
+	if err := shard.Write(ctx, s.writer, marker); err != nil {
+		return fmt.Errorf("failed to write shard marker: %w", err)
+	}
+
+	logger.Info("shard of distributed backup is finished",
+		slog.String("marker", shard.MarkerName(s.shard, s.shards)))
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/shard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckShardMarkers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		markers []shard.Marker
+		errText string
+	}{
+		{
+			name:    "empty directory",
+			markers: nil,
+		},
+		{
+			name:    "other shards finished",
+			markers: []shard.Marker{{Shard: 1, Shards: 4}, {Shard: 3, Shards: 4}},
+		},
+		{
+			name:    "shard finished",
+			markers: []shard.Marker{{Shard: 2, Shards: 4}},
+			errText: "shard 2/4 is already finished in dir, remove shard_2_of_4.json to back it up again",
+		},
+		{
+			name:    "another backup",
+			markers: []shard.Marker{{Shard: 1, Shards: 8}},
+			errText: "directory dir contains shard 1/8 of another distributed backup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkShardMarkers(tt.markers, 2, 4, "dir")
+			if tt.errText != "" {
+				require.EqualError(t, err, tt.errText)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckShardFiles(t *testing.T) {
+	t.Parallel()
+
+	objects := []string{
+		"dir/shard1of4_test_1.asb",
+		"dir/shard1of4_test_2.asb",
+		"dir/shard_1_of_4.json",
+	}
+
+	require.NoError(t, checkShardFiles(objects, "shard2of4_", 2, 4, "dir"))
+	require.EqualError(t, checkShardFiles(objects, "shard1of4_", 1, 4, "dir"),
+		"directory dir already contains files of shard 1/4: dir/shard1of4_test_1.asb")
+	require.NoError(t, checkShardFiles(objects, "prod_shard1of4_", 1, 4, "dir"))
+}
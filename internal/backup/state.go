@@ -0,0 +1,93 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/shard"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage/objectpath"
+)
+
+// resolveState sets the state file of the backup. Unless it is disabled, a backup to a directory always
+// saves its state, so it can be resumed with the same arguments after the process is killed.
+// With resume, the backup is continued from the state file if it exists in the directory.
+func resolveState(ctx context.Context, params *config.BackupServiceConfig, logger *slog.Logger) error {
+	b := params.Backup
+	if b == nil || b.NoState || b.Continue != "" || !b.SupportsState() {
+		return nil
+	}
+
+	name, err := stateFileName(b)
+	if err != nil {
+		return err
+	}
+
+	if !b.Resume {
+		b.StateFileDst = name
+		return nil
+	}
+
+	reader, err := newDirectoryReader(ctx, params, params.SecretAgentConfig(), b.Directory, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create state reader: %w", err)
+	}
+
+	objects, err := reader.ListObjects(ctx, b.Directory)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", b.Directory, err)
+	}
+
+	for _, object := range objects {
+		if objectpath.BaseName(object) == name {
+			logger.Info("resuming backup from state file", slog.String("state_file", name))
+
+			b.StateFileDst = ""
+			b.Continue = name
+
+			return nil
+		}
+	}
+
+	logger.Info("state file not found, starting new backup", slog.String("state_file", name))
+
+	b.StateFileDst = name
+
+	return nil
+}
+
+// stateFileName returns the name of the state file in the backup directory.
+// Backups that share a directory have their own state files.
+func stateFileName(b *models.Backup) (string, error) {
+	if b.StateFileDst != "" {
+		return b.StateFileDst, nil
+	}
+
+	s, shards, err := b.ParseShard()
+	if err != nil {
+		return "", err
+	}
+
+	name := b.OutputFilePrefix + models.StateFileName
+	if shards > 0 {
+		name = b.OutputFilePrefix + shard.FilePrefix(s, shards) + models.StateFileName
+	}
+
+	return name, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shard2of4_backup.state"), []byte("state"), 0o600))
+
+	tests := []struct {
+		name         string
+		backup       models.Backup
+		stateFileDst string
+		continueFile string
+	}{
+		{
+			name:         "new backup",
+			backup:       models.Backup{FileLimit: 250},
+			stateFileDst: models.StateFileName,
+		},
+		{
+			name:         "resume without state file",
+			backup:       models.Backup{FileLimit: 250, Resume: true},
+			stateFileDst: models.StateFileName,
+		},
+		{
+			name:         "resume shard",
+			backup:       models.Backup{FileLimit: 250, Resume: true, Shard: "2/4"},
+			continueFile: "shard2of4_backup.state",
+		},
+		{
+			name:         "resume with state file dst",
+			backup:       models.Backup{FileLimit: 250, Resume: true, StateFileDst: "shard2of4_backup.state"},
+			continueFile: "shard2of4_backup.state",
+		},
+		{
+			name:         "output file prefix",
+			backup:       models.Backup{FileLimit: 250, OutputFilePrefix: "daily_"},
+			stateFileDst: "daily_backup.state",
+		},
+		{
+			name:   "no state",
+			backup: models.Backup{FileLimit: 250, NoState: true},
+		},
+		{
+			name:   "node list",
+			backup: models.Backup{FileLimit: 250, NodeList: "node1"},
+		},
+		{
+			name:   "no file limit",
+			backup: models.Backup{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := tt.backup
+			b.Directory = dir
+			params := &config.BackupServiceConfig{Backup: &b}
+
+			require.NoError(t, resolveState(ctx, params, logger))
+			require.Equal(t, tt.stateFileDst, b.StateFileDst)
+			require.Equal(t, tt.continueFile, b.Continue)
+		})
+	}
+}
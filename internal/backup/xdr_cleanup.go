@@ -0,0 +1,196 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+)
+
+// xdrCleanupClient is the part of the info client that is used to undo changes made by an XDR backup.
+type xdrCleanupClient interface {
+	xdrStatusClient
+	StopXDR(ctx context.Context, nodeName, dc string) error
+	UnBlockMRTWrites(ctx context.Context, nodeName, namespace string) error
+}
+
+// CleanupService undoes changes left on the cluster by an interrupted XDR backup.
+type CleanupService struct {
+	infoClient xdrCleanupClient
+	params     *models.XDRCleanup
+
+	logger *slog.Logger
+}
+
+// NewCleanupService returns a new CleanupService connected to the cluster.
+func NewCleanupService(
+	_ context.Context,
+	params *config.XDRCleanupServiceConfig,
+	logger *slog.Logger,
+) (*CleanupService, error) {
+	if err := params.XDRCleanup.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := params.SecretAgent.Validate(); err != nil {
+		return nil, err
+	}
+
+	infoClient, err := newStatusInfoClient(
+		params.ClientConfig,
+		params.ClientPolicy,
+		params.SecretAgentConfig(),
+		config.NewInfoPolicy(params.XDRCleanup.InfoTimeout),
+		config.NewRetryPolicy(
+			params.XDRCleanup.InfoRetryIntervalMilliseconds,
+			params.XDRCleanup.InfoRetriesMultiplier,
+			params.XDRCleanup.InfoMaxRetries,
+		),
+		logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CleanupService{
+		infoClient: infoClient,
+		params:     params.XDRCleanup,
+		logger:     logger,
+	}, nil
+}
+
+// Run removes the DC and unblocks MRT writes on each node, where they are left.
+// If the state file exists, the DC and the namespace are taken from it, and it is removed after the cleanup.
+func (s *CleanupService) Run(ctx context.Context) error {
+	path := xdrStatePath(s.params.StateFile, s.params.DC, s.params.Namespace)
+
+	state, err := readXDRState(path)
+	if err != nil {
+		return err
+	}
+
+	dc, namespace := s.params.DC, s.params.Namespace
+
+	switch {
+	case state != nil:
+		dc, namespace = state.DC, state.Namespace
+		s.logger.Info("found xdr state file",
+			slog.String("path", path),
+			slog.String("dc", dc),
+			slog.String("namespace", namespace),
+			slog.Time("started", state.Started),
+		)
+	case s.params.StateFile != "":
+		return fmt.Errorf("xdr state file %s doesn't exist", path)
+	}
+
+	nodes, err := cleanupXDR(ctx, s.infoClient, dc, namespace, s.params.DryRun, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to clean up xdr: %w", err)
+	}
+
+	s.logger.Info("xdr cleanup finished", slog.Int("nodes", nodes), slog.Bool("dry_run", s.params.DryRun))
+
+	if state == nil || s.params.DryRun {
+		return nil
+	}
+
+	return removeXDRState(path)
+}
+
+// recoverXDR undoes changes left on the cluster by an interrupted backup, if the state file exists,
+// and saves the state of the new backup.
+func recoverXDR(
+	ctx context.Context, infoClient xdrCleanupClient, path, dc, namespace string, logger *slog.Logger,
+) error {
+	state, err := readXDRState(path)
+	if err != nil {
+		return err
+	}
+
+	if state != nil {
+		logger.Warn("previous xdr backup was interrupted, cleaning up",
+			slog.String("dc", state.DC),
+			slog.String("namespace", state.Namespace),
+			slog.Time("started", state.Started),
+		)
+
+		if _, err = cleanupXDR(ctx, infoClient, state.DC, state.Namespace, false, logger); err != nil {
+			return fmt.Errorf("failed to clean up after interrupted backup: %w", err)
+		}
+	}
+
+	return writeXDRState(path, &xdrState{DC: dc, Namespace: namespace, Started: time.Now()})
+}
+
+// cleanupXDR removes the DC and unblocks MRT writes on each node, where they are left.
+// With dryRun, the changes are only logged. Returns the number of nodes that need the cleanup.
+func cleanupXDR(
+	ctx context.Context, infoClient xdrCleanupClient, dc, namespace string, dryRun bool, logger *slog.Logger,
+) (int, error) {
+	var (
+		errs  []error
+		nodes int
+	)
+
+	for _, node := range infoClient.GetNodesNames() {
+		status := getNodeXDRStatus(ctx, infoClient, node, dc, namespace)
+		if status.Err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", node, status.Err))
+			continue
+		}
+
+		if !status.DCFound && !status.MRTWritesBlocked {
+			continue
+		}
+
+		nodes++
+
+		logArgs := []any{
+			slog.String("node", node),
+			slog.Bool("dc_found", status.DCFound),
+			slog.Bool("mrt_writes_blocked", status.MRTWritesBlocked),
+		}
+
+		if dryRun {
+			logger.Info("xdr cleanup is required", logArgs...)
+			continue
+		}
+
+		if status.DCFound {
+			if err := infoClient.StopXDR(ctx, node, dc); err != nil {
+				errs = append(errs, fmt.Errorf("failed to stop XDR on node %s: %w", node, err))
+				continue
+			}
+		}
+
+		if status.MRTWritesBlocked {
+			if err := infoClient.UnBlockMRTWrites(ctx, node, namespace); err != nil {
+				errs = append(errs, fmt.Errorf("failed to unblock mrts on node %s: %w", node, err))
+				continue
+			}
+		}
+
+		logger.Info("cleaned up xdr", logArgs...)
+	}
+
+	return nodes, errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCleanupInfoClient records nodes where the DC is stopped and MRT writes are unblocked.
+type fakeCleanupInfoClient struct {
+	*fakeStatusInfoClient
+
+	stopped   []string
+	unblocked []string
+}
+
+func (f *fakeCleanupInfoClient) StopXDR(_ context.Context, nodeName, _ string) error {
+	f.stopped = append(f.stopped, nodeName)
+	return nil
+}
+
+func (f *fakeCleanupInfoClient) UnBlockMRTWrites(_ context.Context, nodeName, _ string) error {
+	f.unblocked = append(f.unblocked, nodeName)
+	return nil
+}
+
+func newFakeCleanupInfoClient() *fakeCleanupInfoClient {
+	return &fakeCleanupInfoClient{
+		fakeStatusInfoClient: &fakeStatusInfoClient{
+			nodes: []string{"A", "B", "C", "D"},
+			// A has the DC and MRT writes blocked, B has only the DC, C has only MRT writes blocked.
+			noDC:       map[string]bool{"C": true, "D": true},
+			mrtBlocked: map[string]bool{"A": true, "C": true},
+		},
+	}
+}
+
+func TestCleanupXDR(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	client := newFakeCleanupInfoClient()
+
+	// Dry run doesn't change the cluster.
+	nodes, err := cleanupXDR(context.Background(), client, testDC, testNamespace, true, logger)
+	require.NoError(t, err)
+	require.Equal(t, 3, nodes)
+	require.Empty(t, client.stopped)
+	require.Empty(t, client.unblocked)
+
+	nodes, err = cleanupXDR(context.Background(), client, testDC, testNamespace, false, logger)
+	require.NoError(t, err)
+	require.Equal(t, 3, nodes)
+	require.Equal(t, []string{"A", "B"}, client.stopped)
+	require.Equal(t, []string{"A", "C"}, client.unblocked)
+}
+
+func TestCleanupXDR_Error(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeCleanupInfoClient()
+	client.failNodes = map[string]bool{"B": true}
+
+	_, err := cleanupXDR(context.Background(), client, testDC, testNamespace, false,
+		slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.ErrorContains(t, err, "node B")
+	// Other nodes are cleaned up.
+	require.Equal(t, []string{"A"}, client.stopped)
+	require.Equal(t, []string{"A", "C"}, client.unblocked)
+}
+
+func TestRecoverXDR(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	path := filepath.Join(t.TempDir(), "state.json")
+	client := newFakeCleanupInfoClient()
+
+	// The first start doesn't clean up.
+	require.NoError(t, recoverXDR(ctx, client, path, testDC, testNamespace, logger))
+	require.Empty(t, client.stopped)
+
+	state, err := readXDRState(path)
+	require.NoError(t, err)
+	require.Equal(t, testDC, state.DC)
+	require.Equal(t, testNamespace, state.Namespace)
+	require.WithinDuration(t, time.Now(), state.Started, time.Minute)
+
+	// The state file is left by an interrupted backup.
+	require.NoError(t, recoverXDR(ctx, client, path, testDC, testNamespace, logger))
+	require.Equal(t, []string{"A", "B"}, client.stopped)
+	require.Equal(t, []string{"A", "C"}, client.unblocked)
+	require.FileExists(t, path)
+
+	require.NoError(t, removeXDRState(path))
+	require.NoFileExists(t, path)
+	// Removing a missing file is not an error.
+	require.NoError(t, removeXDRState(path))
+
+	state, err = readXDRState(path)
+	require.NoError(t, err)
+	require.Nil(t, state)
+}
+
+func TestXDRStatePath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "state.json", xdrStatePath("state.json", testDC, testNamespace))
+	require.Equal(t, filepath.Join(os.TempDir(), "abs-backup-cli-xdr-test-dc1.json"),
+		xdrStatePath("", testDC, testNamespace))
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// xdrState contains changes made on the cluster by an XDR backup.
+// It is saved to a local file when the backup is started, and removed when it is finished.
+// If the file exists on start, the previous backup was interrupted.
+type xdrState struct {
+	DC        string    `json:"dc"`
+	Namespace string    `json:"namespace"`
+	Started   time.Time `json:"started"`
+}
+
+// xdrStatePath returns the state file path. If file is empty, a file named after the DC
+// and the namespace in the temp directory is used, so backups of different DCs don't share it.
+func xdrStatePath(file, dc, namespace string) string {
+	if file != "" {
+		return file
+	}
+
+	return filepath.Join(os.TempDir(), fmt.Sprintf("abs-backup-cli-xdr-%s-%s.json", namespace, dc))
+}
+
+// readXDRState reads the state file. Returns nil if it doesn't exist.
+func readXDRState(path string) (*xdrState, error) {
+	data, err := os.ReadFile(path)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read xdr state file %s: %w", path, err)
+	}
+
+	var state xdrState
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode xdr state file %s: %w", path, err)
+	}
+
+	return &state, nil
+}
+
+// writeXDRState writes the state file. A temporary file is renamed, so a crash doesn't leave a partial file.
+func writeXDRState(path string, state *xdrState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode xdr state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write xdr state file %s: %w", tmp, err)
+	}
+
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to rename xdr state file %s: %w", tmp, err)
+	}
+
+	return nil
+}
+
+// removeXDRState removes the state file, if it exists.
+func removeXDRState(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove xdr state file %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/aerospike/backup-go/pkg/asinfo"
+	"github.com/aerospike/tools-common-go/client"
+)
+
+const (
+	cmdXDRStats        = "get-stats:context=xdr;dc=%s;namespace=%s"
+	cmdNamespaceConfig = "get-config:context=namespace;namespace=%s"
+)
+
+// xdrStatusClient is the part of the info client that is used to check the status of an XDR backup.
+type xdrStatusClient interface {
+	GetNodesNames() []string
+	GetStats(ctx context.Context, nodeName, dc, namespace string) (asinfo.Stats, error)
+	// GetNodeInfo sends the command to the node and returns the response as key-value pairs.
+	GetNodeInfo(nodeName, command string) (map[string]string, error)
+}
+
+// statusInfoClient adds requests to a node, that are not available in the asinfo client.
+type statusInfoClient struct {
+	*asinfo.Client
+	cluster *aerospike.Cluster
+	policy  *aerospike.InfoPolicy
+}
+
+// newStatusInfoClient connects to the cluster and returns an info client.
+func newStatusInfoClient(
+	clientConfig *client.AerospikeConfig,
+	clientPolicy *models.ClientPolicy,
+	sa *backup.SecretAgentConfig,
+	infoPolicy *aerospike.InfoPolicy,
+	retryPolicy *bModels.RetryPolicy,
+	logger *slog.Logger,
+) (*statusInfoClient, error) {
+	aerospikeClient, err := storage.NewAerospikeClient(clientConfig, clientPolicy, "", 0, logger, sa)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aerospike client: %w", err)
+	}
+
+	infoClient, err := asinfo.NewClient(aerospikeClient.Cluster(), infoPolicy, retryPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create info client: %w", err)
+	}
+
+	return &statusInfoClient{
+		Client:  infoClient,
+		cluster: aerospikeClient.Cluster(),
+		policy:  infoPolicy,
+	}, nil
+}
+
+func (c *statusInfoClient) GetNodeInfo(nodeName, command string) (map[string]string, error) {
+	node, err := c.cluster.GetNodeByName(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := node.RequestInfo(c.policy, command)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseInfoValues(resp[command])
+}
+
+// parseInfoValues parses an info response in the key1=value1;key2=value2 format.
+func parseInfoValues(resp string) (map[string]string, error) {
+	if strings.HasPrefix(strings.ToLower(resp), "error") {
+		return nil, fmt.Errorf("info command failed: %s", resp)
+	}
+
+	result := make(map[string]string)
+
+	for _, pair := range strings.Split(resp, ";") {
+		key, value, ok := strings.Cut(pair, "=")
+		if ok {
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+// StatusService checks the status of an XDR backup DC on each node of the cluster.
+type StatusService struct {
+	infoClient xdrStatusClient
+	params     *models.XDRStatus
+
+	isLogJSON bool
+
+	logger *slog.Logger
+}
+
+// NewStatusService returns a new StatusService connected to the cluster.
+func NewStatusService(
+	_ context.Context,
+	params *config.XDRStatusServiceConfig,
+	logger *slog.Logger,
+) (*StatusService, error) {
+	if err := params.XDRStatus.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := params.SecretAgent.Validate(); err != nil {
+		return nil, err
+	}
+
+	infoClient, err := newStatusInfoClient(
+		params.ClientConfig,
+		params.ClientPolicy,
+		params.SecretAgentConfig(),
+		config.NewInfoPolicy(params.XDRStatus.InfoTimeout),
+		config.NewRetryPolicy(
+			params.XDRStatus.InfoRetryIntervalMilliseconds,
+			params.XDRStatus.InfoRetriesMultiplier,
+			params.XDRStatus.InfoMaxRetries,
+		),
+		logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusService{
+		infoClient: infoClient,
+		params:     params.XDRStatus,
+		isLogJSON:  params.App.LogJSON,
+		logger:     logger,
+	}, nil
+}
+
+// Run reports the status of the DC on each node.
+// Returns models.ErrXDRUnhealthy if the DC is not healthy.
+func (s *StatusService) Run(ctx context.Context) error {
+	statuses := getXDRStatus(ctx, s.infoClient, s.params.DC, s.params.Namespace)
+	problems := checkXDRStatus(statuses, s.params.MaxLag, s.params.AllowIdle)
+
+	logging.ReportXDRStatus(statuses, problems, s.isLogJSON, s.logger)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", models.ErrXDRUnhealthy, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// getXDRStatus returns the status of the DC on each node.
+func getXDRStatus(ctx context.Context, infoClient xdrStatusClient, dc, namespace string) []logging.XDRNodeStatus {
+	nodes := infoClient.GetNodesNames()
+	result := make([]logging.XDRNodeStatus, 0, len(nodes))
+
+	for _, node := range nodes {
+		result = append(result, getNodeXDRStatus(ctx, infoClient, node, dc, namespace))
+	}
+
+	return result
+}
+
+func getNodeXDRStatus(
+	ctx context.Context, infoClient xdrStatusClient, node, dc, namespace string,
+) logging.XDRNodeStatus {
+	status := logging.XDRNodeStatus{Node: node}
+
+	nsConfig, err := infoClient.GetNodeInfo(node, fmt.Sprintf(cmdNamespaceConfig, namespace))
+	if err != nil {
+		status.Err = fmt.Errorf("failed to get namespace config: %w", err)
+		return status
+	}
+
+	status.MRTWritesBlocked = nsConfig["disable-mrt-writes"] == "true"
+
+	stats, err := infoClient.GetStats(ctx, node, dc, namespace)
+
+	switch {
+	case err != nil && strings.Contains(err.Error(), "DC not found"):
+		return status
+	case err != nil:
+		status.Err = fmt.Errorf("failed to get xdr stats: %w", err)
+		return status
+	}
+
+	status.DCFound = true
+	status.Lag = stats.Lag
+	status.Recoveries = stats.Recoveries
+	status.RecoveriesPending = stats.RecoveriesPending
+
+	// Counters of shipped records are not returned by the asinfo client.
+	xdrStats, err := infoClient.GetNodeInfo(node, fmt.Sprintf(cmdXDRStats, dc, namespace))
+	if err != nil {
+		status.Err = fmt.Errorf("failed to get xdr stats: %w", err)
+		return status
+	}
+
+	status.InQueue = parseInfoInt(xdrStats, "in_queue")
+	status.InProgress = parseInfoInt(xdrStats, "in_progress")
+	status.Success = parseInfoInt(xdrStats, "success")
+	status.Abandoned = parseInfoInt(xdrStats, "abandoned")
+
+	return status
+}
+
+// parseInfoInt returns the integer value of the key, or 0 if it is missing or invalid.
+func parseInfoInt(values map[string]string, key string) int64 {
+	v, err := strconv.ParseInt(values[key], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return v
+}
+
+// checkXDRStatus returns the list of problems, that make the DC unhealthy.
+// MRT writes are blocked while XDR catches up, so it is a problem only if the DC is missing.
+func checkXDRStatus(statuses []logging.XDRNodeStatus, maxLag int64, allowIdle bool) []string {
+	var (
+		problems []string
+		// Number of nodes with the DC, and without errors.
+		found, checked int
+	)
+
+	for _, s := range statuses {
+		if s.Err != nil {
+			problems = append(problems, fmt.Sprintf("node %s: %v", s.Node, s.Err))
+			continue
+		}
+
+		checked++
+
+		switch {
+		case s.DCFound:
+			found++
+
+			if maxLag > 0 && s.Lag > maxLag {
+				problems = append(problems, fmt.Sprintf("node %s: lag %ds is greater than %ds", s.Node, s.Lag, maxLag))
+			}
+		case s.MRTWritesBlocked:
+			problems = append(problems,
+				fmt.Sprintf("node %s: mrt writes are blocked without dc, use --unblock-mrt", s.Node))
+		}
+	}
+
+	switch {
+	case len(statuses) == 0:
+		problems = append(problems, "no active nodes")
+	case checked > 0 && found == 0 && !allowIdle:
+		problems = append(problems, "dc is not found on any node")
+	case found > 0 && found < checked:
+		problems = append(problems, fmt.Sprintf("dc is found only on %d of %d nodes", found, checked))
+	}
+
+	return problems
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/aerospike/backup-go/pkg/asinfo"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStatusInfoClient struct {
+	nodes []string
+	// nodes without the DC.
+	noDC       map[string]bool
+	mrtBlocked map[string]bool
+	failNodes  map[string]bool
+}
+
+func (f *fakeStatusInfoClient) GetNodesNames() []string {
+	return f.nodes
+}
+
+func (f *fakeStatusInfoClient) GetStats(_ context.Context, nodeName, _, _ string) (asinfo.Stats, error) {
+	if f.noDC[nodeName] {
+		return asinfo.Stats{}, errors.New("failed to get stats: DC not found")
+	}
+
+	return asinfo.Stats{Lag: 5, Recoveries: 2, RecoveriesPending: 1}, nil
+}
+
+func (f *fakeStatusInfoClient) GetNodeInfo(nodeName, command string) (map[string]string, error) {
+	if f.failNodes[nodeName] {
+		return nil, errors.New("timeout")
+	}
+
+	if command == fmt.Sprintf(cmdNamespaceConfig, testNamespace) {
+		return map[string]string{"disable-mrt-writes": fmt.Sprint(f.mrtBlocked[nodeName])}, nil
+	}
+
+	return parseInfoValues("lag=5;in_queue=10;in_progress=3;success=100;abandoned=1")
+}
+
+func TestGetXDRStatus(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeStatusInfoClient{
+		nodes:      []string{"A", "B", "C"},
+		noDC:       map[string]bool{"B": true},
+		mrtBlocked: map[string]bool{"B": true},
+		failNodes:  map[string]bool{"C": true},
+	}
+
+	statuses := getXDRStatus(context.Background(), client, testDC, testNamespace)
+	require.Len(t, statuses, 3)
+
+	require.Equal(t, logging.XDRNodeStatus{
+		Node:              "A",
+		DCFound:           true,
+		Lag:               5,
+		InQueue:           10,
+		InProgress:        3,
+		Success:           100,
+		Abandoned:         1,
+		Recoveries:        2,
+		RecoveriesPending: 1,
+	}, statuses[0])
+
+	require.Equal(t, logging.XDRNodeStatus{Node: "B", MRTWritesBlocked: true}, statuses[1])
+	require.ErrorContains(t, statuses[2].Err, "timeout")
+}
+
+func TestCheckXDRStatus(t *testing.T) {
+	t.Parallel()
+
+	running := logging.XDRNodeStatus{Node: "A", DCFound: true, Lag: 10}
+	idle := logging.XDRNodeStatus{Node: "B"}
+
+	tests := []struct {
+		name      string
+		statuses  []logging.XDRNodeStatus
+		maxLag    int64
+		allowIdle bool
+		want      []string
+	}{
+		{
+			name:     "healthy",
+			statuses: []logging.XDRNodeStatus{running, running},
+		},
+		{
+			name:     "lag is greater than max lag",
+			statuses: []logging.XDRNodeStatus{running},
+			maxLag:   5,
+			want:     []string{"node A: lag 10s is greater than 5s"},
+		},
+		{
+			name:     "lag is not checked",
+			statuses: []logging.XDRNodeStatus{running},
+		},
+		{
+			name:     "dc is missing",
+			statuses: []logging.XDRNodeStatus{idle},
+			want:     []string{"dc is not found on any node"},
+		},
+		{
+			name:      "idle is allowed",
+			statuses:  []logging.XDRNodeStatus{idle, idle},
+			allowIdle: true,
+		},
+		{
+			name:      "dc is missing on some nodes",
+			statuses:  []logging.XDRNodeStatus{running, idle},
+			allowIdle: true,
+			want:      []string{"dc is found only on 1 of 2 nodes"},
+		},
+		{
+			name:      "mrt writes blocked without dc",
+			statuses:  []logging.XDRNodeStatus{{Node: "B", MRTWritesBlocked: true}},
+			allowIdle: true,
+			want:      []string{"node B: mrt writes are blocked without dc, use --unblock-mrt"},
+		},
+		{
+			name:     "mrt writes blocked while xdr catches up",
+			statuses: []logging.XDRNodeStatus{{Node: "A", DCFound: true, MRTWritesBlocked: true}},
+		},
+		{
+			name: "node error",
+			statuses: []logging.XDRNodeStatus{
+				running,
+				{Node: "C", Err: errors.New("timeout")},
+			},
+			want: []string{"node C: timeout"},
+		},
+		{
+			name: "no nodes",
+			want: []string{"no active nodes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, checkXDRStatus(tt.statuses, tt.maxLag, tt.allowIdle))
+		})
+	}
+}
+
+func TestParseInfoValues(t *testing.T) {
+	t.Parallel()
+
+	values, err := parseInfoValues("lag=5;in_queue=10;flag")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"lag": "5", "in_queue": "10"}, values)
+	require.Equal(t, int64(10), parseInfoInt(values, "in_queue"))
+	require.Zero(t, parseInfoInt(values, "missing"))
+
+	_, err = parseInfoValues("ERROR::DC not found")
+	require.ErrorContains(t, err, "DC not found")
+}
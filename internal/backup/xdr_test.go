@@ -0,0 +1,108 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aerospike/backup-go/pkg/asinfo"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeXDRInfoClient struct {
+	nodes []string
+	// nodes without the DC.
+	noDC      map[string]bool
+	failNodes map[string]bool
+
+	stopped   []string
+	unblocked []string
+}
+
+func (f *fakeXDRInfoClient) GetNodesNames() []string {
+	return f.nodes
+}
+
+func (f *fakeXDRInfoClient) GetStats(_ context.Context, nodeName, _, _ string) (asinfo.Stats, error) {
+	if f.noDC[nodeName] {
+		return asinfo.Stats{}, errors.New("failed to get stats: DC not found")
+	}
+
+	return asinfo.Stats{}, nil
+}
+
+func (f *fakeXDRInfoClient) StopXDR(_ context.Context, nodeName, _ string) error {
+	if f.failNodes[nodeName] {
+		return errors.New("timeout")
+	}
+
+	f.stopped = append(f.stopped, nodeName)
+
+	return nil
+}
+
+func (f *fakeXDRInfoClient) UnBlockMRTWrites(_ context.Context, nodeName, _ string) error {
+	if f.failNodes[nodeName] {
+		return errors.New("timeout")
+	}
+
+	f.unblocked = append(f.unblocked, nodeName)
+
+	return nil
+}
+
+func TestStopXDR(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeXDRInfoClient{
+		nodes: []string{"A", "B", "C"},
+		noDC:  map[string]bool{"B": true},
+	}
+
+	require.NoError(t, stopXDR(context.Background(), client, testDC, testNamespace))
+	// Nodes without the DC are skipped.
+	require.Equal(t, []string{"A", "C"}, client.stopped)
+
+	client = &fakeXDRInfoClient{
+		nodes:     []string{"A", "B", "C"},
+		failNodes: map[string]bool{"A": true, "C": true},
+	}
+
+	err := stopXDR(context.Background(), client, testDC, testNamespace)
+	require.ErrorContains(t, err, "failed to stop XDR on node A")
+	require.ErrorContains(t, err, "failed to stop XDR on node C")
+	// A failed node doesn't stop the others.
+	require.Equal(t, []string{"B"}, client.stopped)
+}
+
+func TestUnblockMrt(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeXDRInfoClient{nodes: []string{"A", "B"}}
+
+	require.NoError(t, unblockMrt(context.Background(), client, testNamespace))
+	require.Equal(t, []string{"A", "B"}, client.unblocked)
+
+	client = &fakeXDRInfoClient{
+		nodes:     []string{"A", "B"},
+		failNodes: map[string]bool{"B": true},
+	}
+
+	err := unblockMrt(context.Background(), client, testNamespace)
+	require.ErrorContains(t, err, "failed to unblock mrts on node B")
+	require.Equal(t, []string{"A"}, client.unblocked)
+}
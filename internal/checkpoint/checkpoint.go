@@ -0,0 +1,255 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/storage/objectpath"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+const (
+	// FileName is the name of the checkpoint file in the backup directory.
+	FileName = "xdr_checkpoint.json"
+	// HandoverFileName is the name of the file in the scan backup directory, that contains the handover point.
+	HandoverFileName = "xdr_handover.json"
+	// ScanDirectory is the directory of the scan backup, relative to the backup directory.
+	ScanDirectory = "scan"
+)
+
+// segmentTimeFormat is used for segment directory names, so they are sorted by time.
+const segmentTimeFormat = "20060102T150405Z"
+
+// Checkpoint is the state of a rotated XDR backup. It is saved after each rotation.
+type Checkpoint struct {
+	Namespace string `json:"namespace"`
+	// Time until which all changes are acknowledged by the backup and written to closed files.
+	Time time.Time `json:"time"`
+	// Segments are sorted by time.
+	Segments []Segment `json:"segments"`
+	// Scan is set when the scan backup for point-in-time recovery is finished.
+	Scan *Scan `json:"scan,omitempty"`
+}
+
+// Scan is a scan backup, that is taken after the continuous backup is started.
+type Scan struct {
+	// Directory relative to the backup directory.
+	Directory string `json:"directory"`
+	// Handover is the time from which changes are restored from segments.
+	// The scan backup is started after it.
+	Handover time.Time `json:"handover"`
+	Finished time.Time `json:"finished"`
+}
+
+// Segment is a directory with files written between two rotations.
+type Segment struct {
+	// Directory relative to the backup directory.
+	Directory string `json:"directory"`
+	// From is zero for the first segment, if it was started with rewind all.
+	From time.Time `json:"from"`
+	// All changes made before To are shipped to the segment.
+	To time.Time `json:"to"`
+	// Finished is the time when the segment was closed.
+	// Changes made between To and Finished can be shipped to the segment too.
+	Finished time.Time `json:"finished"`
+	// Number of records received in the segment.
+	Records uint64 `json:"records"`
+}
+
+// New returns an empty checkpoint for the namespace.
+func New(namespace string) *Checkpoint {
+	return &Checkpoint{Namespace: namespace}
+}
+
+// SegmentName returns a directory name for the segment started at t.
+func SegmentName(t time.Time) string {
+	return t.UTC().Format(segmentTimeFormat)
+}
+
+// Add appends the segment and moves the checkpoint time to its end.
+func (c *Checkpoint) Add(s Segment) {
+	c.Segments = append(c.Segments, s)
+	c.Time = s.To
+}
+
+// Rewind returns the number of seconds to rewind XDR to continue from the checkpoint at now.
+// One second is added, as record update times are stored by the database in whole seconds.
+func (c *Checkpoint) Rewind(now time.Time) string {
+	seconds := math.Ceil(now.Sub(c.Time).Seconds())
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	return strconv.FormatInt(int64(seconds)+1, 10)
+}
+
+// Replay returns segments to restore the state at until.
+// If until is zero, all segments are returned.
+// Segments started after until are not returned, segments finished after it contain later changes,
+// that must be skipped by the last update time of the record, see ContainsAfter.
+// With a scan backup, segments start from the handover point, and the restored point must be after the scan.
+func (c *Checkpoint) Replay(until time.Time) ([]Segment, error) {
+	result := make([]Segment, 0, len(c.Segments))
+
+	for _, s := range c.Segments {
+		if c.Scan != nil && !s.To.After(c.Scan.Handover) {
+			// Changes of the segment are in the scan backup.
+			continue
+		}
+
+		if !until.IsZero() && s.From.After(until) {
+			break
+		}
+
+		result = append(result, s)
+	}
+
+	if c.Scan == nil {
+		if len(result) == 0 && !until.IsZero() {
+			return nil, fmt.Errorf("no segments are started by %s", until.Format(time.RFC3339))
+		}
+
+		return result, nil
+	}
+
+	if len(result) == 0 || RestoredPoint(result, until).Before(c.Scan.Finished) {
+		if until.IsZero() {
+			return nil, fmt.Errorf("no checkpoint is saved after the scan backup finished at %s",
+				c.Scan.Finished.Format(time.RFC3339))
+		}
+
+		return nil, fmt.Errorf("point in time %s is before the scan backup finished at %s",
+			until.Format(time.RFC3339), c.Scan.Finished.Format(time.RFC3339))
+	}
+
+	return result, nil
+}
+
+// RestoredPoint returns the time until which all changes are restored from the segments returned by Replay.
+// Changes made before To of the last segment are restored, unless until is earlier.
+func RestoredPoint(replay []Segment, until time.Time) time.Time {
+	if len(replay) == 0 {
+		return time.Time{}
+	}
+
+	last := replay[len(replay)-1].To
+	if !until.IsZero() && until.Before(last) {
+		return until
+	}
+
+	return last
+}
+
+// ContainsAfter reports whether the segment can contain changes made after until.
+// Such changes must be skipped, when the segment is restored to until.
+func (s *Segment) ContainsAfter(until time.Time) bool {
+	return !until.IsZero() && s.finished().After(until)
+}
+
+// finished returns the time when the segment was closed.
+// Checkpoints saved by previous versions don't contain it.
+func (s *Segment) finished() time.Time {
+	if s.Finished.IsZero() {
+		return s.To
+	}
+
+	return s.Finished
+}
+
+// Read reads the checkpoint from the directory.
+// Returns nil if the directory doesn't contain a checkpoint.
+func Read(ctx context.Context, reader backup.StreamingReader, dir string) (*Checkpoint, error) {
+	objects, err := reader.ListObjects(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	for _, object := range objects {
+		if objectpath.BaseName(object) != FileName {
+			continue
+		}
+
+		c, err := readFile(ctx, reader, object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint %s: %w", object, err)
+		}
+
+		return c, nil
+	}
+
+	return nil, nil
+}
+
+func readFile(ctx context.Context, reader backup.StreamingReader, name string) (*Checkpoint, error) {
+	readCh := make(chan bModels.File, 1)
+	errCh := make(chan error, 1)
+
+	go reader.StreamFile(ctx, name, readCh, errCh)
+
+	var file bModels.File
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-errCh:
+		return nil, err
+	case file = <-readCh:
+	}
+
+	defer file.Reader.Close()
+
+	var c Checkpoint
+	// The decoder reads only the first value, so bytes left from a longer previous checkpoint are ignored.
+	if err := json.NewDecoder(file.Reader).Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to decode: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Write writes the checkpoint to the writer directory.
+func Write(ctx context.Context, writer backup.Writer, c *Checkpoint) error {
+	return writeFile(ctx, writer, FileName, c)
+}
+
+// WriteHandover writes the handover point to the scan backup directory.
+func WriteHandover(ctx context.Context, writer backup.Writer, scan *Scan) error {
+	return writeFile(ctx, writer, HandoverFileName, scan)
+}
+
+func writeFile(ctx context.Context, writer backup.Writer, name string, v any) error {
+	file, err := writer.NewWriter(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	if err = json.NewEncoder(file).Encode(v); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/require"
+)
+
+const testNamespace = "test"
+
+func TestCheckpoint_WriteRead(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	reader, err := local.NewReader(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	// No checkpoint in a new directory.
+	c, err := Read(ctx, reader, dir)
+	require.NoError(t, err)
+	require.Nil(t, c)
+
+	writer, err := local.NewWriter(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	c = New(testNamespace)
+
+	for i := range 3 {
+		from := start.Add(time.Duration(i) * time.Minute)
+		c.Add(Segment{
+			Directory: SegmentName(from),
+			From:      from,
+			To:        from.Add(time.Minute),
+			Records:   uint64(i),
+		})
+	}
+
+	require.NoError(t, Write(ctx, writer, c))
+
+	// A shorter checkpoint overwrites the longer one.
+	short := New(testNamespace)
+	short.Add(c.Segments[0])
+	short.Scan = &Scan{Directory: ScanDirectory, Handover: start, Finished: start.Add(time.Minute)}
+	require.NoError(t, Write(ctx, writer, short))
+
+	result, err := Read(ctx, reader, dir)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Equal(t, testNamespace, result.Namespace)
+	require.Len(t, result.Segments, 1)
+	require.Equal(t, "20240501T100000Z", result.Segments[0].Directory)
+	require.True(t, start.Add(time.Minute).Equal(result.Time))
+	require.NotNil(t, result.Scan)
+	require.Equal(t, ScanDirectory, result.Scan.Directory)
+	require.True(t, start.Equal(result.Scan.Handover))
+}
+
+func TestCheckpoint_Rewind(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		checkpoint time.Time
+		want       string
+	}{
+		{
+			name:       "whole seconds",
+			checkpoint: now.Add(-15 * time.Minute),
+			want:       "901",
+		},
+		{
+			name:       "fraction is rounded up",
+			checkpoint: now.Add(-1500 * time.Millisecond),
+			want:       "3",
+		},
+		{
+			name:       "checkpoint in the future",
+			checkpoint: now.Add(time.Minute),
+			want:       "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Checkpoint{Time: tt.checkpoint}
+			require.Equal(t, tt.want, c.Rewind(now))
+		})
+	}
+}
+
+func TestCheckpoint_Replay(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	// Segments are run every 10 minutes and take a minute.
+	newCheckpoint := func(scan *Scan) *Checkpoint {
+		c := New(testNamespace)
+
+		for i := range 4 {
+			to := start.Add(time.Duration(i) * 10 * time.Minute)
+			c.Add(Segment{
+				Directory: SegmentName(to),
+				From:      to.Add(-10 * time.Minute),
+				To:        to,
+				Finished:  to.Add(time.Minute),
+			})
+		}
+
+		c.Scan = scan
+
+		return c
+	}
+
+	scan := &Scan{
+		Directory: ScanDirectory,
+		Handover:  start,
+		Finished:  start.Add(15 * time.Minute),
+	}
+
+	tests := []struct {
+		name    string
+		scan    *Scan
+		until   time.Time
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "all segments",
+			want: []string{"20240501T100000Z", "20240501T101000Z", "20240501T102000Z", "20240501T103000Z"},
+		},
+		{
+			name:  "segment started after point in time is skipped",
+			until: start.Add(15 * time.Minute),
+			want:  []string{"20240501T100000Z", "20240501T101000Z", "20240501T102000Z"},
+		},
+		{
+			name:  "segment covering point in time is included",
+			until: start.Add(20*time.Minute + 30*time.Second),
+			want:  []string{"20240501T100000Z", "20240501T101000Z", "20240501T102000Z", "20240501T103000Z"},
+		},
+		{
+			name:    "point in time before the first segment",
+			until:   start.Add(-11 * time.Minute),
+			wantErr: "no segments are started by 2024-05-01T09:49:00Z",
+		},
+		{
+			name: "scan skips segments before handover",
+			scan: scan,
+			want: []string{"20240501T101000Z", "20240501T102000Z", "20240501T103000Z"},
+		},
+		{
+			name:  "scan with point in time",
+			scan:  scan,
+			until: start.Add(16 * time.Minute),
+			want:  []string{"20240501T101000Z", "20240501T102000Z"},
+		},
+		{
+			name:    "point in time before scan is covered",
+			scan:    scan,
+			until:   start.Add(11 * time.Minute),
+			wantErr: "point in time 2024-05-01T10:11:00Z is before the scan backup finished",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			segments, err := newCheckpoint(tt.scan).Replay(tt.until)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			dirs := make([]string, 0, len(segments))
+			for _, s := range segments {
+				dirs = append(dirs, s.Directory)
+			}
+
+			require.Equal(t, tt.want, dirs)
+		})
+	}
+}
+
+func TestCheckpoint_ReplayScanNotCovered(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	c := New(testNamespace)
+	c.Add(Segment{Directory: SegmentName(start), To: start, Finished: start.Add(time.Minute)})
+	c.Scan = &Scan{Directory: ScanDirectory, Handover: start, Finished: start.Add(time.Hour)}
+
+	// The scan is finished, but the next segment is not saved yet.
+	_, err := c.Replay(time.Time{})
+	require.ErrorContains(t, err, "no checkpoint is saved after the scan backup finished")
+}
+
+func TestRestoredPoint(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	replay := []Segment{
+		{From: start.Add(-10 * time.Minute), To: start, Finished: start.Add(time.Minute)},
+		{From: start, To: start.Add(10 * time.Minute), Finished: start.Add(11 * time.Minute)},
+	}
+
+	require.True(t, RestoredPoint(nil, start).IsZero())
+	require.Equal(t, start.Add(10*time.Minute), RestoredPoint(replay, time.Time{}))
+	require.Equal(t, start.Add(5*time.Minute), RestoredPoint(replay, start.Add(5*time.Minute)))
+	// Changes after To of the last segment can be missing.
+	require.Equal(t, start.Add(10*time.Minute), RestoredPoint(replay, start.Add(30*time.Minute)))
+}
+
+func TestSegment_ContainsAfter(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s := Segment{From: start, To: start.Add(10 * time.Minute), Finished: start.Add(11 * time.Minute)}
+
+	require.False(t, s.ContainsAfter(time.Time{}))
+	require.True(t, s.ContainsAfter(start.Add(5*time.Minute)))
+	// Changes between To and Finished can be shipped to the segment too.
+	require.True(t, s.ContainsAfter(start.Add(10*time.Minute)))
+	require.False(t, s.ContainsAfter(start.Add(11*time.Minute)))
+
+	// Checkpoints saved by previous versions don't contain the finish time.
+	s.Finished = time.Time{}
+	require.False(t, s.ContainsAfter(start.Add(10*time.Minute)))
+}
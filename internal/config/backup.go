@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/shard"
 	"github.com/aerospike/backup-go"
 	"github.com/aerospike/tools-common-go/client"
 )
@@ -47,7 +48,12 @@ type BackupServiceConfig struct {
 	AwsS3        *models.AwsS3
 	GcpStorage   *models.GcpStorage
 	AzureBlob    *models.AzureBlob
+	Sftp         *models.Sftp
 	Local        *models.Local
+	// Throttle is set only from the config file.
+	Throttle *models.Throttle
+	// Jobs are set only from the config file. If set, Backup holds the common parameters of the jobs.
+	Jobs []*models.BackupJob
 }
 
 // NewBackupServiceConfig initializes and returns a BackupServiceConfig struct
@@ -65,6 +71,7 @@ func NewBackupServiceConfig(
 	awsS3 *models.AwsS3,
 	gcpStorage *models.GcpStorage,
 	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
 	local *models.Local,
 ) (*BackupServiceConfig, error) {
 	return &BackupServiceConfig{
@@ -79,6 +86,7 @@ func NewBackupServiceConfig(
 		AwsS3:        awsS3,
 		GcpStorage:   gcpStorage,
 		AzureBlob:    azureBlob,
+		Sftp:         sftp,
 		Local:        local,
 	}, nil
 }
@@ -88,12 +96,72 @@ func (p *BackupServiceConfig) IsXDR() bool {
 	return p.BackupXDR != nil && p.Backup == nil
 }
 
+// IsMultiJob determines if the backup configuration contains a list of jobs.
+func (p *BackupServiceConfig) IsMultiJob() bool {
+	return len(p.Jobs) > 0 && !p.IsXDR()
+}
+
+// ForJob returns a copy of the configuration where Backup is replaced with the job parameters.
+// Other sections are shared between jobs.
+func (p *BackupServiceConfig) ForJob(job *models.BackupJob) *BackupServiceConfig {
+	jobConfig := *p
+	jobConfig.Backup = job.Backup
+	jobConfig.Jobs = nil
+
+	return &jobConfig
+}
+
 // IsContinue determines if the backup configuration is a continue backup
 // by checking if Backup is non-nil and Continue is non-empty.
 func (p *BackupServiceConfig) IsContinue() bool {
 	return p.Backup != nil && p.Backup.Continue != ""
 }
 
+// SecretAgentConfig returns the secret agent config, that is used to load secrets before the backup is configured.
+func (p *BackupServiceConfig) SecretAgentConfig() *backup.SecretAgentConfig {
+	return newSecretAgentConfig(p.SecretAgent)
+}
+
+// IsShard checks if the backup is a shard of a distributed backup.
+func (p *BackupServiceConfig) IsShard() bool {
+	return p.Backup != nil && p.Backup.Shard != ""
+}
+
+// StoragePaths returns pointers to the backup paths, that can contain storage URLs.
+func (p *BackupServiceConfig) StoragePaths() []*string {
+	paths := make([]*string, 0, 2+2*len(p.Jobs))
+
+	if p.Backup != nil {
+		paths = append(paths, &p.Backup.Directory, &p.Backup.OutputFile)
+	}
+
+	if p.BackupXDR != nil {
+		paths = append(paths, &p.BackupXDR.Directory)
+	}
+
+	for _, job := range p.Jobs {
+		if job.Backup != nil {
+			paths = append(paths, &job.Backup.Directory, &job.Backup.OutputFile)
+		}
+	}
+
+	return paths
+}
+
+// Destination returns the output file or the directory, where the backup is written.
+func (p *BackupServiceConfig) Destination() string {
+	switch {
+	case p.Backup != nil && p.Backup.OutputFile != "":
+		return p.Backup.OutputFile
+	case p.Backup != nil:
+		return p.Backup.Directory
+	case p.BackupXDR != nil:
+		return p.BackupXDR.Directory
+	default:
+		return ""
+	}
+}
+
 // IsStopXDR checks if the backup operation should stop XDR by verifying that BackupXDR is non-nil and StopXDR is true.
 func (p *BackupServiceConfig) IsStopXDR() bool {
 	return p.BackupXDR != nil && p.BackupXDR.StopXDR
@@ -105,6 +173,12 @@ func (p *BackupServiceConfig) IsUnblockMRT() bool {
 	return p.BackupXDR != nil && p.BackupXDR.UnblockMRT
 }
 
+// IsRotateXDR checks if the backup is a continuous XDR backup,
+// by verifying that BackupXDR is non-nil and RotateInterval is set.
+func (p *BackupServiceConfig) IsRotateXDR() bool {
+	return p.BackupXDR != nil && p.BackupXDR.RotateInterval > 0
+}
+
 // SkipWriterInit checks if the backup operation should skip writer initialization
 // by verifying that Backup is non-nil and Estimate is false.
 func (p *BackupServiceConfig) SkipWriterInit() bool {
@@ -164,6 +238,28 @@ func NewBackupConfigs(serviceConfig *BackupServiceConfig, logger *slog.Logger,
 	return backupConfig, backupXDRConfig, nil
 }
 
+// applyShard sets the file prefix of the shard, so shards don't overwrite files of each other.
+// Indexes and udfs are backed up only by the first shard.
+func applyShard(c *backup.ConfigBackup, b *models.Backup) error {
+	s, shards, err := b.ParseShard()
+	if err != nil || shards == 0 {
+		return err
+	}
+
+	_, count := shard.Partitions(s, shards)
+
+	c.OutputFilePrefix += shard.FilePrefix(s, shards)
+	// A partition range can't be split to more workers than partitions.
+	c.ParallelRead = min(c.ParallelRead, count)
+
+	if s > 1 {
+		c.NoIndexes = true
+		c.NoUDFs = true
+	}
+
+	return nil
+}
+
 // newBackupConfig initializes and returns a configured instance of ConfigBackup based on the provided params.
 // This function sets various backup parameters including namespace, file limits, parallelism options, bandwidth,
 // compression, encryption, and partition filters. It returns an error if any validation or parsing fails.
@@ -188,6 +284,12 @@ func newBackupConfig(params *BackupServiceConfig) (*backup.ConfigBackup, error)
 	c.OutputFilePrefix = params.Backup.OutputFilePrefix
 	c.MetricsEnabled = true
 
+	// With a throttle schedule, limits change during the run, so they are applied by the throttle controller.
+	if !params.Throttle.IsEmpty() {
+		c.RecordsPerSecond = 0
+		c.Bandwidth = 0
+	}
+
 	// Reconfigure params for stdout or single file backup.
 	if params.IsStdout() || params.Backup.OutputFile != "" {
 		// If we back up to stdout, file limit can break the input stream because it will file headers and close descriptors.
@@ -233,6 +335,10 @@ func newBackupConfig(params *BackupServiceConfig) (*backup.ConfigBackup, error)
 
 	c.PartitionFilters = pf
 
+	if err = applyShard(c, params.Backup); err != nil {
+		return nil, err
+	}
+
 	sp, err := newScanPolicy(params.Backup)
 	if err != nil {
 		return nil, err
@@ -299,6 +405,27 @@ func newBackupXDRConfig(params *BackupServiceConfig) *backup.ConfigBackupXDR {
 	return c
 }
 
+// NewXDRScanConfig returns a config of the scan backup, that is taken with a continuous xdr backup
+// for point-in-time recovery. Records, indexes and udfs of the namespace are backed up.
+func NewXDRScanConfig(params *BackupServiceConfig) *backup.ConfigBackup {
+	parallel := runtime.NumCPU()
+	if params.BackupXDR.ParallelWrite > 0 {
+		parallel = params.BackupXDR.ParallelWrite
+	}
+
+	c := backup.NewDefaultBackupConfig()
+	c.Namespace = params.BackupXDR.Namespace
+	c.FileLimit = params.BackupXDR.FileLimit * 1024 * 1024
+	c.ParallelRead = parallel
+	c.ParallelWrite = parallel
+	c.CompressionPolicy = newCompressionPolicy(params.Compression)
+	c.EncryptionPolicy = newEncryptionPolicy(params.Encryption)
+	c.SecretAgentConfig = newSecretAgentConfig(params.SecretAgent)
+	c.MetricsEnabled = true
+
+	return c
+}
+
 func logBackupConfig(logger *slog.Logger, params *BackupServiceConfig, backupConfig *backup.ConfigBackup) {
 	logger.Info("initialized scan backup config",
 		slog.String("namespace", backupConfig.Namespace),
@@ -343,5 +470,7 @@ func logXdrBackupConfig(logger *slog.Logger, params *BackupServiceConfig, backup
 		slog.Int("result_queue_size", backupXDRConfig.ResultQueueSize),
 		slog.Int("ack_queue_size", backupXDRConfig.AckQueueSize),
 		slog.Int("max_connections", backupXDRConfig.MaxConnections),
+		slog.Duration("rotate_interval", params.BackupXDR.RotateInterval),
+		slog.Bool("scan", params.BackupXDR.Scan),
 	)
 }
@@ -42,6 +42,7 @@ func TestNewBackupServiceConfig_WithoutConfigFile(t *testing.T) {
 	awsS3 := &models.AwsS3{}
 	gcpStorage := &models.GcpStorage{}
 	azureBlob := &models.AzureBlob{}
+	sftp := &models.Sftp{}
 	local := &models.Local{}
 
 	config, err := NewBackupServiceConfig(
@@ -56,6 +57,7 @@ func TestNewBackupServiceConfig_WithoutConfigFile(t *testing.T) {
 		awsS3,
 		gcpStorage,
 		azureBlob,
+		sftp,
 		local,
 	)
 
@@ -72,6 +74,7 @@ func TestNewBackupServiceConfig_WithoutConfigFile(t *testing.T) {
 	assert.Equal(t, awsS3, config.AwsS3)
 	assert.Equal(t, gcpStorage, config.GcpStorage)
 	assert.Equal(t, azureBlob, config.AzureBlob)
+	assert.Equal(t, sftp, config.Sftp)
 }
 
 func TestBackupServiceConfig_IsXDR(t *testing.T) {
@@ -628,6 +631,42 @@ func TestNewBackupConfigs_RackList(t *testing.T) {
 	assert.Len(t, backupConfig.RackList, 3)
 }
 
+func TestNewBackupConfigs_Shard(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	newServiceConfig := func(shard string) *BackupServiceConfig {
+		return &BackupServiceConfig{
+			Backup: &models.Backup{
+				Common:           models.Common{Namespace: "test", Parallel: 8},
+				OutputFilePrefix: "daily_",
+				Shard:            shard,
+			},
+			Compression: &models.Compression{},
+			Encryption:  &models.Encryption{},
+			SecretAgent: &models.SecretAgent{},
+		}
+	}
+
+	backupConfig, _, err := NewBackupConfigs(newServiceConfig("2/3"), logger)
+	require.NoError(t, err)
+	require.Len(t, backupConfig.PartitionFilters, 1)
+	assert.Equal(t, 1365, backupConfig.PartitionFilters[0].Begin)
+	assert.Equal(t, 1365, backupConfig.PartitionFilters[0].Count)
+	assert.Equal(t, "daily_shard2of3_", backupConfig.OutputFilePrefix)
+	assert.Equal(t, 8, backupConfig.ParallelRead)
+	// Only the first shard backs up indexes and udfs.
+	assert.True(t, backupConfig.NoIndexes)
+	assert.True(t, backupConfig.NoUDFs)
+
+	backupConfig, _, err = NewBackupConfigs(newServiceConfig("1/4096"), logger)
+	require.NoError(t, err)
+	assert.Equal(t, 0, backupConfig.PartitionFilters[0].Begin)
+	assert.Equal(t, 1, backupConfig.PartitionFilters[0].Count)
+	assert.Equal(t, 1, backupConfig.ParallelRead)
+	assert.False(t, backupConfig.NoIndexes)
+}
+
 func TestNewBackupConfigs_InvalidRackList(t *testing.T) {
 	t.Parallel()
 
@@ -819,6 +858,30 @@ func TestNewBackupConfigs_XDRAllFields(t *testing.T) {
 	assert.Equal(t, backup.EncoderTypeASBX, xdrConfig.EncoderType)
 }
 
+func TestNewXDRScanConfig(t *testing.T) {
+	t.Parallel()
+
+	serviceConfig := &BackupServiceConfig{
+		BackupXDR: &models.BackupXDR{
+			Namespace:     "test-namespace",
+			ParallelWrite: 4,
+			FileLimit:     100,
+		},
+		Compression: &models.Compression{},
+		Encryption:  &models.Encryption{},
+		SecretAgent: &models.SecretAgent{},
+	}
+
+	c := NewXDRScanConfig(serviceConfig)
+
+	assert.Equal(t, "test-namespace", c.Namespace)
+	assert.Equal(t, 4, c.ParallelRead)
+	assert.Equal(t, 4, c.ParallelWrite)
+	assert.Equal(t, uint64(100*1024*1024), c.FileLimit)
+	assert.False(t, c.NoRecords)
+	assert.True(t, c.MetricsEnabled)
+}
+
 func TestNewBackupConfigs_EmptyStringLists(t *testing.T) {
 	t.Parallel()
 
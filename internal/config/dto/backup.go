@@ -15,7 +15,10 @@
 package dto
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/aerospike/aerospike-backup-cli/internal/models"
 )
@@ -37,9 +40,13 @@ type Backup struct {
 	Azure struct {
 		Blob AzureBlob `yaml:"blob"`
 	} `yaml:"azure"`
+	Sftp  Sftp `yaml:"sftp"`
 	Local struct {
 		Disk Local `yaml:"disk"`
 	} `yaml:"local"`
+	Throttle  Throttle    `yaml:"throttle"`
+	Jobs      []BackupJob `yaml:"jobs"`
+	BackupXDR BackupXDR   `yaml:"backup-xdr"`
 }
 
 // DefaultBackup returns a Backup with default values.
@@ -60,9 +67,11 @@ func DefaultBackup() *Backup {
 		Azure: struct {
 			Blob AzureBlob `yaml:"blob"`
 		}{Blob: defaultAzureBlob()},
+		Sftp: defaultSftp(),
 		Local: struct {
 			Disk Local `yaml:"disk"`
 		}{Disk: defaultLocal()},
+		BackupXDR: defaultBackupXDR(),
 	}
 }
 
@@ -116,9 +125,50 @@ func (b *Backup) ToModelBackup() *models.Backup {
 		ScanPageSize:        derefInt64(b.Backup.ScanPageSize),
 		OutputFilePrefix:    derefString(b.Backup.OutputFilePrefix),
 		RackList:            strings.Join(b.Backup.RackList, ","),
+		BackupRack:          derefString(b.Backup.BackupRack),
+		RackFallback:        derefString(b.Backup.RackFallback),
+		RackWaitTimeout:     derefInt64(b.Backup.RackWaitTimeout),
+		Shard:               derefString(b.Backup.Shard),
+		Resume:              derefBool(b.Backup.Resume),
+		NoState:             derefBool(b.Backup.NoState),
+		RetentionDays:       derefInt(b.Backup.RetentionDays),
+		RetentionMode:       derefString(b.Backup.RetentionMode),
+		LegalHold:           derefBool(b.Backup.LegalHold),
+		ParallelJobs:        derefInt(b.Backup.ParallelJobs),
+		PreflightOnly:       derefBool(b.Backup.PreflightOnly),
+		SkipPreflight:       derefBool(b.Backup.SkipPreflight),
 	}
 }
 
+// ToModelBackupJobs maps jobs to models. Each job inherits the backup section
+// and overrides the fields that are set for the job.
+func (b *Backup) ToModelBackupJobs() []*models.BackupJob {
+	if b == nil || len(b.Jobs) == 0 {
+		return nil
+	}
+
+	jobs := make([]*models.BackupJob, 0, len(b.Jobs))
+
+	for i := range b.Jobs {
+		job := &b.Jobs[i]
+		backup := b.ToModelBackup()
+
+		job.applyTo(backup)
+
+		name := derefString(job.Name)
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", backup.Namespace, i)
+		}
+
+		jobs = append(jobs, &models.BackupJob{
+			Name:   name,
+			Backup: backup,
+		})
+	}
+
+	return jobs
+}
+
 type BackupConfig struct {
 	Directory                     *string  `yaml:"directory"`
 	Namespace                     *string  `yaml:"namespace"`
@@ -156,11 +206,23 @@ type BackupConfig struct {
 	ScanPageSize                  *int64   `yaml:"scan-page-size"`
 	OutputFilePrefix              *string  `yaml:"output-file-prefix"`
 	RackList                      []string `yaml:"rack-list"`
+	BackupRack                    *string  `yaml:"backup-rack"`
+	RackFallback                  *string  `yaml:"rack-fallback"`
+	RackWaitTimeout               *int64   `yaml:"rack-wait-timeout"`
+	Shard                         *string  `yaml:"shard"`
+	Resume                        *bool    `yaml:"resume"`
+	NoState                       *bool    `yaml:"no-state"`
+	RetentionDays                 *int     `yaml:"retention-days"`
+	RetentionMode                 *string  `yaml:"retention-mode"`
+	LegalHold                     *bool    `yaml:"legal-hold"`
 	InfoTimeout                   *int64   `yaml:"info-timeout"`
 	InfoMaxRetries                *uint    `yaml:"info-max-retries"`
 	InfoRetriesMultiplier         *float64 `yaml:"info-retry-multiplier"`
 	InfoRetryIntervalMilliseconds *int64   `yaml:"info-retry-interval"`
 	StdBufferSize                 *int     `yaml:"std-buffer"`
+	ParallelJobs                  *int     `yaml:"parallel-jobs"`
+	PreflightOnly                 *bool    `yaml:"preflight-only"`
+	SkipPreflight                 *bool    `yaml:"skip-preflight"`
 }
 
 func defaultBackupConfig() BackupConfig {
@@ -204,7 +266,197 @@ func defaultBackupConfig() BackupConfig {
 		ScanPageSize:                  int64Ptr(models.DefaultBackupScanPageSize),
 		OutputFilePrefix:              stringPtr(models.DefaultBackupOutputFilePrefix),
 		RackList:                      []string{},
+		BackupRack:                    stringPtr(models.DefaultBackupBackupRack),
+		RackFallback:                  stringPtr(models.DefaultBackupRackFallback),
+		RackWaitTimeout:               int64Ptr(models.DefaultBackupRackWaitTimeout),
+		Shard:                         stringPtr(models.DefaultBackupShard),
+		Resume:                        boolPtr(models.DefaultBackupResume),
+		NoState:                       boolPtr(models.DefaultBackupNoState),
+		RetentionDays:                 intPtr(models.DefaultBackupRetentionDays),
+		RetentionMode:                 stringPtr(models.DefaultBackupRetentionMode),
+		LegalHold:                     boolPtr(models.DefaultBackupLegalHold),
 		TotalTimeout:                  int64Ptr(models.DefaultBackupTotalTimeout),
 		Parallel:                      intPtr(models.DefaultBackupParallel),
+		ParallelJobs:                  intPtr(models.DefaultBackupParallelJobs),
+		PreflightOnly:                 boolPtr(models.DefaultBackupPreflightOnly),
+		SkipPreflight:                 boolPtr(models.DefaultBackupSkipPreflight),
+	}
+}
+
+// BackupJob is used to map a single job of a multi-job yaml config.
+// Fields that are not set are taken from the backup section.
+type BackupJob struct {
+	Name             *string  `yaml:"name"`
+	Namespace        *string  `yaml:"namespace"`
+	Directory        *string  `yaml:"directory"`
+	SetList          []string `yaml:"set-list"`
+	BinList          []string `yaml:"bin-list"`
+	ModifiedBefore   *string  `yaml:"modified-before"`
+	ModifiedAfter    *string  `yaml:"modified-after"`
+	AfterDigest      *string  `yaml:"after-digest"`
+	FilterExpression *string  `yaml:"filter-exp"`
+	NodeList         []string `yaml:"node-list"`
+	NoTTLOnly        *bool    `yaml:"no-ttl-only"`
+	PartitionList    []string `yaml:"partition-list"`
+	RackList         []string `yaml:"rack-list"`
+	OutputFilePrefix *string  `yaml:"output-file-prefix"`
+}
+
+func (j *BackupJob) applyTo(b *models.Backup) {
+	if j.Namespace != nil {
+		b.Namespace = *j.Namespace
+	}
+
+	if j.Directory != nil {
+		b.Directory = *j.Directory
+	}
+
+	if j.SetList != nil {
+		b.SetList = strings.Join(j.SetList, ",")
+	}
+
+	if j.BinList != nil {
+		b.BinList = strings.Join(j.BinList, ",")
+	}
+
+	if j.ModifiedBefore != nil {
+		b.ModifiedBefore = *j.ModifiedBefore
+	}
+
+	if j.ModifiedAfter != nil {
+		b.ModifiedAfter = *j.ModifiedAfter
+	}
+
+	if j.AfterDigest != nil {
+		b.AfterDigest = *j.AfterDigest
+	}
+
+	if j.FilterExpression != nil {
+		b.FilterExpression = *j.FilterExpression
+	}
+
+	if j.NodeList != nil {
+		b.NodeList = strings.Join(j.NodeList, ",")
+	}
+
+	if j.NoTTLOnly != nil {
+		b.NoTTLOnly = *j.NoTTLOnly
+	}
+
+	if j.PartitionList != nil {
+		b.PartitionList = strings.Join(j.PartitionList, ",")
+	}
+
+	if j.RackList != nil {
+		b.RackList = strings.Join(j.RackList, ",")
+	}
+
+	if j.OutputFilePrefix != nil {
+		b.OutputFilePrefix = *j.OutputFilePrefix
+	}
+}
+
+// BackupXDR is used to map the backup-xdr section, that is used by the xdr command.
+type BackupXDR struct {
+	Directory                     *string  `yaml:"directory"`
+	Namespace                     *string  `yaml:"namespace"`
+	FileLimit                     *uint64  `yaml:"file-limit"`
+	RemoveFiles                   *bool    `yaml:"remove-files"`
+	ParallelWrite                 *int     `yaml:"parallel-write"`
+	DC                            *string  `yaml:"dc"`
+	LocalAddress                  *string  `yaml:"local-address"`
+	LocalPort                     *int     `yaml:"local-port"`
+	Rewind                        *string  `yaml:"rewind"`
+	MaxThroughput                 *int     `yaml:"max-throughput"`
+	ReadTimeoutMilliseconds       *int64   `yaml:"read-timeout"`
+	WriteTimeoutMilliseconds      *int64   `yaml:"write-timeout"`
+	ResultQueueSize               *int     `yaml:"results-queue-size"`
+	AckQueueSize                  *int     `yaml:"ack-queue-size"`
+	MaxConnections                *int     `yaml:"max-connections"`
+	InfoPolingPeriodMilliseconds  *int64   `yaml:"info-poling-period"`
+	StartTimeoutMilliseconds      *int64   `yaml:"start-timeout"`
+	InfoTimeout                   *int64   `yaml:"info-timeout"`
+	InfoMaxRetries                *uint    `yaml:"info-max-retries"`
+	InfoRetriesMultiplier         *float64 `yaml:"info-retry-multiplier"`
+	InfoRetryIntervalMilliseconds *int64   `yaml:"info-retry-interval"`
+	Forward                       *bool    `yaml:"forward"`
+	StopXDR                       *bool    `yaml:"stop-xdr"`
+	UnblockMRT                    *bool    `yaml:"unblock-mrt"`
+	// RotateInterval is set as a duration string, e.g. 15m.
+	RotateInterval *time.Duration `yaml:"rotate-interval"`
+	Scan           *bool          `yaml:"scan"`
+	// CleanupStateFile is a local file with changes made on the cluster, to undo them after a crash.
+	CleanupStateFile *string `yaml:"cleanup-state-file"`
+}
+
+func defaultBackupXDR() BackupXDR {
+	return BackupXDR{
+		Directory:                     stringPtr(models.DefaultBackupXDRDirectory),
+		Namespace:                     stringPtr(models.DefaultBackupXDRNamespace),
+		FileLimit:                     uint64Ptr(models.DefaultBackupXDRFileLimit),
+		RemoveFiles:                   boolPtr(models.DefaultBackupXDRRemoveFiles),
+		ParallelWrite:                 intPtr(models.DefaultBackupXDRParallelWrite),
+		DC:                            stringPtr(models.DefaultBackupXDRDC),
+		LocalAddress:                  stringPtr(models.DefaultBackupXDRLocalAddress),
+		LocalPort:                     intPtr(models.DefaultBackupXDRLocalPort),
+		Rewind:                        stringPtr(models.DefaultBackupXDRRewind),
+		MaxThroughput:                 intPtr(models.DefaultBackupXDRMaxThroughput),
+		ReadTimeoutMilliseconds:       int64Ptr(models.DefaultBackupXDRReadTimeout),
+		WriteTimeoutMilliseconds:      int64Ptr(models.DefaultBackupXDRWriteTimeout),
+		ResultQueueSize:               intPtr(models.DefaultBackupXDRResultQueueSize),
+		AckQueueSize:                  intPtr(models.DefaultBackupXDRAckQueueSize),
+		MaxConnections:                intPtr(models.DefaultBackupXDRMaxConnections),
+		InfoPolingPeriodMilliseconds:  int64Ptr(models.DefaultBackupXDRInfoPolingPeriod),
+		StartTimeoutMilliseconds:      int64Ptr(models.DefaultBackupXDRStartTimeout),
+		InfoTimeout:                   int64Ptr(models.DefaultBackupXDRInfoTimeout),
+		InfoMaxRetries:                uintPtr(models.DefaultBackupXDRInfoMaxRetries),
+		InfoRetriesMultiplier:         float64Ptr(models.DefaultBackupXDRInfoRetriesMultiplier),
+		InfoRetryIntervalMilliseconds: int64Ptr(models.DefaultBackupXDRInfoRetryInterval),
+		Forward:                       boolPtr(models.DefaultBackupXDRForward),
+		StopXDR:                       boolPtr(models.DefaultBackupXDRStopXDR),
+		UnblockMRT:                    boolPtr(models.DefaultBackupXDRUnblockMRT),
+		RotateInterval:                durationPtr(models.DefaultBackupXDRRotateInterval),
+		Scan:                          boolPtr(models.DefaultBackupXDRScan),
+		CleanupStateFile:              stringPtr(models.DefaultBackupXDRCleanupStateFile),
+	}
+}
+
+// ToModelBackupXDR maps the backup-xdr section to the model.
+// Returns nil if the section is not set, so it has only default values.
+func (b *Backup) ToModelBackupXDR() *models.BackupXDR {
+	if b == nil || reflect.DeepEqual(b.BackupXDR, defaultBackupXDR()) {
+		return nil
+	}
+
+	x := &b.BackupXDR
+
+	return &models.BackupXDR{
+		Directory:                     derefString(x.Directory),
+		Namespace:                     derefString(x.Namespace),
+		FileLimit:                     derefUint64(x.FileLimit),
+		RemoveFiles:                   derefBool(x.RemoveFiles),
+		ParallelWrite:                 derefInt(x.ParallelWrite),
+		DC:                            derefString(x.DC),
+		LocalAddress:                  derefString(x.LocalAddress),
+		LocalPort:                     derefInt(x.LocalPort),
+		Rewind:                        derefString(x.Rewind),
+		MaxThroughput:                 derefInt(x.MaxThroughput),
+		ReadTimeoutMilliseconds:       derefInt64(x.ReadTimeoutMilliseconds),
+		WriteTimeoutMilliseconds:      derefInt64(x.WriteTimeoutMilliseconds),
+		ResultQueueSize:               derefInt(x.ResultQueueSize),
+		AckQueueSize:                  derefInt(x.AckQueueSize),
+		MaxConnections:                derefInt(x.MaxConnections),
+		InfoPolingPeriodMilliseconds:  derefInt64(x.InfoPolingPeriodMilliseconds),
+		StartTimeoutMilliseconds:      derefInt64(x.StartTimeoutMilliseconds),
+		InfoTimeout:                   derefInt64(x.InfoTimeout),
+		InfoMaxRetries:                derefUint(x.InfoMaxRetries),
+		InfoRetriesMultiplier:         derefFloat64(x.InfoRetriesMultiplier),
+		InfoRetryIntervalMilliseconds: derefInt64(x.InfoRetryIntervalMilliseconds),
+		Forward:                       derefBool(x.Forward),
+		StopXDR:                       derefBool(x.StopXDR),
+		UnblockMRT:                    derefBool(x.UnblockMRT),
+		RotateInterval:                derefDuration(x.RotateInterval),
+		Scan:                          derefBool(x.Scan),
+		CleanupStateFile:              derefString(x.CleanupStateFile),
 	}
 }
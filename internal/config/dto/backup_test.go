@@ -46,6 +46,7 @@ func TestDefaultBackupConfig(t *testing.T) {
 	assert.Empty(t, config.SetList)
 	assert.Empty(t, config.BinList)
 	assert.Equal(t, models.DefaultBackupParallel, derefInt(config.Parallel))
+	assert.Equal(t, models.DefaultBackupParallelJobs, derefInt(config.ParallelJobs))
 	assert.Equal(t, models.DefaultCommonNoRecords, derefBool(config.NoRecords))
 	assert.Equal(t, models.DefaultCommonNoIndexes, derefBool(config.NoIndexes))
 	assert.Equal(t, models.DefaultCommonNoUDFs, derefBool(config.NoUDFs))
@@ -79,6 +80,11 @@ func TestDefaultBackupConfig(t *testing.T) {
 	assert.Equal(t, models.DefaultBackupStateFileDst, derefString(config.StateFileDst))
 	assert.Equal(t, models.DefaultBackupContinue, derefString(config.Continue))
 	assert.Equal(t, int64(models.DefaultBackupScanPageSize), derefInt64(config.ScanPageSize))
+	assert.Equal(t, models.DefaultBackupResume, derefBool(config.Resume))
+	assert.Equal(t, models.DefaultBackupNoState, derefBool(config.NoState))
+	assert.Equal(t, models.DefaultBackupRetentionMode, derefString(config.RetentionMode))
+	assert.Equal(t, models.DefaultBackupPreflightOnly, derefBool(config.PreflightOnly))
+	assert.Equal(t, models.DefaultBackupSkipPreflight, derefBool(config.SkipPreflight))
 	assert.Equal(t, models.DefaultBackupOutputFilePrefix, derefString(config.OutputFilePrefix))
 	assert.Empty(t, config.RackList)
 	assert.Equal(t, int64(models.DefaultBackupTotalTimeout), derefInt64(config.TotalTimeout))
@@ -127,6 +133,15 @@ func TestBackupConfig_ToModelBackup(t *testing.T) {
 		ScanPageSize:                  int64Ptr(2500),
 		OutputFilePrefix:              stringPtr("prefix-"),
 		RackList:                      []string{"rack-a"},
+		BackupRack:                    stringPtr("2"),
+		RackFallback:                  stringPtr("other-rack"),
+		RackWaitTimeout:               int64Ptr(1000),
+		Shard:                         stringPtr("2/3"),
+		Resume:                        boolPtr(true),
+		RetentionDays:                 intPtr(30),
+		RetentionMode:                 stringPtr("compliance"),
+		LegalHold:                     boolPtr(true),
+		SkipPreflight:                 boolPtr(true),
 	}
 
 	backup := &Backup{Backup: config}
@@ -175,6 +190,17 @@ func TestBackupConfig_ToModelBackup(t *testing.T) {
 	assert.Equal(t, int64(2500), model.ScanPageSize)
 	assert.Equal(t, "prefix-", model.OutputFilePrefix)
 	assert.Equal(t, "rack-a", model.RackList)
+	assert.Equal(t, "2", model.BackupRack)
+	assert.Equal(t, "other-rack", model.RackFallback)
+	assert.Equal(t, int64(1000), model.RackWaitTimeout)
+	assert.Equal(t, "2/3", model.Shard)
+	assert.True(t, model.Resume)
+	assert.False(t, model.NoState)
+	assert.Equal(t, 30, model.RetentionDays)
+	assert.Equal(t, "compliance", model.RetentionMode)
+	assert.True(t, model.LegalHold)
+	assert.False(t, model.PreflightOnly)
+	assert.True(t, model.SkipPreflight)
 }
 
 func TestBackup_ToModelBackup_NilHandling(t *testing.T) {
@@ -248,3 +274,45 @@ func TestBackup_ToModelBackup_DefaultToModel(t *testing.T) {
 	assert.Equal(t, int64(models.DefaultBackupScanPageSize), model.ScanPageSize)
 	assert.Equal(t, models.DefaultBackupOutputFilePrefix, model.OutputFilePrefix)
 }
+
+func TestBackupToModelBackupJobs(t *testing.T) {
+	b := DefaultBackup()
+	b.Backup.Namespace = stringPtr("test")
+	b.Backup.Directory = stringPtr("base")
+	b.Backup.SetList = []string{"set1"}
+	b.Backup.Parallel = intPtr(4)
+	b.Backup.ParallelJobs = intPtr(2)
+	b.Jobs = []BackupJob{
+		{
+			Name:      stringPtr("users"),
+			Directory: stringPtr("users"),
+			SetList:   []string{"users", "profiles"},
+		},
+		{
+			Namespace: stringPtr("events"),
+			Directory: stringPtr("events"),
+		},
+	}
+
+	jobs := b.ToModelBackupJobs()
+	require.Len(t, jobs, 2)
+
+	assert.Equal(t, "users", jobs[0].Name)
+	assert.Equal(t, "test", jobs[0].Backup.Namespace)
+	assert.Equal(t, "users", jobs[0].Backup.Directory)
+	assert.Equal(t, "users,profiles", jobs[0].Backup.SetList)
+	assert.Equal(t, 4, jobs[0].Backup.Parallel)
+
+	// Name defaults to namespace and job index.
+	assert.Equal(t, "events-1", jobs[1].Name)
+	assert.Equal(t, "events", jobs[1].Backup.Namespace)
+	assert.Equal(t, "events", jobs[1].Backup.Directory)
+	assert.Equal(t, "set1", jobs[1].Backup.SetList)
+	assert.Equal(t, 2, jobs[1].Backup.ParallelJobs)
+
+	// Jobs must not share the model.
+	assert.NotSame(t, jobs[0].Backup, jobs[1].Backup)
+
+	b.Jobs = nil
+	assert.Nil(t, b.ToModelBackupJobs())
+}
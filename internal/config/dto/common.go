@@ -17,6 +17,7 @@ package dto
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aerospike/aerospike-backup-cli/internal/models"
 	"github.com/aerospike/tools-common-go/client"
@@ -372,6 +373,14 @@ type AwsS3 struct {
 	EndpointOverride     *string  `yaml:"endpoint-override"`
 	AccessKeyID          *string  `yaml:"access-key-id"`
 	SecretAccessKey      *string  `yaml:"secret-access-key"`
+	AddressingStyle      *string  `yaml:"addressing-style"`
+	RoleArn              *string  `yaml:"role-arn"`
+	ExternalID           *string  `yaml:"external-id"`
+	SessionName          *string  `yaml:"session-name"`
+	WebIdentityTokenFile *string  `yaml:"web-identity-token-file"`
+	SSE                  *string  `yaml:"sse"`
+	SSEKmsKeyID          *string  `yaml:"sse-kms-key-id"`
+	SSECustomerKey       *string  `yaml:"sse-customer-key"`
 	RestorePollDuration  *int64   `yaml:"restore-poll-duration"`
 	StorageClass         *string  `yaml:"storage-class"`
 	AccessTier           *string  `yaml:"tier"`
@@ -385,6 +394,10 @@ type AwsS3 struct {
 	RetryReadMaxAttempts *uint    `yaml:"retry-read-max-attempts"`
 	MaxConnsPerHost      *int     `yaml:"max-conns-per-host"`
 	RequestTimeout       *int     `yaml:"request-timeout"`
+	TLSCAFile            *string  `yaml:"tls-cafile"`
+	TLSCertFile          *string  `yaml:"tls-certfile"`
+	TLSKeyFile           *string  `yaml:"tls-keyfile"`
+	ProxyURL             *string  `yaml:"proxy-url"`
 }
 
 func defaultAwsS3() AwsS3 {
@@ -395,6 +408,14 @@ func defaultAwsS3() AwsS3 {
 		EndpointOverride:     stringPtr(models.DefaultS3Endpoint),
 		AccessKeyID:          stringPtr(models.DefaultS3AccessKeyID),
 		SecretAccessKey:      stringPtr(models.DefaultS3SecretAccessKey),
+		AddressingStyle:      stringPtr(models.DefaultS3AddressingStyle),
+		RoleArn:              stringPtr(models.DefaultS3RoleArn),
+		ExternalID:           stringPtr(models.DefaultS3ExternalID),
+		SessionName:          stringPtr(models.DefaultS3SessionName),
+		WebIdentityTokenFile: stringPtr(models.DefaultS3WebIdentityToken),
+		SSE:                  stringPtr(models.DefaultS3SSE),
+		SSEKmsKeyID:          stringPtr(models.DefaultS3SSEKmsKeyID),
+		SSECustomerKey:       stringPtr(models.DefaultS3SSECustomerKey),
 		RestorePollDuration:  int64Ptr(models.DefaultS3RestorePollDuration),
 		StorageClass:         stringPtr(models.DefaultS3StorageClass),
 		AccessTier:           stringPtr(models.DefaultS3AccessTier),
@@ -408,6 +429,10 @@ func defaultAwsS3() AwsS3 {
 		RetryReadMaxAttempts: uintPtr(models.DefaultCloudRetryReadMaxAttempts),
 		MaxConnsPerHost:      intPtr(models.DefaultCloudMaxConnsPerHost),
 		RequestTimeout:       intPtr(models.DefaultCloudRequestTimeout),
+		TLSCAFile:            stringPtr(models.DefaultCloudTLSCAFile),
+		TLSCertFile:          stringPtr(models.DefaultCloudTLSCertFile),
+		TLSKeyFile:           stringPtr(models.DefaultCloudTLSKeyFile),
+		ProxyURL:             stringPtr(models.DefaultCloudProxyURL),
 	}
 }
 
@@ -417,19 +442,27 @@ func (a *AwsS3) ToModelAwsS3() *models.AwsS3 {
 	}
 
 	return &models.AwsS3{
-		BucketName:          derefString(a.BucketName),
-		Region:              derefString(a.Region),
-		Profile:             derefString(a.Profile),
-		Endpoint:            derefString(a.EndpointOverride),
-		AccessKeyID:         derefString(a.AccessKeyID),
-		SecretAccessKey:     derefString(a.SecretAccessKey),
-		StorageClass:        derefString(a.StorageClass),
-		AccessTier:          derefString(a.AccessTier),
-		RetryMaxAttempts:    derefInt(a.RetryMaxAttempts),
-		RetryMaxBackoff:     derefInt(a.RetryMaxBackoff),
-		ChunkSize:           derefInt(a.ChunkSize),
-		UploadConcurrency:   derefInt(a.UploadConcurrency),
-		RestorePollDuration: derefInt64(a.RestorePollDuration),
+		BucketName:           derefString(a.BucketName),
+		Region:               derefString(a.Region),
+		Profile:              derefString(a.Profile),
+		Endpoint:             derefString(a.EndpointOverride),
+		AccessKeyID:          derefString(a.AccessKeyID),
+		SecretAccessKey:      derefString(a.SecretAccessKey),
+		AddressingStyle:      derefString(a.AddressingStyle),
+		RoleArn:              derefString(a.RoleArn),
+		ExternalID:           derefString(a.ExternalID),
+		SessionName:          derefString(a.SessionName),
+		WebIdentityTokenFile: derefString(a.WebIdentityTokenFile),
+		SSE:                  derefString(a.SSE),
+		SSEKmsKeyID:          derefString(a.SSEKmsKeyID),
+		SSECustomerKey:       derefString(a.SSECustomerKey),
+		StorageClass:         derefString(a.StorageClass),
+		AccessTier:           derefString(a.AccessTier),
+		RetryMaxAttempts:     derefInt(a.RetryMaxAttempts),
+		RetryMaxBackoff:      derefInt(a.RetryMaxBackoff),
+		ChunkSize:            derefInt(a.ChunkSize),
+		UploadConcurrency:    derefInt(a.UploadConcurrency),
+		RestorePollDuration:  derefInt64(a.RestorePollDuration),
 		StorageCommon: models.StorageCommon{
 			CalculateChecksum:    derefBool(a.CalculateChecksum),
 			RetryReadBackoff:     derefInt(a.RetryReadBackoff),
@@ -437,12 +470,17 @@ func (a *AwsS3) ToModelAwsS3() *models.AwsS3 {
 			RetryReadMaxAttempts: derefUint(a.RetryReadMaxAttempts),
 			MaxConnsPerHost:      derefInt(a.MaxConnsPerHost),
 			RequestTimeout:       derefInt(a.RequestTimeout),
+			TLSCAFile:            derefString(a.TLSCAFile),
+			TLSCertFile:          derefString(a.TLSCertFile),
+			TLSKeyFile:           derefString(a.TLSKeyFile),
+			ProxyURL:             derefString(a.ProxyURL),
 		},
 	}
 }
 
 type GcpStorage struct {
 	KeyFile                *string  `yaml:"key-path"`
+	ImpersonateAccount     *string  `yaml:"impersonate-service-account"`
 	BucketName             *string  `yaml:"bucket-name"`
 	EndpointOverride       *string  `yaml:"endpoint-override"`
 	RetryMaxAttempts       *int     `yaml:"retry-max-attempts"`
@@ -456,11 +494,16 @@ type GcpStorage struct {
 	RetryReadMaxAttempts   *uint    `yaml:"retry-read-max-attempts"`
 	MaxConnsPerHost        *int     `yaml:"max-conns-per-host"`
 	RequestTimeout         *int     `yaml:"request-timeout"`
+	TLSCAFile              *string  `yaml:"tls-cafile"`
+	TLSCertFile            *string  `yaml:"tls-certfile"`
+	TLSKeyFile             *string  `yaml:"tls-keyfile"`
+	ProxyURL               *string  `yaml:"proxy-url"`
 }
 
 func defaultGcpStorage() GcpStorage {
 	return GcpStorage{
 		KeyFile:                stringPtr(models.DefaultGcpKeyFile),
+		ImpersonateAccount:     stringPtr(models.DefaultGcpImpersonateAccount),
 		BucketName:             stringPtr(models.DefaultGcpBucketName),
 		EndpointOverride:       stringPtr(models.DefaultGcpEndpoint),
 		RetryMaxAttempts:       intPtr(models.DefaultGcpRetryMaxAttempts),
@@ -474,6 +517,10 @@ func defaultGcpStorage() GcpStorage {
 		RetryReadMaxAttempts:   uintPtr(models.DefaultCloudRetryReadMaxAttempts),
 		MaxConnsPerHost:        intPtr(models.DefaultCloudMaxConnsPerHost),
 		RequestTimeout:         intPtr(models.DefaultCloudRequestTimeout),
+		TLSCAFile:              stringPtr(models.DefaultCloudTLSCAFile),
+		TLSCertFile:            stringPtr(models.DefaultCloudTLSCertFile),
+		TLSKeyFile:             stringPtr(models.DefaultCloudTLSKeyFile),
+		ProxyURL:               stringPtr(models.DefaultCloudProxyURL),
 	}
 }
 
@@ -483,14 +530,15 @@ func (g *GcpStorage) ToModelGcpStorage() *models.GcpStorage {
 	}
 
 	return &models.GcpStorage{
-		KeyFile:                derefString(g.KeyFile),
-		BucketName:             derefString(g.BucketName),
-		Endpoint:               derefString(g.EndpointOverride),
-		RetryMaxAttempts:       derefInt(g.RetryMaxAttempts),
-		RetryBackoffMax:        derefInt(g.RetryMaxBackoff),
-		RetryBackoffInit:       derefInt(g.RetryInitBackoff),
-		RetryBackoffMultiplier: derefFloat64(g.RetryBackoffMultiplier),
-		ChunkSize:              derefInt(g.ChunkSize),
+		KeyFile:                   derefString(g.KeyFile),
+		ImpersonateServiceAccount: derefString(g.ImpersonateAccount),
+		BucketName:                derefString(g.BucketName),
+		Endpoint:                  derefString(g.EndpointOverride),
+		RetryMaxAttempts:          derefInt(g.RetryMaxAttempts),
+		RetryBackoffMax:           derefInt(g.RetryMaxBackoff),
+		RetryBackoffInit:          derefInt(g.RetryInitBackoff),
+		RetryBackoffMultiplier:    derefFloat64(g.RetryBackoffMultiplier),
+		ChunkSize:                 derefInt(g.ChunkSize),
 		StorageCommon: models.StorageCommon{
 			CalculateChecksum:    derefBool(g.CalculateChecksum),
 			RetryReadBackoff:     derefInt(g.RetryReadBackoff),
@@ -498,6 +546,10 @@ func (g *GcpStorage) ToModelGcpStorage() *models.GcpStorage {
 			RetryReadMaxAttempts: derefUint(g.RetryReadMaxAttempts),
 			MaxConnsPerHost:      derefInt(g.MaxConnsPerHost),
 			RequestTimeout:       derefInt(g.RequestTimeout),
+			TLSCAFile:            derefString(g.TLSCAFile),
+			TLSCertFile:          derefString(g.TLSCertFile),
+			TLSKeyFile:           derefString(g.TLSKeyFile),
+			ProxyURL:             derefString(g.ProxyURL),
 		},
 	}
 }
@@ -508,6 +560,9 @@ type AzureBlob struct {
 	TenantID             *string  `yaml:"tenant-id"`
 	ClientID             *string  `yaml:"client-id"`
 	ClientSecret         *string  `yaml:"client-secret"`
+	SASToken             *string  `yaml:"sas-token"`
+	FederatedTokenFile   *string  `yaml:"federated-token-file"`
+	AuthMode             *string  `yaml:"auth-mode"`
 	EndpointOverride     *string  `yaml:"endpoint"`
 	ContainerName        *string  `yaml:"container-name"`
 	AccessTier           *string  `yaml:"access-tier"`
@@ -522,6 +577,10 @@ type AzureBlob struct {
 	RetryReadMaxAttempts *uint    `yaml:"retry-read-max-attempts"`
 	MaxConnsPerHost      *int     `yaml:"max-conns-per-host"`
 	RequestTimeout       *int     `yaml:"request-timeout"`
+	TLSCAFile            *string  `yaml:"tls-cafile"`
+	TLSCertFile          *string  `yaml:"tls-certfile"`
+	TLSKeyFile           *string  `yaml:"tls-keyfile"`
+	ProxyURL             *string  `yaml:"proxy-url"`
 	BlockSize            *int     `yaml:"block-size"`
 }
 
@@ -532,6 +591,9 @@ func defaultAzureBlob() AzureBlob {
 		TenantID:             stringPtr(models.DefaultAzureTenantID),
 		ClientID:             stringPtr(models.DefaultAzureClientID),
 		ClientSecret:         stringPtr(models.DefaultAzureClientSecret),
+		SASToken:             stringPtr(models.DefaultAzureSASToken),
+		FederatedTokenFile:   stringPtr(models.DefaultAzureFederatedTokenFile),
+		AuthMode:             stringPtr(models.DefaultAzureAuthMode),
 		EndpointOverride:     stringPtr(models.DefaultAzureEndpoint),
 		ContainerName:        stringPtr(models.DefaultAzureContainerName),
 		AccessTier:           stringPtr(models.DefaultAzureAccessTier),
@@ -547,6 +609,10 @@ func defaultAzureBlob() AzureBlob {
 		RetryReadMaxAttempts: uintPtr(models.DefaultCloudRetryReadMaxAttempts),
 		MaxConnsPerHost:      intPtr(models.DefaultCloudMaxConnsPerHost),
 		RequestTimeout:       intPtr(models.DefaultCloudRequestTimeout),
+		TLSCAFile:            stringPtr(models.DefaultCloudTLSCAFile),
+		TLSCertFile:          stringPtr(models.DefaultCloudTLSCertFile),
+		TLSKeyFile:           stringPtr(models.DefaultCloudTLSKeyFile),
+		ProxyURL:             stringPtr(models.DefaultCloudProxyURL),
 	}
 }
 
@@ -561,6 +627,9 @@ func (a *AzureBlob) ToModelAzureBlob() *models.AzureBlob {
 		TenantID:            derefString(a.TenantID),
 		ClientID:            derefString(a.ClientID),
 		ClientSecret:        derefString(a.ClientSecret),
+		SASToken:            derefString(a.SASToken),
+		FederatedTokenFile:  derefString(a.FederatedTokenFile),
+		AuthMode:            derefString(a.AuthMode),
 		Endpoint:            derefString(a.EndpointOverride),
 		ContainerName:       derefString(a.ContainerName),
 		AccessTier:          derefString(a.AccessTier),
@@ -577,17 +646,74 @@ func (a *AzureBlob) ToModelAzureBlob() *models.AzureBlob {
 			RetryReadMaxAttempts: derefUint(a.RetryReadMaxAttempts),
 			MaxConnsPerHost:      derefInt(a.MaxConnsPerHost),
 			RequestTimeout:       derefInt(a.RequestTimeout),
+			TLSCAFile:            derefString(a.TLSCAFile),
+			TLSCertFile:          derefString(a.TLSCertFile),
+			TLSKeyFile:           derefString(a.TLSKeyFile),
+			ProxyURL:             derefString(a.ProxyURL),
 		},
 	}
 }
 
+type Sftp struct {
+	Host           *string `yaml:"host"`
+	User           *string `yaml:"user"`
+	Password       *string `yaml:"password"`
+	KeyFile        *string `yaml:"key-file"`
+	KeyPassphrase  *string `yaml:"key-passphrase"`
+	UseAgent       *bool   `yaml:"use-agent"`
+	KnownHostsFile *string `yaml:"known-hosts-file"`
+	Connections    *int    `yaml:"connections"`
+	ConnectTimeout *int    `yaml:"connect-timeout"`
+	BufferSize     *int    `yaml:"buffer-size"`
+}
+
+func defaultSftp() Sftp {
+	return Sftp{
+		Host:           stringPtr(models.DefaultSftpHost),
+		User:           stringPtr(models.DefaultSftpUser),
+		Password:       stringPtr(models.DefaultSftpPassword),
+		KeyFile:        stringPtr(models.DefaultSftpKeyFile),
+		KeyPassphrase:  stringPtr(models.DefaultSftpKeyPassphrase),
+		UseAgent:       boolPtr(models.DefaultSftpUseAgent),
+		KnownHostsFile: stringPtr(models.DefaultSftpKnownHostsFile),
+		Connections:    intPtr(models.DefaultSftpConnections),
+		ConnectTimeout: intPtr(models.DefaultSftpConnectTimeout),
+		BufferSize:     intPtr(models.DefaultSftpBufferSize),
+	}
+}
+
+func (s *Sftp) ToModelSftp() *models.Sftp {
+	if s == nil {
+		return nil
+	}
+
+	return &models.Sftp{
+		Host:           derefString(s.Host),
+		User:           derefString(s.User),
+		Password:       derefString(s.Password),
+		KeyFile:        derefString(s.KeyFile),
+		KeyPassphrase:  derefString(s.KeyPassphrase),
+		UseAgent:       derefBool(s.UseAgent),
+		KnownHostsFile: derefString(s.KnownHostsFile),
+		Connections:    derefInt(s.Connections),
+		ConnectTimeout: derefInt(s.ConnectTimeout),
+		BufferSize:     derefInt(s.BufferSize),
+	}
+}
+
 type Local struct {
-	BufferSize int `yaml:"buffer-size"`
+	BufferSize      int  `yaml:"buffer-size"`
+	ReadAhead       int  `yaml:"read-ahead"`
+	DropCache       bool `yaml:"drop-cache"`
+	OpenConcurrency int  `yaml:"open-concurrency"`
 }
 
 func defaultLocal() Local {
 	return Local{
-		BufferSize: models.DefaultLocalBufferSize,
+		BufferSize:      models.DefaultLocalBufferSize,
+		ReadAhead:       models.DefaultLocalReadAhead,
+		DropCache:       models.DefaultLocalDropCache,
+		OpenConcurrency: models.DefaultLocalOpenConcurrency,
 	}
 }
 
@@ -597,7 +723,81 @@ func (l *Local) ToModelLocal() *models.Local {
 	}
 
 	return &models.Local{
-		BufferSize: l.BufferSize,
+		BufferSize:      l.BufferSize,
+		ReadAhead:       l.ReadAhead,
+		DropCache:       l.DropCache,
+		OpenConcurrency: l.OpenConcurrency,
+	}
+}
+
+// Throttle is used to map time-window rate limit schedules.
+// Keys are daily windows in the HH:MM-HH:MM format, values are limits.
+type Throttle struct {
+	RecordsPerSecond map[string]int64  `yaml:"rps"`
+	Bandwidth        map[string]int64  `yaml:"bandwidth"`
+	Adaptive         *AdaptiveThrottle `yaml:"adaptive"`
+}
+
+// AdaptiveThrottle is used to map cluster load thresholds.
+// Intervals and latency threshold are in milliseconds.
+type AdaptiveThrottle struct {
+	CheckInterval      *int64   `yaml:"check-interval"`
+	LatencyThreshold   *int64   `yaml:"latency-threshold"`
+	MaxLatencyPct      *float64 `yaml:"max-latency-pct"`
+	MaxCPUPct          *int64   `yaml:"max-cpu-pct"`
+	MaxErrorsPerSecond *int64   `yaml:"max-errors-per-second"`
+	MinRatePct         *int64   `yaml:"min-rate-pct"`
+}
+
+func (t *Throttle) ToModelThrottle() (*models.Throttle, error) {
+	if t == nil || (len(t.RecordsPerSecond) == 0 && len(t.Bandwidth) == 0 && t.Adaptive == nil) {
+		return nil, nil
+	}
+
+	rps, err := models.ParseThrottleWindows(t.RecordsPerSecond)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rps schedule: %w", err)
+	}
+
+	bandwidth, err := models.ParseThrottleWindows(t.Bandwidth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bandwidth schedule: %w", err)
+	}
+
+	return &models.Throttle{
+		RecordsPerSecond: rps,
+		Bandwidth:        bandwidth,
+		Adaptive:         t.Adaptive.ToModelAdaptiveThrottle(),
+	}, nil
+}
+
+func (a *AdaptiveThrottle) ToModelAdaptiveThrottle() *models.AdaptiveThrottle {
+	if a == nil {
+		return nil
+	}
+
+	checkInterval := models.DefaultAdaptiveCheckInterval
+	if a.CheckInterval != nil {
+		checkInterval = *a.CheckInterval
+	}
+
+	latencyThreshold := models.DefaultAdaptiveLatencyThreshold
+	if a.LatencyThreshold != nil {
+		latencyThreshold = *a.LatencyThreshold
+	}
+
+	minRatePct := models.DefaultAdaptiveMinRatePct
+	if a.MinRatePct != nil {
+		minRatePct = *a.MinRatePct
+	}
+
+	return &models.AdaptiveThrottle{
+		CheckInterval:      time.Duration(checkInterval) * time.Millisecond,
+		LatencyThreshold:   time.Duration(latencyThreshold) * time.Millisecond,
+		MaxLatencyPct:      derefFloat64(a.MaxLatencyPct),
+		MaxCPUPct:          derefInt64(a.MaxCPUPct),
+		MaxErrorsPerSecond: derefInt64(a.MaxErrorsPerSecond),
+		MinRatePct:         minRatePct,
 	}
 }
 
@@ -615,6 +815,8 @@ func stringPtr(s string) *string { return &s }
 
 func float64Ptr(f float64) *float64 { return &f }
 
+func durationPtr(d time.Duration) *time.Duration { return &d }
+
 func derefInt(p *int) int {
 	if p == nil {
 		return 0
@@ -655,6 +857,14 @@ func derefBool(p *bool) bool {
 	return *p
 }
 
+func derefDuration(p *time.Duration) time.Duration {
+	if p == nil {
+		return 0
+	}
+
+	return *p
+}
+
 func derefString(p *string) string {
 	if p == nil {
 		return ""
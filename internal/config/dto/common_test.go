@@ -16,7 +16,9 @@ package dto
 
 import (
 	"testing"
+	"time"
 
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
 	"github.com/aerospike/tools-common-go/flags"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -389,3 +391,60 @@ func TestCluster_ToModelClientPolicy(t *testing.T) {
 		assert.Equal(t, int64(0), policy.LoginTimeout)
 	})
 }
+
+func TestThrottle_ToModelThrottle(t *testing.T) {
+	t.Parallel()
+
+	var empty *Throttle
+
+	result, err := empty.ToModelThrottle()
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	throttle := &Throttle{
+		RecordsPerSecond: map[string]int64{"06:00-24:00": 20000, "00:00-06:00": 0},
+	}
+
+	result, err = throttle.ToModelThrottle()
+	require.NoError(t, err)
+	require.Len(t, result.RecordsPerSecond, 2)
+	require.Equal(t, int64(0), result.RecordsPerSecond[0].Value)
+	require.Equal(t, int64(20000), result.RecordsPerSecond[1].Value)
+	require.Empty(t, result.Bandwidth)
+
+	throttle.Bandwidth = map[string]int64{"bad": 1}
+
+	_, err = throttle.ToModelThrottle()
+	require.ErrorContains(t, err, "invalid bandwidth schedule")
+}
+
+func TestAdaptiveThrottle_ToModelAdaptiveThrottle(t *testing.T) {
+	t.Parallel()
+
+	throttle := &Throttle{Adaptive: &AdaptiveThrottle{MaxCPUPct: int64Ptr(80)}}
+
+	result, err := throttle.ToModelThrottle()
+	require.NoError(t, err)
+	require.Empty(t, result.RecordsPerSecond)
+	require.Equal(t, &models.AdaptiveThrottle{
+		CheckInterval:    10 * time.Second,
+		LatencyThreshold: 8 * time.Millisecond,
+		MaxCPUPct:        80,
+		MinRatePct:       10,
+	}, result.Adaptive)
+
+	adaptive := &AdaptiveThrottle{
+		CheckInterval:      int64Ptr(5000),
+		LatencyThreshold:   int64Ptr(64),
+		MaxLatencyPct:      float64Ptr(5),
+		MaxErrorsPerSecond: int64Ptr(100),
+		MinRatePct:         int64Ptr(25),
+	}
+	require.Equal(t, &models.AdaptiveThrottle{
+		CheckInterval:      5 * time.Second,
+		LatencyThreshold:   64 * time.Millisecond,
+		MaxLatencyPct:      5,
+		MaxErrorsPerSecond: 100,
+		MinRatePct:         25,
+	}, adaptive.ToModelAdaptiveThrottle())
+}
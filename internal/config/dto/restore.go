@@ -37,6 +37,11 @@ type Restore struct {
 	Azure struct {
 		Blob AzureBlob `yaml:"blob"`
 	} `yaml:"azure"`
+	Sftp  Sftp `yaml:"sftp"`
+	Local struct {
+		Disk Local `yaml:"disk"`
+	} `yaml:"local"`
+	Throttle Throttle `yaml:"throttle"`
 }
 
 // DefaultRestore returns a Restore with default values.
@@ -57,6 +62,10 @@ func DefaultRestore() *Restore {
 		Azure: struct {
 			Blob AzureBlob `yaml:"blob"`
 		}{Blob: defaultAzureBlob()},
+		Sftp: defaultSftp(),
+		Local: struct {
+			Disk Local `yaml:"disk"`
+		}{Disk: defaultLocal()},
 	}
 }
 
@@ -101,8 +110,13 @@ func (r *Restore) ToModelRestore() *models.Restore {
 		RetryBaseInterval:  derefInt64(r.Restore.RetryBaseInterval),
 		RetryMultiplier:    derefFloat64(r.Restore.RetryMultiplier),
 		RetryMaxAttempts:   derefUint(r.Restore.RetryMaxAttempts),
+		Mode:               derefString(r.Restore.Mode),
 		ValidateOnly:       derefBool(r.Restore.ValidateOnly),
 		ApplyMetadataLast:  derefBool(r.Restore.ApplyMetadataLast),
+		PointInTime:        derefString(r.Restore.PointInTime),
+		StateFileDst:       derefString(r.Restore.StateFileDst),
+		Continue:           derefString(r.Restore.Continue),
+		StateInterval:      derefInt64(r.Restore.StateInterval),
 	}
 }
 
@@ -134,6 +148,7 @@ type RestoreConfig struct {
 	RetryBaseInterval             *int64   `yaml:"retry-base-interval"`
 	RetryMultiplier               *float64 `yaml:"retry-multiplier"`
 	RetryMaxAttempts              *uint    `yaml:"retry-max-attempts"`
+	Mode                          *string  `yaml:"mode"`
 	ValidateOnly                  *bool    `yaml:"validate"`
 	InfoTimeout                   *int64   `yaml:"info-timeout"`
 	InfoMaxRetries                *uint    `yaml:"info-max-retries"`
@@ -141,6 +156,10 @@ type RestoreConfig struct {
 	InfoRetryIntervalMilliseconds *int64   `yaml:"info-retry-interval"`
 	ApplyMetadataLast             *bool    `yaml:"apply-metadata-last"`
 	StdBufferSize                 *int     `yaml:"std-buffer"`
+	PointInTime                   *string  `yaml:"point-in-time"`
+	StateFileDst                  *string  `yaml:"state-file-dst"`
+	Continue                      *string  `yaml:"continue"`
+	StateInterval                 *int64   `yaml:"state-interval"`
 }
 
 func defaultRestoreConfig() RestoreConfig {
@@ -177,7 +196,12 @@ func defaultRestoreConfig() RestoreConfig {
 		RetryBaseInterval:             int64Ptr(models.DefaultRestoreRetryBaseInterval),
 		RetryMultiplier:               float64Ptr(models.DefaultRestoreRetryMultiplier),
 		RetryMaxAttempts:              uintPtr(models.DefaultRestoreRetryMaxAttempts),
+		Mode:                          stringPtr(models.DefaultRestoreMode),
 		ValidateOnly:                  boolPtr(models.DefaultRestoreValidateOnly),
 		ApplyMetadataLast:             boolPtr(models.DefaultRestoreApplyMetadataLast),
+		PointInTime:                   stringPtr(models.DefaultRestorePointInTime),
+		StateFileDst:                  stringPtr(models.DefaultRestoreStateFileDst),
+		Continue:                      stringPtr(models.DefaultRestoreContinue),
+		StateInterval:                 int64Ptr(models.DefaultRestoreStateInterval),
 	}
 }
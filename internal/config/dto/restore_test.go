@@ -35,6 +35,7 @@ func TestDefaultRestore(t *testing.T) {
 	require.NotNil(t, restore.Aws.S3)
 	require.NotNil(t, restore.Gcp.Storage)
 	require.NotNil(t, restore.Azure.Blob)
+	require.NotNil(t, restore.Local.Disk)
 }
 
 func TestDefaultRestoreConfig(t *testing.T) {
@@ -74,6 +75,9 @@ func TestDefaultRestoreConfig(t *testing.T) {
 	assert.Equal(t, uint(models.DefaultRestoreRetryMaxAttempts), derefUint(config.RetryMaxAttempts))
 	assert.Equal(t, models.DefaultRestoreValidateOnly, derefBool(config.ValidateOnly))
 	assert.Equal(t, models.DefaultRestoreApplyMetadataLast, derefBool(config.ApplyMetadataLast))
+	assert.Equal(t, models.DefaultRestoreStateFileDst, derefString(config.StateFileDst))
+	assert.Equal(t, models.DefaultRestoreContinue, derefString(config.Continue))
+	assert.Equal(t, int64(models.DefaultRestoreStateInterval), derefInt64(config.StateInterval))
 }
 
 func TestRestoreConfig_ToModelRestore(t *testing.T) {
@@ -110,6 +114,7 @@ func TestRestoreConfig_ToModelRestore(t *testing.T) {
 		RetryBaseInterval:             int64Ptr(500),
 		RetryMultiplier:               float64Ptr(2.0),
 		RetryMaxAttempts:              uintPtr(10),
+		Mode:                          stringPtr("asbx"),
 		ValidateOnly:                  boolPtr(false),
 		ApplyMetadataLast:             boolPtr(true),
 	}
@@ -151,6 +156,7 @@ func TestRestoreConfig_ToModelRestore(t *testing.T) {
 	assert.Equal(t, int64(500), model.RetryBaseInterval)
 	assert.Equal(t, 2.0, model.RetryMultiplier)
 	assert.Equal(t, uint(10), model.RetryMaxAttempts)
+	assert.Equal(t, "asbx", model.Mode)
 	assert.False(t, model.ValidateOnly)
 	assert.True(t, model.ApplyMetadataLast)
 }
@@ -213,6 +219,7 @@ func TestRestore_ToModelRestore_DefaultToModel(t *testing.T) {
 	assert.Equal(t, int64(models.DefaultRestoreRetryBaseInterval), model.RetryBaseInterval)
 	assert.Equal(t, models.DefaultRestoreRetryMultiplier, model.RetryMultiplier)
 	assert.Equal(t, uint(models.DefaultRestoreRetryMaxAttempts), model.RetryMaxAttempts)
+	assert.Equal(t, models.DefaultRestoreMode, model.Mode)
 	assert.Equal(t, models.DefaultRestoreValidateOnly, model.ValidateOnly)
 	assert.Equal(t, models.DefaultRestoreApplyMetadataLast, model.ApplyMetadataLast)
 }
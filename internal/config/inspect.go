@@ -0,0 +1,108 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go"
+)
+
+// InspectServiceConfig represents the configuration of the inspection of .asbx files.
+type InspectServiceConfig struct {
+	App         *models.App
+	Inspect     *models.Inspect
+	Compression *models.Compression
+	Encryption  *models.Encryption
+	SecretAgent *models.SecretAgent
+	AwsS3       *models.AwsS3
+	GcpStorage  *models.GcpStorage
+	AzureBlob   *models.AzureBlob
+	Sftp        *models.Sftp
+	Local       *models.Local
+}
+
+// NewInspectServiceConfig returns a new InspectServiceConfig with the provided configuration components.
+func NewInspectServiceConfig(
+	app *models.App,
+	inspect *models.Inspect,
+	compression *models.Compression,
+	encryption *models.Encryption,
+	secretAgent *models.SecretAgent,
+	awsS3 *models.AwsS3,
+	gcpStorage *models.GcpStorage,
+	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
+	local *models.Local,
+) *InspectServiceConfig {
+	return &InspectServiceConfig{
+		App:         app,
+		Inspect:     inspect,
+		Compression: compression,
+		Encryption:  encryption,
+		SecretAgent: secretAgent,
+		AwsS3:       awsS3,
+		GcpStorage:  gcpStorage,
+		AzureBlob:   azureBlob,
+		Sftp:        sftp,
+		Local:       local,
+	}
+}
+
+// ToRestoreServiceConfig returns a restore configuration, that is used to initialize readers of .asbx files
+// in the directory. If directory is empty, the inspected directory or file is used.
+func (p *InspectServiceConfig) ToRestoreServiceConfig(directory string) *RestoreServiceConfig {
+	restore := &models.Restore{
+		Common:    models.Common{Directory: p.Inspect.Directory},
+		InputFile: p.Inspect.InputFile,
+		Mode:      models.RestoreModeASBX,
+	}
+
+	if directory != "" {
+		restore.Directory = directory
+	}
+
+	return &RestoreServiceConfig{
+		App:         p.App,
+		Restore:     restore,
+		Compression: p.Compression,
+		Encryption:  p.Encryption,
+		SecretAgent: p.SecretAgent,
+		AwsS3:       p.AwsS3,
+		GcpStorage:  p.GcpStorage,
+		AzureBlob:   p.AzureBlob,
+		Sftp:        p.Sftp,
+		Local:       p.Local,
+	}
+}
+
+// StoragePaths returns pointers to the inspected paths, that can contain storage URLs.
+func (p *InspectServiceConfig) StoragePaths() []*string {
+	return []*string{&p.Inspect.Directory, &p.Inspect.InputFile}
+}
+
+// CompressionPolicy returns the compression policy of the inspected files, or nil if they are not compressed.
+func (p *InspectServiceConfig) CompressionPolicy() *backup.CompressionPolicy {
+	return newCompressionPolicy(p.Compression)
+}
+
+// EncryptionPolicy returns the encryption policy of the inspected files, or nil if they are not encrypted.
+func (p *InspectServiceConfig) EncryptionPolicy() *backup.EncryptionPolicy {
+	return newEncryptionPolicy(p.Encryption)
+}
+
+// SecretAgentConfig returns the secret agent config, that is used to load storage secrets.
+func (p *InspectServiceConfig) SecretAgentConfig() *backup.SecretAgentConfig {
+	return newSecretAgentConfig(p.SecretAgent)
+}
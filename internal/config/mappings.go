@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/shard"
 	"github.com/aerospike/aerospike-client-go/v8"
 	"github.com/aerospike/backup-go"
 	bModels "github.com/aerospike/backup-go/models"
@@ -204,7 +205,9 @@ func newScanPolicy(b *models.Backup) (*aerospike.ScanPolicy, error) {
 	p.TotalTimeout = time.Duration(b.TotalTimeout) * time.Millisecond
 	p.SocketTimeout = time.Duration(b.SocketTimeout) * time.Millisecond
 	// If we selected racks we must set replica policy to aerospike.PREFER_RACK
-	if b.PreferRacks != "" {
+	// With a backup rack, the client prefers only this rack, so replicas of other racks are read
+	// only when the rack doesn't hold the partition.
+	if b.PreferRacks != "" || b.BackupRack != "" {
 		p.ReplicaPolicy = aerospike.PREFER_RACK
 	}
 
@@ -292,6 +295,15 @@ func mapPartitionFilter(b *models.Backup) ([]*aerospike.PartitionFilter, error)
 		return []*aerospike.PartitionFilter{afterDigestFilter}, nil
 	case b.PartitionList != "":
 		return backup.ParsePartitionFilterListString(b.Namespace, b.PartitionList)
+	case b.Shard != "":
+		s, shards, err := b.ParseShard()
+		if err != nil {
+			return nil, err
+		}
+
+		begin, count := shard.Partitions(s, shards)
+
+		return backup.ParsePartitionFilterListString(b.Namespace, fmt.Sprintf("%d-%d", begin, count))
 	default:
 		return []*aerospike.PartitionFilter{backup.NewPartitionFilterAll()}, nil
 	}
@@ -479,6 +479,14 @@ func TestMapScanPolicy_Success(t *testing.T) {
 	assert.False(t, scanPolicy.IncludeBinData)
 }
 
+func TestMapScanPolicy_BackupRack(t *testing.T) {
+	t.Parallel()
+
+	scanPolicy, err := newScanPolicy(&models.Backup{BackupRack: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, aerospike.PREFER_RACK, scanPolicy.ReplicaPolicy)
+}
+
 func TestMapWritePolicy_Success(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,151 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+)
+
+// pathTemplateData contains values of placeholders, that can be used in backup paths,
+// e.g. {{.Namespace}}/{{.Date "2006-01-02"}}/{{.Hostname}}.
+type pathTemplateData struct {
+	Namespace string
+	Hostname  string
+	// now is the backup start time.
+	now time.Time
+	// dateUsed is set when the template calls Date.
+	dateUsed bool
+}
+
+// Date formats the backup start time with the Go time layout.
+func (d *pathTemplateData) Date(layout string) string {
+	d.dateUsed = true
+	return d.now.Format(layout)
+}
+
+// templatePath is a path field, that can contain placeholders.
+type templatePath struct {
+	name  string
+	value *string
+}
+
+// ExpandPathTemplates expands placeholders in the backup directory and the output file prefix
+// of the backup, xdr backup and each job. Each path gets the namespace of its own backup.
+// The date can't be used when a run continues a previous one, as the path would change between runs.
+func (p *BackupServiceConfig) ExpandPathTemplates(now time.Time) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	// continuedBy is the option, that makes the run continue a previous one, or empty string.
+	expand := func(namespace, continuedBy string, paths ...templatePath) error {
+		for _, path := range paths {
+			data := &pathTemplateData{
+				Namespace: namespace,
+				Hostname:  hostname,
+				now:       now,
+			}
+
+			expanded, err := expandPathTemplate(*path.value, data)
+			if err != nil {
+				return fmt.Errorf("failed to expand %s template %s: %w", path.name, *path.value, err)
+			}
+
+			if data.dateUsed && continuedBy != "" {
+				return fmt.Errorf("%s template %s can't contain .Date with %s, the path must be the same on every run",
+					path.name, *path.value, continuedBy)
+			}
+
+			*path.value = expanded
+		}
+
+		return nil
+	}
+
+	if p.Backup != nil {
+		if err = expand(p.Backup.Namespace, backupContinuedBy(p.Backup),
+			templatePath{name: "directory", value: &p.Backup.Directory},
+			templatePath{name: "output-file-prefix", value: &p.Backup.OutputFilePrefix},
+		); err != nil {
+			return err
+		}
+	}
+
+	if p.BackupXDR != nil {
+		continuedBy := ""
+		if p.BackupXDR.RotateInterval > 0 {
+			continuedBy = "rotate-interval"
+		}
+
+		if err = expand(p.BackupXDR.Namespace, continuedBy,
+			templatePath{name: "directory", value: &p.BackupXDR.Directory},
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, job := range p.Jobs {
+		if job.Backup == nil {
+			continue
+		}
+
+		if err = expand(job.Backup.Namespace, backupContinuedBy(job.Backup),
+			templatePath{name: "directory", value: &job.Backup.Directory},
+			templatePath{name: "output-file-prefix", value: &job.Backup.OutputFilePrefix},
+		); err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// backupContinuedBy returns the option, that continues a previous backup, or empty string.
+func backupContinuedBy(b *models.Backup) string {
+	switch {
+	case b.Resume:
+		return "resume"
+	case b.Continue != "":
+		return "continue"
+	default:
+		return ""
+	}
+}
+
+// expandPathTemplate executes the template in value, values without placeholders are returned as is.
+func expandPathTemplate(value string, data *pathTemplateData) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err = tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
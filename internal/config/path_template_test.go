@@ -0,0 +1,177 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandPathTemplate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "Plain path",
+			value: "/backup/dir",
+			want:  "/backup/dir",
+		},
+		{
+			name:  "All placeholders",
+			value: `/backup/{{.Namespace}}/{{.Date "2006-01-02"}}/{{.Hostname}}`,
+			want:  "/backup/test/2024-05-01/host-1",
+		},
+		{
+			name:  "Storage URL",
+			value: `s3://bucket/{{.Namespace}}/{{.Date "2006/01/02_1504"}}`,
+			want:  "s3://bucket/test/2024/05/01_2230",
+		},
+		{
+			name:  "File prefix",
+			value: `{{.Hostname}}_`,
+			want:  "host-1_",
+		},
+		{
+			name:    "Unknown placeholder",
+			value:   "/backup/{{.Set}}",
+			wantErr: "can't evaluate field Set",
+		},
+		{
+			name:    "Invalid template",
+			value:   "/backup/{{.Namespace",
+			wantErr: "unclosed action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := &pathTemplateData{Namespace: "test", Hostname: "host-1", now: now}
+
+			got, err := expandPathTemplate(tt.value, data)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBackupServiceConfig_ExpandPathTemplates(t *testing.T) {
+	t.Parallel()
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	now := time.Date(2024, 5, 1, 22, 30, 0, 0, time.UTC)
+
+	params := &BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: `/backup/{{.Namespace}}/{{.Date "2006-01-02"}}`,
+				Namespace: "test",
+			},
+			OutputFilePrefix: "{{.Hostname}}_",
+		},
+		Jobs: []*models.BackupJob{
+			{
+				Name: "users",
+				Backup: &models.Backup{
+					Common: models.Common{
+						Directory: "/backup/{{.Namespace}}",
+						Namespace: "users",
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, params.ExpandPathTemplates(now))
+	assert.Equal(t, "/backup/test/2024-05-01", params.Backup.Directory)
+	assert.Equal(t, hostname+"_", params.Backup.OutputFilePrefix)
+	// Each job gets its own namespace.
+	assert.Equal(t, "/backup/users", params.Jobs[0].Backup.Directory)
+	assert.Equal(t, "/backup/test/2024-05-01", params.Destination())
+
+	xdrParams := &BackupServiceConfig{
+		BackupXDR: &models.BackupXDR{
+			Directory: `/xdr/{{.Namespace}}/{{.Date "20060102"}}`,
+			Namespace: "test",
+		},
+	}
+
+	require.NoError(t, xdrParams.ExpandPathTemplates(now))
+	assert.Equal(t, "/xdr/test/20240501", xdrParams.BackupXDR.Directory)
+
+	params.Jobs[0].Backup.Directory = "/backup/{{.Unknown}}"
+	err = params.ExpandPathTemplates(now)
+	assert.ErrorContains(t, err, "job users: failed to expand directory template")
+}
+
+func TestBackupServiceConfig_ExpandPathTemplatesContinued(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 22, 30, 0, 0, time.UTC)
+
+	newParams := func(directory string) *BackupServiceConfig {
+		return &BackupServiceConfig{
+			Backup: &models.Backup{
+				Common: models.Common{Directory: directory, Namespace: "test"},
+				Resume: true,
+			},
+		}
+	}
+
+	// The date changes between runs, so the state file wouldn't be found.
+	err := newParams(`/backup/{{.Date "2006-01-02"}}`).ExpandPathTemplates(now)
+	assert.ErrorContains(t, err, "can't contain .Date with resume")
+
+	params := newParams("/backup/{{.Namespace}}/{{.Hostname}}")
+	require.NoError(t, params.ExpandPathTemplates(now))
+
+	params = newParams("/backup")
+	params.Backup.Resume = false
+	params.Backup.Continue = "state"
+	params.Backup.OutputFilePrefix = `{{.Date "0102"}}_`
+	err = params.ExpandPathTemplates(now)
+	assert.ErrorContains(t, err, "output-file-prefix template {{.Date \"0102\"}}_ can't contain .Date with continue")
+
+	xdrParams := &BackupServiceConfig{
+		BackupXDR: &models.BackupXDR{
+			Directory:      `/xdr/{{.Date "20060102"}}`,
+			Namespace:      "test",
+			RotateInterval: time.Minute,
+		},
+	}
+
+	err = xdrParams.ExpandPathTemplates(now)
+	assert.ErrorContains(t, err, "can't contain .Date with rotate-interval")
+}
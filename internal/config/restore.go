@@ -37,6 +37,10 @@ type RestoreServiceConfig struct {
 	AwsS3        *models.AwsS3
 	GcpStorage   *models.GcpStorage
 	AzureBlob    *models.AzureBlob
+	Sftp         *models.Sftp
+	Local        *models.Local
+	// Throttle is set only from the config file.
+	Throttle *models.Throttle
 }
 
 // NewRestoreServiceConfig creates and returns a new RestoreServiceConfig initialized with the provided parameters.
@@ -53,6 +57,8 @@ func NewRestoreServiceConfig(
 	awsS3 *models.AwsS3,
 	gcpStorage *models.GcpStorage,
 	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
+	local *models.Local,
 ) (*RestoreServiceConfig, error) {
 	return &RestoreServiceConfig{
 		App:          app,
@@ -65,6 +71,8 @@ func NewRestoreServiceConfig(
 		AwsS3:        awsS3,
 		GcpStorage:   gcpStorage,
 		AzureBlob:    azureBlob,
+		Sftp:         sftp,
+		Local:        local,
 	}, nil
 }
 
@@ -78,6 +86,25 @@ func (r *RestoreServiceConfig) IsStdin() bool {
 	return false
 }
 
+// StoragePaths returns pointers to the restore paths, that can contain storage URLs.
+func (r *RestoreServiceConfig) StoragePaths() []*string {
+	if r.Restore == nil {
+		return nil
+	}
+
+	return []*string{&r.Restore.Directory, &r.Restore.InputFile, &r.Restore.ParentDirectory}
+}
+
+// CompressionPolicy returns the compression policy of the restored files, or nil if they are not compressed.
+func (r *RestoreServiceConfig) CompressionPolicy() *backup.CompressionPolicy {
+	return newCompressionPolicy(r.Compression)
+}
+
+// EncryptionPolicy returns the encryption policy of the restored files, or nil if they are not encrypted.
+func (r *RestoreServiceConfig) EncryptionPolicy() *backup.EncryptionPolicy {
+	return newEncryptionPolicy(r.Encryption)
+}
+
 // NewRestoreConfig creates and returns a new ConfigRestore object, initialized with given restore parameters.
 func NewRestoreConfig(serviceConfig *RestoreServiceConfig, logger *slog.Logger) *backup.ConfigRestore {
 	logger.Info("initializing restore config")
@@ -106,6 +133,12 @@ func NewRestoreConfig(serviceConfig *RestoreServiceConfig, logger *slog.Logger)
 	c.MaxAsyncBatches = serviceConfig.Restore.MaxAsyncBatches
 	c.MetricsEnabled = true
 
+	// With a throttle schedule, limits change during the run, so they are applied by the throttle controller.
+	if !serviceConfig.Throttle.IsEmpty() {
+		c.RecordsPerSecond = 0
+		c.Bandwidth = 0
+	}
+
 	c.CompressionPolicy = newCompressionPolicy(serviceConfig.Compression)
 	c.EncryptionPolicy = newEncryptionPolicy(serviceConfig.Encryption)
 	c.SecretAgentConfig = newSecretAgentConfig(serviceConfig.SecretAgent)
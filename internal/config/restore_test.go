@@ -39,6 +39,8 @@ func TestNewRestoreServiceConfig_WithoutConfigFile(t *testing.T) {
 	awsS3 := &models.AwsS3{}
 	gcpStorage := &models.GcpStorage{}
 	azureBlob := &models.AzureBlob{}
+	sftp := &models.Sftp{}
+	local := &models.Local{}
 
 	config, err := NewRestoreServiceConfig(
 		app,
@@ -51,6 +53,8 @@ func TestNewRestoreServiceConfig_WithoutConfigFile(t *testing.T) {
 		awsS3,
 		gcpStorage,
 		azureBlob,
+		sftp,
+		local,
 	)
 
 	require.NoError(t, err)
@@ -65,6 +69,8 @@ func TestNewRestoreServiceConfig_WithoutConfigFile(t *testing.T) {
 	assert.Equal(t, awsS3, config.AwsS3)
 	assert.Equal(t, gcpStorage, config.GcpStorage)
 	assert.Equal(t, azureBlob, config.AzureBlob)
+	assert.Equal(t, sftp, config.Sftp)
+	assert.Equal(t, local, config.Local)
 }
 
 func TestRestoreServiceConfig_IsStdin(t *testing.T) {
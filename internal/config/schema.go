@@ -0,0 +1,185 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config/dto"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches strings accepted by time.ParseDuration, e.g. 1h30m.
+const durationPattern = `^[-+]?(0|((\d+(\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h))+)$`
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// BackupSchema returns a JSON Schema describing the backup YAML configuration file.
+// The schema is generated from dto.Backup, so it always matches what DecodeBackupServiceConfig accepts.
+func BackupSchema() ([]byte, error) {
+	return newSchema("abs-backup-cli configuration", dto.DefaultBackup())
+}
+
+// RestoreSchema returns a JSON Schema describing the restore YAML configuration file.
+// The schema is generated from dto.Restore, so it always matches what DecodeRestoreServiceConfig accepts.
+func RestoreSchema() ([]byte, error) {
+	return newSchema("abs-restore-cli configuration", dto.DefaultRestore())
+}
+
+// newSchema builds a JSON Schema document for the given value. Default values are taken from v.
+func newSchema(title string, v any) ([]byte, error) {
+	schema, err := schemaForValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	schema["$schema"] = schemaDraft
+	schema["title"] = title
+
+	result, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	return result, nil
+}
+
+// schemaForValue maps a reflected value to a JSON Schema node.
+// Structs are closed with additionalProperties: false, the same way the YAML decoder
+// rejects unknown fields.
+func schemaForValue(v reflect.Value) (map[string]any, error) {
+	t := v.Type()
+
+	// Unwrap pointers, remembering the value to use it as a default.
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Value{}
+		}
+	}
+
+	node := make(map[string]any)
+
+	// Durations are int64, but the YAML decoder reads them from duration strings.
+	if t == durationType {
+		node["type"] = "string"
+		node["pattern"] = durationPattern
+
+		if v.IsValid() {
+			node["default"] = v.Interface().(time.Duration).String()
+		}
+
+		return node, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]any)
+
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := yamlFieldName(field)
+			if name == "" {
+				continue
+			}
+
+			var fieldValue reflect.Value
+			if v.IsValid() {
+				fieldValue = v.Field(i)
+			} else {
+				fieldValue = reflect.Zero(field.Type)
+			}
+
+			fieldSchema, err := schemaForValue(fieldValue)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build schema for %s: %w", name, err)
+			}
+
+			properties[name] = fieldSchema
+		}
+
+		node["type"] = "object"
+		node["properties"] = properties
+		node["additionalProperties"] = false
+
+		return node, nil
+	case reflect.Slice:
+		items, err := schemaForValue(reflect.Zero(t.Elem()))
+		if err != nil {
+			return nil, err
+		}
+
+		node["type"] = "array"
+		node["items"] = items
+
+		return node, nil
+	case reflect.Map:
+		values, err := schemaForValue(reflect.Zero(t.Elem()))
+		if err != nil {
+			return nil, err
+		}
+
+		node["type"] = "object"
+		node["additionalProperties"] = values
+
+		return node, nil
+	case reflect.String:
+		node["type"] = "string"
+	case reflect.Bool:
+		node["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		node["type"] = "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		node["type"] = "integer"
+		node["minimum"] = 0
+	case reflect.Float32, reflect.Float64:
+		node["type"] = "number"
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t.Kind())
+	}
+
+	if v.IsValid() {
+		node["default"] = v.Interface()
+	}
+
+	return node, nil
+}
+
+// yamlFieldName returns the key name used by the yaml decoder for a field, or empty string if the field is skipped.
+func yamlFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return name
+}
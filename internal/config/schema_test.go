@@ -0,0 +1,99 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaNode struct {
+	Type                 string                `json:"type"`
+	Properties           map[string]schemaNode `json:"properties"`
+	Items                *schemaNode           `json:"items"`
+	AdditionalProperties any                   `json:"additionalProperties"`
+	Default              any                   `json:"default"`
+	Pattern              string                `json:"pattern"`
+}
+
+func TestBackupSchema(t *testing.T) {
+	t.Parallel()
+
+	data, err := BackupSchema()
+	require.NoError(t, err)
+
+	var schema schemaNode
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	require.Equal(t, "object", schema.Type)
+	require.Equal(t, false, schema.AdditionalProperties)
+
+	backupSection, ok := schema.Properties["backup"]
+	require.True(t, ok)
+	require.Equal(t, false, backupSection.AdditionalProperties)
+
+	parallel := backupSection.Properties["parallel"]
+	require.Equal(t, "integer", parallel.Type)
+	require.EqualValues(t, models.DefaultBackupParallel, parallel.Default)
+
+	require.Equal(t, "array", backupSection.Properties["set-list"].Type)
+	require.Equal(t, "string", backupSection.Properties["set-list"].Items.Type)
+
+	seeds := schema.Properties["cluster"].Properties["seeds"]
+	require.Equal(t, "array", seeds.Type)
+	require.Equal(t, "object", seeds.Items.Type)
+	require.Contains(t, seeds.Items.Properties, "host")
+
+	require.Contains(t, schema.Properties["local"].Properties["disk"].Properties, "buffer-size")
+
+	// Throttle schedules are maps of windows to limits.
+	rps := schema.Properties["throttle"].Properties["rps"]
+	require.Equal(t, "object", rps.Type)
+	require.Equal(t, "integer", rps.AdditionalProperties.(map[string]any)["type"])
+
+	// Durations are set as strings.
+	rotateInterval := schema.Properties["backup-xdr"].Properties["rotate-interval"]
+	require.Equal(t, "string", rotateInterval.Type)
+	require.Equal(t, "0s", rotateInterval.Default)
+
+	pattern := regexp.MustCompile(rotateInterval.Pattern)
+	for _, d := range []string{"15m", "1h30m", "0", "1.5h", "500ms"} {
+		require.Truef(t, pattern.MatchString(d), "Duration %s should match the pattern", d)
+	}
+
+	for _, d := range []string{"15", "m", "1 h", "1d"} {
+		require.Falsef(t, pattern.MatchString(d), "Value %s should not match the pattern", d)
+	}
+}
+
+func TestRestoreSchema(t *testing.T) {
+	t.Parallel()
+
+	data, err := RestoreSchema()
+	require.NoError(t, err)
+
+	var schema schemaNode
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	restoreSection, ok := schema.Properties["restore"]
+	require.True(t, ok)
+	require.Equal(t, "boolean", restoreSection.Properties["validate"].Type)
+	require.Equal(t, "number", restoreSection.Properties["retry-multiplier"].Type)
+	require.NotContains(t, schema.Properties, "backup")
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+)
+
+// Storage URL schemes, that can be used in paths instead of bucket and container flags.
+const (
+	storageSchemeS3    = "s3"
+	storageSchemeGcp   = "gs"
+	storageSchemeAzure = "az"
+	storageSchemeFile  = "file"
+)
+
+// storageURLRegexp matches paths, that start with a URL scheme.
+var storageURLRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://`)
+
+// storageURL is a parsed storage URL, e.g. s3://bucket/prefix.
+type storageURL struct {
+	scheme string
+	// bucket is a bucket or container name, empty for file URLs.
+	bucket string
+	// path is a path inside the bucket or on the local file system.
+	path string
+}
+
+// parseStorageURL parses a path in the s3://bucket/path, gs://bucket/path, az://container/path
+// or file:///path format. If the path is not a URL, nil is returned.
+func parseStorageURL(raw string) (*storageURL, error) {
+	if !storageURLRegexp.MatchString(raw) {
+		return nil, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse storage URL %s: %w", raw, err)
+	}
+
+	if u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return nil, fmt.Errorf("storage URL %s must not contain user info, query or fragment", raw)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+
+	switch scheme {
+	case storageSchemeS3, storageSchemeGcp, storageSchemeAzure:
+		if u.Host == "" {
+			return nil, fmt.Errorf("storage URL %s must contain a bucket or container name", raw)
+		}
+
+		// Paths in buckets are relative, the root of a bucket is "/".
+		p := strings.TrimPrefix(u.Path, "/")
+		if p == "" {
+			p = "/"
+		}
+
+		return &storageURL{scheme: scheme, bucket: u.Host, path: p}, nil
+	case storageSchemeFile:
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("file storage URL %s must contain an absolute path, e.g. file:///path", raw)
+		}
+
+		if u.Path == "" {
+			return nil, fmt.Errorf("file storage URL %s must contain a path", raw)
+		}
+
+		return &storageURL{scheme: scheme, path: u.Path}, nil
+	default:
+		return nil, fmt.Errorf("unsupported storage URL scheme %s, must be one of %s, %s, %s, %s",
+			u.Scheme, storageSchemeS3, storageSchemeGcp, storageSchemeAzure, storageSchemeFile)
+	}
+}
+
+// resolveStorageURLs replaces storage URLs in paths with paths inside the storage,
+// and sets the bucket or container name from URLs to the storage configuration.
+// All URLs must point to the same bucket, that must match the bucket set in the storage configuration.
+// It returns the scheme of the URLs, or an empty string if no URL is used.
+func resolveStorageURLs(
+	paths []*string,
+	awsS3 *models.AwsS3,
+	gcpStorage *models.GcpStorage,
+	azureBlob *models.AzureBlob,
+) (string, error) {
+	var resolved *storageURL
+
+	for _, p := range paths {
+		if p == nil {
+			continue
+		}
+
+		u, err := parseStorageURL(*p)
+		if err != nil {
+			return "", err
+		}
+
+		if u == nil {
+			continue
+		}
+
+		if resolved != nil && (resolved.scheme != u.scheme || resolved.bucket != u.bucket) {
+			return "", fmt.Errorf("storage URL %s conflicts with other storage URLs, all URLs must use the same storage",
+				*p)
+		}
+
+		if err = setStorageBucket(u, *p, awsS3, gcpStorage, azureBlob); err != nil {
+			return "", err
+		}
+
+		resolved = u
+		*p = u.path
+	}
+
+	if resolved == nil {
+		return "", nil
+	}
+
+	return resolved.scheme, nil
+}
+
+// setStorageBucket sets the bucket or container name from the URL to the storage configuration.
+func setStorageBucket(
+	u *storageURL,
+	raw string,
+	awsS3 *models.AwsS3,
+	gcpStorage *models.GcpStorage,
+	azureBlob *models.AzureBlob,
+) error {
+	var bucket *string
+
+	switch u.scheme {
+	case storageSchemeS3:
+		if awsS3 != nil {
+			bucket = &awsS3.BucketName
+		}
+	case storageSchemeGcp:
+		if gcpStorage != nil {
+			bucket = &gcpStorage.BucketName
+		}
+	case storageSchemeAzure:
+		if azureBlob != nil {
+			bucket = &azureBlob.ContainerName
+		}
+	default:
+		return nil
+	}
+
+	if bucket == nil {
+		return fmt.Errorf("storage URL %s can't be used, %s storage is not configured", raw, u.scheme)
+	}
+
+	if *bucket != "" && *bucket != u.bucket {
+		return fmt.Errorf("storage URL %s conflicts with configured bucket %s", raw, *bucket)
+	}
+
+	*bucket = u.bucket
+
+	return nil
+}
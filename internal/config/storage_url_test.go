@@ -0,0 +1,161 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPath(p string) *string {
+	return &p
+}
+
+func TestParseStorageURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    *storageURL
+		wantErr string
+	}{
+		{
+			name: "Plain path",
+			raw:  "/backup/dir",
+		},
+		{
+			name: "Relative path",
+			raw:  "backup",
+		},
+		{
+			name: "S3 URL",
+			raw:  "s3://bucket/prefix/dir",
+			want: &storageURL{scheme: storageSchemeS3, bucket: "bucket", path: "prefix/dir"},
+		},
+		{
+			name: "S3 bucket root",
+			raw:  "s3://bucket",
+			want: &storageURL{scheme: storageSchemeS3, bucket: "bucket", path: "/"},
+		},
+		{
+			name: "GCP URL",
+			raw:  "gs://bucket/file.asb",
+			want: &storageURL{scheme: storageSchemeGcp, bucket: "bucket", path: "file.asb"},
+		},
+		{
+			name: "Azure URL",
+			raw:  "az://container/dir/",
+			want: &storageURL{scheme: storageSchemeAzure, bucket: "container", path: "dir/"},
+		},
+		{
+			name: "File URL",
+			raw:  "file:///mnt/backup",
+			want: &storageURL{scheme: storageSchemeFile, path: "/mnt/backup"},
+		},
+		{
+			name: "Upper case scheme",
+			raw:  "S3://bucket/dir",
+			want: &storageURL{scheme: storageSchemeS3, bucket: "bucket", path: "dir"},
+		},
+		{
+			name:    "Missing bucket",
+			raw:     "s3:///dir",
+			wantErr: "must contain a bucket or container name",
+		},
+		{
+			name:    "File URL with host",
+			raw:     "file://backup/dir",
+			wantErr: "must contain an absolute path",
+		},
+		{
+			name:    "Query",
+			raw:     "gs://bucket/dir?generation=1",
+			wantErr: "must not contain user info, query or fragment",
+		},
+		{
+			name:    "Unsupported scheme",
+			raw:     "ftp://host/dir",
+			wantErr: "unsupported storage URL scheme ftp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseStorageURL(tt.raw)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveStorageURLs(t *testing.T) {
+	t.Parallel()
+
+	directory, parent := "s3://bucket/backup", "/local/dir"
+	awsS3 := &models.AwsS3{}
+
+	scheme, err := resolveStorageURLs([]*string{&directory, nil, &parent}, awsS3, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, storageSchemeS3, scheme)
+	assert.Equal(t, "backup", directory)
+	assert.Equal(t, "/local/dir", parent)
+	assert.Equal(t, "bucket", awsS3.BucketName)
+
+	// Resolved paths are not changed again.
+	scheme, err = resolveStorageURLs([]*string{&directory}, awsS3, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, scheme)
+	assert.Equal(t, "backup", directory)
+
+	// The same bucket is set with the flag.
+	gcp := &models.GcpStorage{BucketName: "bucket"}
+	scheme, err = resolveStorageURLs([]*string{testPath("gs://bucket/dir")}, nil, gcp, nil)
+	require.NoError(t, err)
+	assert.Equal(t, storageSchemeGcp, scheme)
+
+	// Another container is set with the flag.
+	azure := &models.AzureBlob{ContainerName: "other"}
+	_, err = resolveStorageURLs([]*string{testPath("az://container/dir")}, nil, nil, azure)
+	assert.ErrorContains(t, err, "conflicts with configured bucket other")
+
+	// URLs of different storages.
+	_, err = resolveStorageURLs(
+		[]*string{testPath("s3://bucket/dir"), testPath("gs://bucket/dir")},
+		&models.AwsS3{}, &models.GcpStorage{}, nil,
+	)
+	assert.ErrorContains(t, err, "conflicts with other storage URLs")
+
+	// Storage is not configured.
+	_, err = resolveStorageURLs([]*string{testPath("gs://bucket/dir")}, nil, nil, nil)
+	assert.ErrorContains(t, err, "gs storage is not configured")
+
+	// File URL.
+	directory = "file:///mnt/backup"
+	scheme, err = resolveStorageURLs([]*string{&directory}, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, storageSchemeFile, scheme)
+	assert.Equal(t, "/mnt/backup", directory)
+}
@@ -22,17 +22,28 @@ import (
 	"github.com/aerospike/aerospike-client-go/v8"
 )
 
+// ValidateStorages validates the storage configuration and checks that only one storage is configured.
+// Storage URLs in paths, e.g. s3://bucket/prefix, are replaced with paths inside the storage,
+// and the bucket or container name from URLs is set to the storage configuration.
+//
 //nolint:gocyclo // It is a long validation function.
 func ValidateStorages(
 	isBackup bool,
+	paths []*string,
 	awsS3 *models.AwsS3,
 	gcpStorage *models.GcpStorage,
 	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
 	local *models.Local,
 ) error {
 	// TODO: think how to rework this func. I want to get rid of it.
 	var count int
 
+	scheme, err := resolveStorageURLs(paths, awsS3, gcpStorage, azureBlob)
+	if err != nil {
+		return err
+	}
+
 	if local != nil {
 		if err := local.Validate(isBackup); err != nil {
 			return fmt.Errorf("failed to validate local storage: %w", err)
@@ -65,13 +76,42 @@ func ValidateStorages(
 		count++
 	}
 
+	if sftp != nil && (sftp.Host != "" || sftp.User != "") {
+		if err := sftp.Validate(isBackup); err != nil {
+			return fmt.Errorf("failed to validate sftp: %w", err)
+		}
+
+		count++
+	}
+
 	if count > 1 {
 		return fmt.Errorf("only one cloud provider can be configured")
 	}
 
+	if scheme == storageSchemeFile && count > 0 {
+		return fmt.Errorf("file storage URL can't be used with cloud or SFTP storage")
+	}
+
 	return nil
 }
 
+// ValidateRetention checks that the retention of backup files is supported by the configured storage.
+func ValidateRetention(
+	backupParams *models.Backup,
+	awsS3 *models.AwsS3,
+	azureBlob *models.AzureBlob,
+) error {
+	if backupParams == nil || !backupParams.HasRetention() {
+		return nil
+	}
+
+	if (awsS3 != nil && awsS3.BucketName != "") || (azureBlob != nil && azureBlob.ContainerName != "") {
+		return nil
+	}
+
+	return fmt.Errorf("retention-days and legal-hold are supported only for S3 and Azure Blob storage")
+}
+
 func ValidatePartitionFilters(partitionFilters []*aerospike.PartitionFilter) error {
 	if len(partitionFilters) < 1 {
 		return nil
@@ -33,9 +33,11 @@ func TestValidateStorages(t *testing.T) {
 	tests := []struct {
 		name       string
 		isBackup   bool
+		paths      []*string
 		awsS3      *models.AwsS3
 		gcpStorage *models.GcpStorage
 		azureBlob  *models.AzureBlob
+		sftp       *models.Sftp
 		local      *models.Local
 		wantErr    bool
 	}{
@@ -213,13 +215,106 @@ func TestValidateStorages(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:     "Valid SFTP configuration only",
+			isBackup: true,
+			sftp: &models.Sftp{
+				Host:        "backup-host",
+				User:        "backup",
+				KeyFile:     "/home/backup/.ssh/id_ed25519",
+				Connections: 1,
+				BufferSize:  5,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "SFTP without authorization",
+			isBackup: true,
+			sftp: &models.Sftp{
+				Host:        "backup-host",
+				User:        "backup",
+				Connections: 1,
+				BufferSize:  5,
+			},
+			wantErr: true,
+		},
+		{
+			name:     "SFTP and AWS S3 configured",
+			isBackup: false,
+			awsS3: &models.AwsS3{
+				Region:              "us-west-2",
+				BucketName:          testBucket,
+				RestorePollDuration: 1,
+				StorageCommon: models.StorageCommon{
+					RetryReadMultiplier: 2,
+					RetryReadBackoff:    100,
+				},
+			},
+			sftp: &models.Sftp{
+				Host:        "backup-host",
+				User:        "backup",
+				UseAgent:    true,
+				Connections: 1,
+			},
+			wantErr: true,
+		},
+		{
+			name:     "S3 URL with GCP bucket configured",
+			isBackup: false,
+			paths:    []*string{testPath("s3://" + testBucket + "/backup")},
+			awsS3: &models.AwsS3{
+				Region:              "us-west-2",
+				RestorePollDuration: 1,
+				StorageCommon: models.StorageCommon{
+					RetryReadMultiplier: 2,
+					RetryReadBackoff:    100,
+				},
+			},
+			gcpStorage: &models.GcpStorage{
+				BucketName:             testBucket,
+				RetryBackoffMultiplier: 2,
+				StorageCommon: models.StorageCommon{
+					RetryReadMultiplier: 2,
+					RetryReadBackoff:    100,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "File URL with AWS S3 configured",
+			isBackup: false,
+			paths:    []*string{testPath("file:///backup")},
+			awsS3: &models.AwsS3{
+				Region:              "us-west-2",
+				BucketName:          testBucket,
+				RestorePollDuration: 1,
+				StorageCommon: models.StorageCommon{
+					RetryReadMultiplier: 2,
+					RetryReadBackoff:    100,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Azure URL with different container configured",
+			isBackup: true,
+			paths:    []*string{testPath("az://other/backup")},
+			azureBlob: &models.AzureBlob{
+				ContainerName:       testBucket,
+				AccountName:         "account-name",
+				AccountKey:          "account-key",
+				RestorePollDuration: 1,
+				UploadConcurrency:   10,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			err := ValidateStorages(tt.isBackup, tt.awsS3, tt.gcpStorage, tt.azureBlob, tt.local)
+			err := ValidateStorages(tt.isBackup, tt.paths, tt.awsS3, tt.gcpStorage, tt.azureBlob, tt.sftp, tt.local)
 			if tt.wantErr {
 				assert.Error(t, err, "Expected error but got none")
 			} else {
@@ -229,6 +324,19 @@ func TestValidateStorages(t *testing.T) {
 	}
 }
 
+func TestValidateRetention(t *testing.T) {
+	t.Parallel()
+
+	retention := &models.Backup{RetentionDays: 30}
+
+	assert.NoError(t, ValidateRetention(&models.Backup{}, nil, nil))
+	assert.NoError(t, ValidateRetention(retention, &models.AwsS3{BucketName: testBucket}, nil))
+	assert.NoError(t, ValidateRetention(retention, nil, &models.AzureBlob{ContainerName: testBucket}))
+	assert.ErrorContains(t, ValidateRetention(retention, &models.AwsS3{}, nil),
+		"retention-days and legal-hold are supported only for S3 and Azure Blob storage")
+	assert.Error(t, ValidateRetention(&models.Backup{LegalHold: true}, nil, nil))
+}
+
 func TestValidatePartitionFilters(t *testing.T) {
 	t.Parallel()
 
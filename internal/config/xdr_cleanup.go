@@ -0,0 +1,74 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/tools-common-go/client"
+)
+
+// XDRCleanupServiceConfig represents the configuration of the cleanup after an interrupted xdr backup.
+type XDRCleanupServiceConfig struct {
+	App          *models.App
+	ClientConfig *client.AerospikeConfig
+	ClientPolicy *models.ClientPolicy
+	XDRCleanup   *models.XDRCleanup
+	SecretAgent  *models.SecretAgent
+}
+
+// NewXDRCleanupServiceConfig returns a new XDRCleanupServiceConfig with the provided configuration components.
+func NewXDRCleanupServiceConfig(
+	app *models.App,
+	clientConfig *client.AerospikeConfig,
+	clientPolicy *models.ClientPolicy,
+	xdrCleanup *models.XDRCleanup,
+	secretAgent *models.SecretAgent,
+) *XDRCleanupServiceConfig {
+	return &XDRCleanupServiceConfig{
+		App:          app,
+		ClientConfig: clientConfig,
+		ClientPolicy: clientPolicy,
+		XDRCleanup:   xdrCleanup,
+		SecretAgent:  secretAgent,
+	}
+}
+
+// ToXDRCleanupServiceConfig returns the cleanup configuration of the xdr backup.
+// Namespace, DC, state file and info policies are taken from the backup-xdr section,
+// unless the state file is set by flags.
+func (p *BackupServiceConfig) ToXDRCleanupServiceConfig(cleanup *models.XDRCleanup) *XDRCleanupServiceConfig {
+	xdrCleanup := &models.XDRCleanup{
+		Namespace:                     p.BackupXDR.Namespace,
+		DC:                            p.BackupXDR.DC,
+		StateFile:                     p.BackupXDR.CleanupStateFile,
+		DryRun:                        cleanup.DryRun,
+		InfoTimeout:                   p.BackupXDR.InfoTimeout,
+		InfoMaxRetries:                p.BackupXDR.InfoMaxRetries,
+		InfoRetriesMultiplier:         p.BackupXDR.InfoRetriesMultiplier,
+		InfoRetryIntervalMilliseconds: p.BackupXDR.InfoRetryIntervalMilliseconds,
+	}
+
+	if cleanup.StateFile != "" {
+		xdrCleanup.StateFile = cleanup.StateFile
+	}
+
+	return NewXDRCleanupServiceConfig(p.App, p.ClientConfig, p.ClientPolicy, xdrCleanup, p.SecretAgent)
+}
+
+// SecretAgentConfig returns the secret agent config, that is used to load client secrets.
+func (p *XDRCleanupServiceConfig) SecretAgentConfig() *backup.SecretAgentConfig {
+	return newSecretAgentConfig(p.SecretAgent)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/tools-common-go/client"
+)
+
+// XDRStatusServiceConfig represents the configuration of the xdr status check.
+type XDRStatusServiceConfig struct {
+	App          *models.App
+	ClientConfig *client.AerospikeConfig
+	ClientPolicy *models.ClientPolicy
+	XDRStatus    *models.XDRStatus
+	SecretAgent  *models.SecretAgent
+}
+
+// NewXDRStatusServiceConfig returns a new XDRStatusServiceConfig with the provided configuration components.
+func NewXDRStatusServiceConfig(
+	app *models.App,
+	clientConfig *client.AerospikeConfig,
+	clientPolicy *models.ClientPolicy,
+	xdrStatus *models.XDRStatus,
+	secretAgent *models.SecretAgent,
+) *XDRStatusServiceConfig {
+	return &XDRStatusServiceConfig{
+		App:          app,
+		ClientConfig: clientConfig,
+		ClientPolicy: clientPolicy,
+		XDRStatus:    xdrStatus,
+		SecretAgent:  secretAgent,
+	}
+}
+
+// ToXDRStatusServiceConfig returns the status check configuration of the xdr backup.
+// Namespace, DC and info policies are taken from the backup-xdr section, the check limits are set by flags.
+func (p *BackupServiceConfig) ToXDRStatusServiceConfig(status *models.XDRStatus) *XDRStatusServiceConfig {
+	xdrStatus := &models.XDRStatus{
+		Namespace:                     p.BackupXDR.Namespace,
+		DC:                            p.BackupXDR.DC,
+		MaxLag:                        status.MaxLag,
+		AllowIdle:                     status.AllowIdle,
+		InfoTimeout:                   p.BackupXDR.InfoTimeout,
+		InfoMaxRetries:                p.BackupXDR.InfoMaxRetries,
+		InfoRetriesMultiplier:         p.BackupXDR.InfoRetriesMultiplier,
+		InfoRetryIntervalMilliseconds: p.BackupXDR.InfoRetryIntervalMilliseconds,
+	}
+
+	return NewXDRStatusServiceConfig(p.App, p.ClientConfig, p.ClientPolicy, xdrStatus, p.SecretAgent)
+}
+
+// SecretAgentConfig returns the secret agent config, that is used to load client secrets.
+func (p *XDRStatusServiceConfig) SecretAgentConfig() *backup.SecretAgentConfig {
+	return newSecretAgentConfig(p.SecretAgent)
+}
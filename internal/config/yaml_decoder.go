@@ -48,6 +48,11 @@ func dtoToBackupServiceConfig(dtoBackup *dto.Backup) (*BackupServiceConfig, erro
 		return nil, fmt.Errorf("failed to map to aerospike config: %w", err)
 	}
 
+	throttle, err := dtoBackup.Throttle.ToModelThrottle()
+	if err != nil {
+		return nil, fmt.Errorf("failed to map throttle: %w", err)
+	}
+
 	return &BackupServiceConfig{
 		App:          dtoBackup.App.ToModelApp(),
 		ClientConfig: asConfig,
@@ -59,7 +64,11 @@ func dtoToBackupServiceConfig(dtoBackup *dto.Backup) (*BackupServiceConfig, erro
 		AwsS3:        dtoBackup.Aws.S3.ToModelAwsS3(),
 		GcpStorage:   dtoBackup.Gcp.Storage.ToModelGcpStorage(),
 		AzureBlob:    dtoBackup.Azure.Blob.ToModelAzureBlob(),
+		Sftp:         dtoBackup.Sftp.ToModelSftp(),
 		Local:        dtoBackup.Local.Disk.ToModelLocal(),
+		Throttle:     throttle,
+		Jobs:         dtoBackup.ToModelBackupJobs(),
+		BackupXDR:    dtoBackup.ToModelBackupXDR(),
 	}, nil
 }
 
@@ -89,6 +98,11 @@ func dtoToRestoreServiceConfig(dtoRestore *dto.Restore) (*RestoreServiceConfig,
 		return nil, fmt.Errorf("failed to map to aerospike config: %w", err)
 	}
 
+	throttle, err := dtoRestore.Throttle.ToModelThrottle()
+	if err != nil {
+		return nil, fmt.Errorf("failed to map throttle: %w", err)
+	}
+
 	return &RestoreServiceConfig{
 		App:          dtoRestore.App.ToModelApp(),
 		ClientConfig: asConfig,
@@ -100,6 +114,9 @@ func dtoToRestoreServiceConfig(dtoRestore *dto.Restore) (*RestoreServiceConfig,
 		AwsS3:        dtoRestore.Aws.S3.ToModelAwsS3(),
 		GcpStorage:   dtoRestore.Gcp.Storage.ToModelGcpStorage(),
 		AzureBlob:    dtoRestore.Azure.Blob.ToModelAzureBlob(),
+		Sftp:         dtoRestore.Sftp.ToModelSftp(),
+		Local:        dtoRestore.Local.Disk.ToModelLocal(),
+		Throttle:     throttle,
 	}, nil
 }
 
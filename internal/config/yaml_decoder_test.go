@@ -15,9 +15,11 @@
 package config
 
 import (
+	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aerospike/aerospike-backup-cli/internal/config/dto"
 	"github.com/stretchr/testify/require"
@@ -58,6 +60,19 @@ encryption:
   encrypt: none
 `
 
+	unknownKeyBackupYAML = `
+backup:
+  namespace: test
+  parralel: 3
+`
+
+	unknownKeyRestoreYAML = `
+restore:
+  namespace: test
+  directory: test
+  batch: 10
+`
+
 	invalidYAML = `
 invalid: yaml: content:
   - this is not valid
@@ -103,6 +118,13 @@ func TestDecodeBackupServiceConfig(t *testing.T) {
 			setupFile: true,
 			wantErr:   "failed to decode config file",
 		},
+		{
+			name:      "unknown key",
+			filename:  "unknown_key.yaml",
+			content:   unknownKeyBackupYAML,
+			setupFile: true,
+			wantErr:   "line 4: field parralel not found",
+		},
 		{
 			name:      "empty file",
 			filename:  "empty.yaml",
@@ -181,6 +203,13 @@ func TestDecodeRestoreServiceConfig(t *testing.T) {
 			setupFile: true,
 			wantErr:   "failed to decode config file",
 		},
+		{
+			name:      "unknown key",
+			filename:  "unknown_key.yaml",
+			content:   unknownKeyRestoreYAML,
+			setupFile: true,
+			wantErr:   "line 5: field batch not found",
+		},
 		{
 			name:      "empty file",
 			filename:  "empty.yaml",
@@ -376,3 +405,129 @@ func createTempFile(t *testing.T, name, content string) string {
 
 	return tempFile
 }
+
+func TestDecodeBackupServiceConfigJobs(t *testing.T) {
+	t.Parallel()
+
+	content := `
+backup:
+  namespace: test
+  parallel: 4
+  parallel-jobs: 2
+jobs:
+  - name: users
+    directory: backups/users
+    set-list:
+      - users
+  - namespace: events
+    directory: backups/events
+`
+	config, err := DecodeBackupServiceConfig(createTempFile(t, "jobs.yaml", content))
+	require.NoError(t, err)
+	require.True(t, config.IsMultiJob())
+	require.Len(t, config.Jobs, 2)
+	require.Equal(t, 2, config.Backup.ParallelJobs)
+
+	require.Equal(t, "users", config.Jobs[0].Name)
+	require.Equal(t, "test", config.Jobs[0].Backup.Namespace)
+	require.Equal(t, "users", config.Jobs[0].Backup.SetList)
+	require.Equal(t, 4, config.Jobs[0].Backup.Parallel)
+
+	require.Equal(t, "events-1", config.Jobs[1].Name)
+	require.Equal(t, "backups/events", config.ForJob(config.Jobs[1]).Backup.Directory)
+}
+
+func TestDecodeBackupServiceConfigXDR(t *testing.T) {
+	t.Parallel()
+
+	content := `
+backup-xdr:
+  namespace: test
+  directory: xdr
+  dc: backup-dc
+  local-address: 10.0.0.1
+  local-port: 8066
+  file-limit: 100
+  rotate-interval: 15m
+  cleanup-state-file: /var/lib/abs/xdr.json
+`
+	backupConfig, err := DecodeBackupServiceConfig(createTempFile(t, "xdr.yaml", content))
+	require.NoError(t, err)
+	require.NotNil(t, backupConfig.BackupXDR)
+	require.Equal(t, "test", backupConfig.BackupXDR.Namespace)
+	require.Equal(t, "xdr", backupConfig.BackupXDR.Directory)
+	require.Equal(t, "backup-dc", backupConfig.BackupXDR.DC)
+	require.Equal(t, "10.0.0.1", backupConfig.BackupXDR.LocalAddress)
+	require.Equal(t, 8066, backupConfig.BackupXDR.LocalPort)
+	require.Equal(t, uint64(100), backupConfig.BackupXDR.FileLimit)
+	require.Equal(t, 15*time.Minute, backupConfig.BackupXDR.RotateInterval)
+	require.True(t, backupConfig.IsRotateXDR())
+	require.Equal(t, "/var/lib/abs/xdr.json", backupConfig.BackupXDR.CleanupStateFile)
+	// Not set values are defaults.
+	require.Equal(t, "all", backupConfig.BackupXDR.Rewind)
+	require.Equal(t, 4096, backupConfig.BackupXDR.MaxConnections)
+	require.NoError(t, backupConfig.BackupXDR.Validate())
+
+	// Without the section, xdr backup is not configured.
+	backupConfig, err = DecodeBackupServiceConfig(createTempFile(t, "no_xdr.yaml", validBackupYAML))
+	require.NoError(t, err)
+	require.Nil(t, backupConfig.BackupXDR)
+}
+
+func TestDecodeServiceConfigThrottle(t *testing.T) {
+	t.Parallel()
+
+	content := `
+backup:
+  namespace: test
+  directory: test
+  records-per-second: 5000
+throttle:
+  rps:
+    "00:00-06:00": 0
+    "06:00-24:00": 20000
+  bandwidth:
+    "09:00-18:00": 50
+`
+	backupConfig, err := DecodeBackupServiceConfig(createTempFile(t, "throttle.yaml", content))
+	require.NoError(t, err)
+	require.NotNil(t, backupConfig.Throttle)
+	require.Len(t, backupConfig.Throttle.RecordsPerSecond, 2)
+	require.Len(t, backupConfig.Throttle.Bandwidth, 1)
+	require.NoError(t, backupConfig.Throttle.Validate())
+
+	// Limits are applied by the throttle controller instead of backup-go.
+	cfg, err := newBackupConfig(backupConfig)
+	require.NoError(t, err)
+	require.Zero(t, cfg.RecordsPerSecond)
+	require.Zero(t, cfg.Bandwidth)
+
+	restoreContent := `
+restore:
+  namespace: test
+  directory: test
+throttle:
+  rps:
+    "06:00": 100
+`
+	_, err = DecodeRestoreServiceConfig(createTempFile(t, "throttle_restore.yaml", restoreContent))
+	require.ErrorContains(t, err, "failed to map throttle: invalid rps schedule")
+
+	adaptiveContent := `
+restore:
+  namespace: test
+  directory: test
+  records-per-second: 5000
+throttle:
+  adaptive:
+    max-latency-pct: 5
+    max-cpu-pct: 85
+`
+	restoreConfig, err := DecodeRestoreServiceConfig(createTempFile(t, "throttle_adaptive.yaml", adaptiveContent))
+	require.NoError(t, err)
+	require.NotNil(t, restoreConfig.Throttle.Adaptive)
+	require.Equal(t, 5.0, restoreConfig.Throttle.Adaptive.MaxLatencyPct)
+	require.Equal(t, int64(85), restoreConfig.Throttle.Adaptive.MaxCPUPct)
+	require.NoError(t, restoreConfig.Throttle.Validate())
+	require.Zero(t, NewRestoreConfig(restoreConfig, slog.Default()).RecordsPerSecond)
+}
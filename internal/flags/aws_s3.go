@@ -75,10 +75,63 @@ func (f *AwsS3) NewFlagSet() *pflag.FlagSet {
 		models.DefaultS3SecretAccessKey,
 		"S3 secret access key. If not set, profile auth info will be used.")
 
+	flagSet.StringVar(&f.RoleArn, "s3-role-arn",
+		models.DefaultS3RoleArn,
+		"ARN of an IAM role to assume with STS, e.g. a role in the central backup account.\n"+
+			"The role is assumed with the access keys, profile or default credentials, and temporary\n"+
+			"credentials are refreshed before they expire.")
+
+	flagSet.StringVar(&f.ExternalID, "s3-external-id",
+		models.DefaultS3ExternalID,
+		"External ID to use when assuming the --s3-role-arn role.")
+
+	flagSet.StringVar(&f.SessionName, "s3-session-name",
+		models.DefaultS3SessionName,
+		"Session name to use when assuming the --s3-role-arn role. If not set, it is generated.")
+
+	flagSet.StringVar(&f.WebIdentityTokenFile, "s3-web-identity-token-file",
+		models.DefaultS3WebIdentityToken,
+		"Path to a file with an OIDC token, e.g. a Kubernetes service account token.\n"+
+			"If set, the --s3-role-arn role is assumed with the web identity token instead of other credentials.")
+
 	flagSet.StringVar(&f.Endpoint, "s3-endpoint-override",
 		models.DefaultS3Endpoint,
 		"An alternate URL endpoint to send S3 API calls to.")
 
+	flagSet.StringVar(&f.AddressingStyle, "s3-addressing-style",
+		models.DefaultS3AddressingStyle,
+		"Addressing style of S3 requests:\n"+
+			"path - the bucket name is in the path, e.g. https://s3.amazonaws.com/bucket/key,\n"+
+			"virtual-hosted - the bucket name is in the host name, e.g. https://bucket.s3.amazonaws.com/key.")
+
+	var descSSECustomerKey string
+
+	switch f.operation {
+	case OperationBackup:
+		flagSet.StringVar(&f.SSE, "s3-sse",
+			models.DefaultS3SSE,
+			"Server-side encryption of uploaded objects. Types are:\n"+
+				"AES256 - keys managed by S3 (SSE-S3),\n"+
+				"aws:kms - keys managed by AWS KMS (SSE-KMS),\n"+
+				"customer - key provided with --s3-sse-customer-key (SSE-C).\n"+
+				"If not set, the default encryption of the bucket is used.")
+
+		flagSet.StringVar(&f.SSEKmsKeyID, "s3-sse-kms-key-id",
+			models.DefaultS3SSEKmsKeyID,
+			"ID, ARN or alias of the KMS key to encrypt objects with, when --s3-sse is aws:kms.\n"+
+				"If not set, the AWS managed key aws/s3 is used.")
+
+		descSSECustomerKey = "Base64-encoded 256-bit key to encrypt objects with, when --s3-sse is customer.\n" +
+			"S3 doesn't store the key, the same key is required to restore the backup."
+	case OperationRestore:
+		descSSECustomerKey = "Base64-encoded 256-bit key, that objects were encrypted with on backup (SSE-C).\n" +
+			"Objects encrypted with S3 or KMS managed keys are decrypted without additional flags."
+	}
+
+	flagSet.StringVar(&f.SSECustomerKey, "s3-sse-customer-key",
+		models.DefaultS3SSECustomerKey,
+		descSSECustomerKey)
+
 	switch f.operation {
 	case OperationBackup:
 		flagSet.StringVar(&f.StorageClass, "s3-storage-class",
@@ -158,6 +211,8 @@ func (f *AwsS3) NewFlagSet() *pflag.FlagSet {
 			"The timeout includes connection time, any redirects, and reading the response body.\n"+
 			"0 means no limit.")
 
+	flagSet.AddFlagSet(newStorageTransportFlagSet(&f.StorageCommon, "s3"))
+
 	return flagSet
 }
 
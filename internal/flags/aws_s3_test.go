@@ -40,6 +40,15 @@ func TestAwsS3_NewFlagSet(t *testing.T) {
 		"--s3-upload-concurrency", "10",
 		"--s3-max-conns-per-host", "10",
 		"--s3-request-timeout", "10",
+		"--s3-addressing-style", "virtual-hosted",
+		"--s3-tls-cafile", "/etc/ssl/ca.pem",
+		"--s3-proxy-url", "http://proxy:3128",
+		"--s3-role-arn", "arn:aws:iam::123456789012:role/backup",
+		"--s3-external-id", "external",
+		"--s3-session-name", "backup",
+		"--s3-web-identity-token-file", "/var/run/secrets/token",
+		"--s3-sse", "aws:kms",
+		"--s3-sse-kms-key-id", "alias/backup",
 	}
 
 	err := flagSet.Parse(args)
@@ -59,6 +68,15 @@ func TestAwsS3_NewFlagSet(t *testing.T) {
 	assert.Equal(t, 10, result.UploadConcurrency, "The s3-upload-concurrency flag should be parsed correctly")
 	assert.Equal(t, 10, result.MaxConnsPerHost, "The s3-max-conns-per-host flag should be parsed correctly")
 	assert.Equal(t, 10, result.RequestTimeout, "The s3-request-timeout flag should be parsed correctly")
+	assert.Equal(t, "virtual-hosted", result.AddressingStyle, "The s3-addressing-style flag should be parsed correctly")
+	assert.Equal(t, "/etc/ssl/ca.pem", result.TLSCAFile, "The s3-tls-cafile flag should be parsed correctly")
+	assert.Equal(t, "http://proxy:3128", result.ProxyURL, "The s3-proxy-url flag should be parsed correctly")
+	assert.Equal(t, "arn:aws:iam::123456789012:role/backup", result.RoleArn, "The s3-role-arn flag should be parsed correctly")
+	assert.Equal(t, "external", result.ExternalID, "The s3-external-id flag should be parsed correctly")
+	assert.Equal(t, "backup", result.SessionName, "The s3-session-name flag should be parsed correctly")
+	assert.Equal(t, "/var/run/secrets/token", result.WebIdentityTokenFile, "The s3-web-identity-token-file flag should be parsed correctly")
+	assert.Equal(t, "aws:kms", result.SSE, "The s3-sse flag should be parsed correctly")
+	assert.Equal(t, "alias/backup", result.SSEKmsKeyID, "The s3-sse-kms-key-id flag should be parsed correctly")
 
 	awsS3 = NewAwsS3(OperationRestore)
 	flagSet = awsS3.NewFlagSet()
@@ -66,6 +84,7 @@ func TestAwsS3_NewFlagSet(t *testing.T) {
 		"--s3-retry-read-backoff", "900",
 		"--s3-retry-read-multiplier", "1.5",
 		"--s3-retry-read-max-attempts", "5",
+		"--s3-sse-customer-key", "c2VjcmV0",
 	}
 
 	err = flagSet.Parse(args)
@@ -76,6 +95,7 @@ func TestAwsS3_NewFlagSet(t *testing.T) {
 	assert.Equal(t, 900, result.RetryReadBackoff, "The s3-retry-read-backoff flag should be parsed correctly")
 	assert.Equal(t, 1.5, result.RetryReadMultiplier, "The s3-retry-read-multiplier flag should be parsed correctly")
 	assert.Equal(t, uint(5), result.RetryReadMaxAttempts, "The s3-retry-read-max-attempts flag should be parsed correctly")
+	assert.Equal(t, "c2VjcmV0", result.SSECustomerKey, "The s3-sse-customer-key flag should be parsed correctly")
 }
 
 func TestAwsS3_NewFlagSet_DefaultValues(t *testing.T) {
@@ -95,12 +115,18 @@ func TestAwsS3_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.Equal(t, "", result.AccessKeyID, "The default value for s3-access-key-id should be an empty string")
 	assert.Equal(t, "", result.SecretAccessKey, "The default value for s3-secret-access-key should be an empty string")
 	assert.Equal(t, "", result.StorageClass, "The default value for s3-storage-class should be an empty string")
+	assert.Equal(t, "", result.RoleArn, "The default value for s3-role-arn should be an empty string")
+	assert.Equal(t, "", result.WebIdentityTokenFile, "The default value for s3-web-identity-token-file should be an empty string")
+	assert.Equal(t, "", result.SSE, "The default value for s3-sse should be an empty string")
+	assert.Equal(t, "", result.SSECustomerKey, "The default value for s3-sse-customer-key should be an empty string")
 	assert.Equal(t, models.DefaultS3ChunkSize, result.ChunkSize, "The default value for s3-chunk-size should be 5mb")
 	assert.Equal(t, models.DefaultS3RetryMaxAttempts, result.RetryMaxAttempts, "The default value for s3-retry-max-attempts should be 100")
 	assert.Equal(t, models.DefaultS3RetryMaxBackoff, result.RetryMaxBackoff, "The default value for s3-retry-max-backoff should be 90")
 	assert.Equal(t, models.DefaultS3UploadConcurrency, result.UploadConcurrency, "The default value for s3-upload-concurrency should be 1")
 	assert.Equal(t, models.DefaultCloudMaxConnsPerHost, result.MaxConnsPerHost, "The default value for s3-max-conns-per-host should be 0")
 	assert.Equal(t, models.DefaultCloudRequestTimeout, result.RequestTimeout, "The default value for s3-request-timeout should be 0")
+	assert.Equal(t, models.S3AddressingStylePath, result.AddressingStyle, "The default value for s3-addressing-style should be path")
+	assert.Equal(t, "", result.ProxyURL, "The default value for s3-proxy-url should be an empty string")
 
 	awsS3 = NewAwsS3(OperationRestore)
 	flagSet = awsS3.NewFlagSet()
@@ -81,6 +81,24 @@ func (f *AzureBlob) NewFlagSet() *pflag.FlagSet {
 		models.DefaultAzureClientSecret,
 		"Azure client secret for Azure Active Directory authorization.")
 
+	flagSet.StringVar(&f.SASToken, "azure-sas-token",
+		models.DefaultAzureSASToken,
+		"Azure shared access signature token, e.g. a container-scoped SAS issued by the storage owner.")
+
+	flagSet.StringVar(&f.FederatedTokenFile, "azure-federated-token-file",
+		models.DefaultAzureFederatedTokenFile,
+		"Path to the federated token file for workload identity authorization.\n"+
+			"If not set, AZURE_FEDERATED_TOKEN_FILE environment variable is used.")
+
+	flagSet.StringVar(&f.AuthMode, "azure-auth-mode",
+		models.DefaultAzureAuthMode,
+		"Azure authorization mode. Modes are: shared-key, client-secret, managed-identity,\n"+
+			"workload-identity, sas, default. The default mode chains environment, workload identity,\n"+
+			"managed identity and Azure CLI credentials.\n"+
+			"If not set, the mode is detected from the set credentials.\n"+
+			"--azure-client-id selects a user-assigned managed identity in managed-identity mode,\n"+
+			"--azure-tenant-id and --azure-client-id override environment variables in workload-identity mode.")
+
 	flagSet.StringVar(&f.Endpoint, "azure-endpoint",
 		models.DefaultAzureEndpoint,
 		"Azure endpoint.")
@@ -154,6 +172,8 @@ func (f *AzureBlob) NewFlagSet() *pflag.FlagSet {
 			"The timeout includes connection time, any redirects, and reading the response body.\n"+
 			"0 means no limit.")
 
+	flagSet.AddFlagSet(newStorageTransportFlagSet(&f.StorageCommon, "azure"))
+
 	return flagSet
 }
 
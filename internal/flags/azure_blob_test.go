@@ -33,6 +33,9 @@ func TestAzureBlob_NewFlagSetRestore(t *testing.T) {
 		"--azure-tenant-id", "tenant-id",
 		"--azure-client-id", "client-id",
 		"--azure-client-secret", "client-secret",
+		"--azure-sas-token", "sv=2024-01-01&sig=abc",
+		"--azure-federated-token-file", "/var/run/secrets/token",
+		"--azure-auth-mode", "workload-identity",
 		"--azure-endpoint", "https://custom-endpoint.com",
 		"--azure-container-name", "my-container",
 		"--azure-access-tier", "Standard",
@@ -55,6 +58,10 @@ func TestAzureBlob_NewFlagSetRestore(t *testing.T) {
 	assert.Equal(t, "tenant-id", result.TenantID, "The azure-tenant-id flag should be parsed correctly")
 	assert.Equal(t, "client-id", result.ClientID, "The azure-client-id flag should be parsed correctly")
 	assert.Equal(t, "client-secret", result.ClientSecret, "The azure-client-secret flag should be parsed correctly")
+	assert.Equal(t, "sv=2024-01-01&sig=abc", result.SASToken, "The azure-sas-token flag should be parsed correctly")
+	assert.Equal(t, "/var/run/secrets/token", result.FederatedTokenFile,
+		"The azure-federated-token-file flag should be parsed correctly")
+	assert.Equal(t, "workload-identity", result.AuthMode, "The azure-auth-mode flag should be parsed correctly")
 	assert.Equal(t, "https://custom-endpoint.com", result.Endpoint, "The azure-endpoint flag should be parsed correctly")
 	assert.Equal(t, "my-container", result.ContainerName, "The azure-container-name flag should be parsed correctly")
 	assert.Equal(t, "Standard", result.AccessTier, "The azure-access-tier flag should be parsed correctly")
@@ -83,6 +90,8 @@ func TestAzureBlob_NewFlagSet_DefaultValuesRestore(t *testing.T) {
 	assert.Equal(t, "", result.TenantID, "The default value for azure-tenant-id should be an empty string")
 	assert.Equal(t, "", result.ClientID, "The default value for azure-client-id should be an empty string")
 	assert.Equal(t, "", result.ClientSecret, "The default value for azure-client-secret should be an empty string")
+	assert.Equal(t, "", result.SASToken, "The default value for azure-sas-token should be an empty string")
+	assert.Equal(t, "", result.AuthMode, "The default value for azure-auth-mode should be an empty string")
 	assert.Equal(t, "", result.Endpoint, "The default value for azure-endpoint should be an empty string")
 	assert.Equal(t, "", result.ContainerName, "The default value for azure-container-name should be an empty string")
 	assert.Equal(t, "", result.AccessTier, "The default value for azure-access-tier should be an empty string")
@@ -107,6 +116,9 @@ func TestAzureBlob_NewFlagSetBackup(t *testing.T) {
 		"--azure-tenant-id", "tenant-id",
 		"--azure-client-id", "client-id",
 		"--azure-client-secret", "client-secret",
+		"--azure-sas-token", "sv=2024-01-01&sig=abc",
+		"--azure-federated-token-file", "/var/run/secrets/token",
+		"--azure-auth-mode", "workload-identity",
 		"--azure-endpoint", "https://custom-endpoint.com",
 		"--azure-container-name", "my-container",
 		"--azure-access-tier", "Standard",
@@ -114,6 +126,8 @@ func TestAzureBlob_NewFlagSetBackup(t *testing.T) {
 		"--azure-upload-concurrency", "10",
 		"--azure-max-conns-per-host", "10",
 		"--azure-request-timeout", "10",
+		"--azure-tls-cafile", "/etc/ssl/ca.pem",
+		"--azure-proxy-url", "https://proxy:3128",
 	}
 
 	err := flagSet.Parse(args)
@@ -126,6 +140,10 @@ func TestAzureBlob_NewFlagSetBackup(t *testing.T) {
 	assert.Equal(t, "tenant-id", result.TenantID, "The azure-tenant-id flag should be parsed correctly")
 	assert.Equal(t, "client-id", result.ClientID, "The azure-client-id flag should be parsed correctly")
 	assert.Equal(t, "client-secret", result.ClientSecret, "The azure-client-secret flag should be parsed correctly")
+	assert.Equal(t, "sv=2024-01-01&sig=abc", result.SASToken, "The azure-sas-token flag should be parsed correctly")
+	assert.Equal(t, "/var/run/secrets/token", result.FederatedTokenFile,
+		"The azure-federated-token-file flag should be parsed correctly")
+	assert.Equal(t, "workload-identity", result.AuthMode, "The azure-auth-mode flag should be parsed correctly")
 	assert.Equal(t, "https://custom-endpoint.com", result.Endpoint, "The azure-endpoint flag should be parsed correctly")
 	assert.Equal(t, "my-container", result.ContainerName, "The azure-container-name flag should be parsed correctly")
 	assert.Equal(t, "Standard", result.AccessTier, "The azure-access-tier flag should be parsed correctly")
@@ -133,6 +151,8 @@ func TestAzureBlob_NewFlagSetBackup(t *testing.T) {
 	assert.Equal(t, 10, result.UploadConcurrency, "The azure-upload-concurrency flag should be parsed correctly")
 	assert.Equal(t, 10, result.MaxConnsPerHost, "The azure-max-conns-per-host flag should be parsed correctly")
 	assert.Equal(t, 10, result.RequestTimeout, "The azure-request-timeout flag should be parsed correctly")
+	assert.Equal(t, "/etc/ssl/ca.pem", result.TLSCAFile, "The azure-tls-cafile flag should be parsed correctly")
+	assert.Equal(t, "https://proxy:3128", result.ProxyURL, "The azure-proxy-url flag should be parsed correctly")
 }
 
 func TestAzureBlob_NewFlagSet_DefaultValuesBackup(t *testing.T) {
@@ -151,10 +171,13 @@ func TestAzureBlob_NewFlagSet_DefaultValuesBackup(t *testing.T) {
 	assert.Equal(t, "", result.TenantID, "The default value for azure-tenant-id should be an empty string")
 	assert.Equal(t, "", result.ClientID, "The default value for azure-client-id should be an empty string")
 	assert.Equal(t, "", result.ClientSecret, "The default value for azure-client-secret should be an empty string")
+	assert.Equal(t, "", result.SASToken, "The default value for azure-sas-token should be an empty string")
+	assert.Equal(t, "", result.AuthMode, "The default value for azure-auth-mode should be an empty string")
 	assert.Equal(t, "", result.Endpoint, "The default value for azure-endpoint should be an empty string")
 	assert.Equal(t, "", result.ContainerName, "The default value for azure-container-name should be an empty string")
 	assert.Equal(t, "", result.AccessTier, "The default value for azure-access-tier should be an empty string")
 	assert.Equal(t, models.DefaultAzureBlockSize, result.BlockSize, "The default value for azure-block-size should be 5MB")
 	assert.Equal(t, 0, result.MaxConnsPerHost, "The default value for s3-max-conns-per-host should be 0")
 	assert.Equal(t, models.DefaultCloudRequestTimeout, result.RequestTimeout, "The default value for azure-request-timeout should be 0")
+	assert.Equal(t, "", result.ProxyURL, "The default value for azure-proxy-url should be an empty string")
 }
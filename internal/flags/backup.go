@@ -49,7 +49,7 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 	flagSet.StringVarP(&f.OutputFilePrefix, "output-file-prefix", "q",
 		"",
 		"When using directory parameter, prepend a prefix to the names of the generated files.\n"+
-			"Not applicable when --output-file is used. ")
+			"Not applicable when --output-file is used.\n"+descPathTemplate)
 	flagSet.Uint64VarP(&f.FileLimit, "file-limit", "F",
 		models.DefaultBackupFileLimit,
 		"Rotate backup files when their size crosses the given\n"+
@@ -133,6 +133,36 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 			"Unlike --prefer-racks, only specified racks will be backed up.\n"+
 			"This argument is mutually exclusive with --prefer-racks and --node-list.")
 
+	flagSet.StringVar(&f.BackupRack, "backup-rack",
+		models.DefaultBackupBackupRack,
+		"<rack id>\n"+
+			"Pins the backup to a single rack. Records are read only from replicas on nodes of this rack,\n"+
+			"one node after another. Records and bytes backed up from each node and rack are reported.\n"+
+			"This argument is mutually exclusive with --prefer-racks, --rack-list, --node-list,\n"+
+			"--partition-list, --after-digest, --output-file, --continue and --state-file-dst.")
+
+	flagSet.StringVar(&f.RackFallback, "rack-fallback",
+		models.DefaultBackupRackFallback,
+		"Policy that is applied when a node of the --backup-rack is lost:\n"+
+			"fail - the backup fails.\n"+
+			"wait - waits for migrations to finish, then partitions of the lost node are read from\n"+
+			"       the nodes left in the backup rack.\n"+
+			"other-rack - partitions of the lost node are read from replicas on other racks.")
+
+	flagSet.StringVar(&f.Shard, "shard",
+		models.DefaultBackupShard,
+		"<shard>/<shards>\n"+
+			"Backs up one shard of a distributed backup, e.g. 1/4. Each shard process\n"+
+			"backs up its own range of partitions to the same directory, with its own file prefix.\n"+
+			"When a shard is finished, its completion marker is written, so restore can check that all shards are present.\n"+
+			"Indexes and udfs are backed up only by the first shard.\n"+
+			"This argument is mutually exclusive with --partition-list, --after-digest, --node-list, --rack-list,\n"+
+			"--backup-rack, --output-file, --remove-files and --remove-artifacts.")
+
+	flagSet.Int64Var(&f.RackWaitTimeout, "rack-wait-timeout",
+		models.DefaultBackupRackWaitTimeout,
+		"Time in milliseconds to wait for migrations to finish with --rack-fallback wait.")
+
 	flagSet.Int64VarP(&f.MaxRecords, "max-records", "M",
 		models.DefaultBackupMaxRecords,
 		"The number of records approximately to back up. 0 - all records")
@@ -161,6 +191,7 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 	flagSet.StringVar(&f.StateFileDst, "state-file-dst",
 		models.DefaultBackupStateFileDst,
 		"Name of a state file that will be saved in backup --directory.\n"+
+			"If not set, the state is saved to "+models.StateFileName+", unless --no-state is set.\n"+
 			"Works only with --file-limit parameter. As --file-limit is reached and the file is closed,\n"+
 			"the current state will be saved. Works only for default and/or partition backup.\n"+
 			"Not work with --rack-list or --node--list.")
@@ -175,7 +206,45 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 		models.DefaultBackupScanPageSize,
 		"Number of records will be read on one iteration for continuation backup.\n"+
 			"Affects size if overlap on resuming backup after an error.\n"+
-			"Used only with --state-file-dst or --continue.")
+			"Used only when the state file is saved, that is disabled by --no-state.")
+
+	flagSet.BoolVar(&f.Resume, "resume",
+		models.DefaultBackupResume,
+		"Resumes an interrupted/failed backup from the state file in backup --directory, if it exists.\n"+
+			"Otherwise, a new backup is started. The state file is "+models.StateFileName+", or --state-file-dst if set.\n"+
+			"Allows restarting a killed backup with the same arguments.\n"+
+			"--resume and --continue are mutually exclusive.")
+
+	flagSet.BoolVar(&f.NoState, "no-state",
+		models.DefaultBackupNoState,
+		"Disables the state file, that is saved by default in backup --directory as "+models.StateFileName+".\n"+
+			"Without the state file, records are read without pagination, but the backup can't be resumed.")
+
+	flagSet.IntVar(&f.RetentionDays, "retention-days",
+		models.DefaultBackupRetentionDays,
+		"Number of days backup files can't be removed or overwritten, e.g. for ransomware protection.\n"+
+			"Sets S3 Object Lock retention or Azure Blob immutability policy on each backup file.\n"+
+			"The bucket must have Object Lock enabled, the container must have version-level immutability enabled.\n"+
+			"If 0, the retention is not set.")
+
+	flagSet.StringVar(&f.RetentionMode, "retention-mode",
+		models.DefaultBackupRetentionMode,
+		"Mode of the --retention-days retention:\n"+
+			"governance - users with special permissions can remove or shorten the retention (Azure unlocked policy),\n"+
+			"compliance - no one can remove or shorten the retention (Azure locked policy).")
+
+	flagSet.BoolVar(&f.LegalHold, "legal-hold",
+		models.DefaultBackupLegalHold,
+		"Sets a legal hold on each backup file, files can't be removed until the hold is released.")
+
+	flagSet.BoolVar(&f.PreflightOnly, "preflight-only",
+		models.DefaultBackupPreflightOnly,
+		"Check the storage and exit without the backup. The check writes, reads, lists and deletes a probe file\n"+
+			"in the backup directory, and compares free local disk space with the projected backup size.")
+
+	flagSet.BoolVar(&f.SkipPreflight, "skip-preflight",
+		models.DefaultBackupSkipPreflight,
+		"Skip the storage check, that is run before the backup is started.")
 
 	return flagSet
 }
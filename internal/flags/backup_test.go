@@ -44,6 +44,15 @@ func TestBackup_NewFlagSet(t *testing.T) {
 		"--no-ttl-only",
 		"--prefer-racks", "1,2,3,4",
 		"--rack-list", "1,2,3,4",
+		"--backup-rack", "2",
+		"--rack-fallback", "wait",
+		"--rack-wait-timeout", "1000",
+		"--shard", "1/4",
+		"--resume",
+		"--retention-days", "30",
+		"--retention-mode", "compliance",
+		"--legal-hold",
+		"--preflight-only",
 		"--partition-list", "4000,1-236,EjRWeJq83vEjRRI0VniavN7xI0U=",
 	}
 
@@ -67,6 +76,16 @@ func TestBackup_NewFlagSet(t *testing.T) {
 	assert.Equal(t, true, result.NoTTLOnly, "The no-ttl-only flag should be parsed correctly")
 	assert.Equal(t, "1,2,3,4", result.PreferRacks, "The prefer-racks flag should be parsed correctly")
 	assert.Equal(t, "1,2,3,4", result.RackList, "The rack-list flag should be parsed correctly")
+	assert.Equal(t, "2", result.BackupRack, "The backup-rack flag should be parsed correctly")
+	assert.Equal(t, "wait", result.RackFallback, "The rack-fallback flag should be parsed correctly")
+	assert.Equal(t, int64(1000), result.RackWaitTimeout, "The rack-wait-timeout flag should be parsed correctly")
+	assert.Equal(t, "1/4", result.Shard, "The shard flag should be parsed correctly")
+	assert.True(t, result.Resume, "The resume flag should be parsed correctly")
+	assert.False(t, result.NoState, "The no-state flag should be false when not set")
+	assert.Equal(t, 30, result.RetentionDays, "The retention-days flag should be parsed correctly")
+	assert.Equal(t, "compliance", result.RetentionMode, "The retention-mode flag should be parsed correctly")
+	assert.True(t, result.LegalHold, "The legal-hold flag should be parsed correctly")
+	assert.True(t, result.PreflightOnly, "The preflight-only flag should be parsed correctly")
 	assert.Equal(t, "4000,1-236,EjRWeJq83vEjRRI0VniavN7xI0U=", result.PartitionList, "The partition-list flag should be parsed correctly")
 	assert.Equal(t, 3, result.MaxRetries, "The max-retries flag should be parsed correctly")
 }
@@ -98,6 +117,17 @@ func TestBackup_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.Equal(t, false, result.NoTTLOnly, "The default value for no-ttl-only should be false")
 	assert.Equal(t, "", result.PreferRacks, "The default value for prefer-racks should be empty string")
 	assert.Equal(t, "", result.RackList, "The default value for rack list should be empty string")
+	assert.Equal(t, "", result.BackupRack, "The default value for backup-rack should be empty string")
+	assert.Equal(t, "fail", result.RackFallback, "The default value for rack-fallback should be fail")
+	assert.Equal(t, int64(600000), result.RackWaitTimeout, "The default value for rack-wait-timeout should be 600000")
+	assert.Equal(t, "", result.Shard, "The default value for shard should be empty string")
+	assert.False(t, result.Resume, "The default value for resume should be false")
+	assert.False(t, result.NoState, "The default value for no-state should be false")
+	assert.Equal(t, 0, result.RetentionDays, "The default value for retention-days should be 0")
+	assert.Equal(t, "governance", result.RetentionMode, "The default value for retention-mode should be governance")
+	assert.False(t, result.LegalHold, "The default value for legal-hold should be false")
+	assert.False(t, result.PreflightOnly, "The default value for preflight-only should be false")
+	assert.False(t, result.SkipPreflight, "The default value for skip-preflight should be false")
 	assert.Equal(t, "", result.PartitionList, "The default value for partition-list should be empty string")
 	assert.Equal(t, 5, result.MaxRetries, "The default value for max-retries should be 5")
 }
@@ -36,7 +36,7 @@ func (f *BackupXDR) NewFlagSet() *pflag.FlagSet {
 
 	flagSet.StringVarP(&f.Directory, "directory", "d",
 		models.DefaultBackupXDRDirectory,
-		"The directory that holds the backup files. Required.")
+		"The directory that holds the backup files. Required.\n"+descStorageURL+"\n"+descPathTemplate)
 
 	flagSet.BoolVarP(&f.RemoveFiles, "remove-files", "r",
 		models.DefaultBackupXDRRemoveFiles,
@@ -147,6 +147,27 @@ func (f *BackupXDR) NewFlagSet() *pflag.FlagSet {
 		"Set the timeout (ms) for asinfo commands sent from abs-backup-cli to the database.\n"+
 			"The info commands are to check version, get indexes, get udfs, count records, and check batch write support.")
 
+	flagSet.DurationVar(&f.RotateInterval, "rotate-interval",
+		models.DefaultBackupXDRRotateInterval,
+		"Keep the backup running and rotate it every interval, e.g. 15m.\n"+
+			"Each rotation is written to a new segment directory. When it is finished, a checkpoint\n"+
+			"with the time until which all changes are saved is written to the backup directory.\n"+
+			"After a restart, the backup continues from the checkpoint, --rewind is used only for the first segment.\n"+
+			"If 0, the backup finishes after the first run.")
+
+	flagSet.BoolVar(&f.Scan, "scan",
+		models.DefaultBackupXDRScan,
+		"Take a scan backup after the first segment of a continuous backup, for point-in-time recovery.\n"+
+			"The scan backup is written to the scan directory, and the handover point between it and\n"+
+			"the segments is saved to the checkpoint. Existing records are in the scan backup, so --rewind is ignored.\n"+
+			"Requires --rotate-interval.")
+
+	flagSet.StringVar(&f.CleanupStateFile, "cleanup-state-file",
+		models.DefaultBackupXDRCleanupStateFile,
+		"Local file where the DC and MRT writes blocking configured on the database are saved.\n"+
+			"If the backup is interrupted, they are undone on the next start, or by the xdr cleanup command.\n"+
+			"If not set, a file named after the DC and the namespace in the temp directory is used.")
+
 	return flagSet
 }
 
@@ -45,6 +45,7 @@ func TestBackupXDR_NewFlagSet(t *testing.T) {
 		"--stop-xdr",
 		"--max-throughput", "1000",
 		"--info-timeout", "1000",
+		"--cleanup-state-file", "/tmp/xdr.json",
 	}
 
 	err := flagSet.Parse(args)
@@ -69,6 +70,7 @@ func TestBackupXDR_NewFlagSet(t *testing.T) {
 	assert.Equal(t, true, result.StopXDR, "The stop flag should be parsed correctly")
 	assert.Equal(t, 1000, result.MaxThroughput, "The max-throughput flag should be parsed correctly")
 	assert.Equal(t, int64(1000), result.InfoTimeout, "The info-timeout flag should be parsed correctly")
+	assert.Equal(t, "/tmp/xdr.json", result.CleanupStateFile, "The cleanup-state-file flag should be parsed correctly")
 }
 
 func TestBackupXDR_NewFlagSet_DefaultValues(t *testing.T) {
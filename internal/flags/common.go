@@ -28,8 +28,13 @@ const (
 	descNamespaceBackup  = "The namespace to be backed up. Required."
 	descNamespaceRestore = "Used to restore to a different namespace. Example: source-ns,destination-ns"
 
-	descDirectoryBackup  = "The directory that holds the backup files. Required, unless -o or -e is used."
-	descDirectoryRestore = "The directory that holds the backup files. Required, unless --input-file is used."
+	descDirectoryBackup = "The directory that holds the backup files. Required, unless -o or -e is used.\n" +
+		descStorageURL + "\n" + descPathTemplate
+	descDirectoryRestore = "The directory that holds the backup files. Required, unless --input-file is used.\n" +
+		descStorageURL
+	descStorageURL   = "Can be a storage URL: s3://bucket/path, gs://bucket/path, az://container/path or file:///path."
+	descPathTemplate = "Can contain placeholders, that are replaced on start: {{.Namespace}}, {{.Hostname}}\n" +
+		"and {{.Date \"2006-01-02\"}} with a Go time layout."
 
 	descSetListBackup = "The set(s) to be backed up. Accepts comma-separated values with no spaces: 'set1,set2,set3'\n" +
 		"If multiple sets are being backed up, filter-exp cannot be used.\n" +
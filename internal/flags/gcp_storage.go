@@ -35,7 +35,14 @@ func (f *GcpStorage) NewFlagSet() *pflag.FlagSet {
 
 	flagSet.StringVar(&f.KeyFile, "gcp-key-path",
 		models.DefaultGcpKeyFile,
-		"Path to file containing service account JSON key.")
+		"Path to file containing service account JSON key, or other credentials JSON,\n"+
+			"e.g. external account config for workload identity federation.\n"+
+			"If not set, application default credentials are used, unless --gcp-endpoint-override is set.")
+
+	flagSet.StringVar(&f.ImpersonateServiceAccount, "gcp-impersonate-service-account",
+		models.DefaultGcpImpersonateAccount,
+		"Email of the service account to impersonate. Base credentials must be allowed\n"+
+			"to create tokens for it, e.g. with the Service Account Token Creator role.")
 
 	flagSet.StringVar(&f.BucketName, "gcp-bucket-name",
 		models.DefaultGcpBucketName,
@@ -103,6 +110,8 @@ func (f *GcpStorage) NewFlagSet() *pflag.FlagSet {
 			"The timeout includes connection time, any redirects, and reading the response body.\n"+
 			"0 means no limit.")
 
+	flagSet.AddFlagSet(newStorageTransportFlagSet(&f.StorageCommon, "gcp"))
+
 	return flagSet
 }
 
@@ -30,6 +30,7 @@ func TestGcpStorage_NewFlagSetBackup(t *testing.T) {
 
 	args := []string{
 		"--gcp-key-path", "/path/to/keyfile.json",
+		"--gcp-impersonate-service-account", "backup@project.iam.gserviceaccount.com",
 		"--gcp-bucket-name", "my-bucket",
 		"--gcp-endpoint-override", "https://gcp.custom-endpoint.com",
 		"--gcp-chunk-size", "1",
@@ -39,6 +40,8 @@ func TestGcpStorage_NewFlagSetBackup(t *testing.T) {
 		"--gcp-retry-backoff-multiplier", "10",
 		"--gcp-max-conns-per-host", "10",
 		"--gcp-request-timeout", "10",
+		"--gcp-tls-certfile", "/etc/ssl/cert.pem",
+		"--gcp-tls-keyfile", "/etc/ssl/key.pem",
 	}
 
 	err := flagSet.Parse(args)
@@ -47,6 +50,8 @@ func TestGcpStorage_NewFlagSetBackup(t *testing.T) {
 	result := gcpStorage.GetGcpStorage()
 
 	assert.Equal(t, "/path/to/keyfile.json", result.KeyFile, "The gcp-key-path flag should be parsed correctly")
+	assert.Equal(t, "backup@project.iam.gserviceaccount.com", result.ImpersonateServiceAccount,
+		"The gcp-impersonate-service-account flag should be parsed correctly")
 	assert.Equal(t, "my-bucket", result.BucketName, "The gcp-bucket-name flag should be parsed correctly")
 	assert.Equal(t, "https://gcp.custom-endpoint.com", result.Endpoint, "The gcp-endpoint-override flag should be parsed correctly")
 	assert.Equal(t, 1, result.ChunkSize, "The gcp-chunk-size flag should be parsed correctly")
@@ -56,6 +61,8 @@ func TestGcpStorage_NewFlagSetBackup(t *testing.T) {
 	assert.Equal(t, float64(10), result.RetryBackoffMultiplier, "The gcp-retry-backoff-multiplier flag should be parsed correctly")
 	assert.Equal(t, 10, result.MaxConnsPerHost, "The gcp-max-conns-per-host flag should be parsed correctly")
 	assert.Equal(t, 10, result.RequestTimeout, "The gcp-request-timeout flag should be parsed correctly")
+	assert.Equal(t, "/etc/ssl/cert.pem", result.TLSCertFile, "The gcp-tls-certfile flag should be parsed correctly")
+	assert.Equal(t, "/etc/ssl/key.pem", result.TLSKeyFile, "The gcp-tls-keyfile flag should be parsed correctly")
 }
 
 func TestGcpStorage_NewFlagSetRestore(t *testing.T) {
@@ -94,6 +101,8 @@ func TestGcpStorage_NewFlagSet_DefaultValuesBackup(t *testing.T) {
 	result := gcpStorage.GetGcpStorage()
 
 	assert.Equal(t, "", result.KeyFile, "The default value for gcp-key-path should be an empty string")
+	assert.Equal(t, "", result.ImpersonateServiceAccount,
+		"The default value for gcp-impersonate-service-account should be an empty string")
 	assert.Equal(t, "", result.BucketName, "The default value for gcp-bucket-name should be an empty string")
 	assert.Equal(t, "", result.Endpoint, "The default value for gcp-endpoint-override should be an empty string")
 	assert.Equal(t, models.DefaultGcpChunkSize, result.ChunkSize, "The default value for gcp-chunk-size should be 5MB")
@@ -103,6 +112,7 @@ func TestGcpStorage_NewFlagSet_DefaultValuesBackup(t *testing.T) {
 	assert.Equal(t, models.DefaultGcpRetryBackoffMultiplier, result.RetryBackoffMultiplier, "The default value for gcp-retry-backoff-multiplier should be 2")
 	assert.Equal(t, models.DefaultCloudMaxConnsPerHost, result.MaxConnsPerHost, "The default value for gcp-max-conns-per-host should be 0")
 	assert.Equal(t, models.DefaultCloudRequestTimeout, result.RequestTimeout, "The default value for gcp-request-timeout should be 0")
+	assert.Equal(t, "", result.TLSCAFile, "The default value for gcp-tls-cafile should be an empty string")
 }
 
 func TestGcpStorage_NewFlagSet_DefaultValuesRestore(t *testing.T) {
@@ -0,0 +1,59 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type Inspect struct {
+	models.Inspect
+}
+
+func NewInspect() *Inspect {
+	return &Inspect{}
+}
+
+func (f *Inspect) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVarP(&f.Directory, "directory", "d",
+		models.DefaultInspectDirectory,
+		"The directory that holds the .asbx files. If it contains a checkpoint of a continuous xdr backup,\n"+
+			"the segments of the checkpoint are inspected in order.\n"+descStorageURL)
+
+	flagSet.StringVarP(&f.InputFile, "input-file", "i",
+		models.DefaultInspectInputFile,
+		"The .asbx file to inspect.")
+
+	flagSet.DurationVar(&f.HistogramInterval, "histogram-interval",
+		models.DefaultInspectHistogramInterval,
+		"Width of the buckets of the histogram of changes over time, e.g. 5m.")
+
+	flagSet.BoolVar(&f.Dump, "dump",
+		models.DefaultInspectDump,
+		"Print each change record as a JSON line to stdout.")
+
+	flagSet.Int64Var(&f.DumpLimit, "dump-limit",
+		models.DefaultInspectDumpLimit,
+		"Maximum number of change records printed by --dump. If 0, all records are printed.")
+
+	return flagSet
+}
+
+func (f *Inspect) GetInspect() *models.Inspect {
+	return &f.Inspect
+}
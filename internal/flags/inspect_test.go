@@ -0,0 +1,81 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect_NewFlagSet(t *testing.T) {
+	t.Parallel()
+
+	inspect := NewInspect()
+
+	flagSet := inspect.NewFlagSet()
+
+	args := []string{
+		"--directory", "/backup/dir",
+		"--histogram-interval", "5m",
+		"--dump",
+		"--dump-limit", "100",
+	}
+
+	err := flagSet.Parse(args)
+	assert.NoError(t, err)
+
+	result := inspect.GetInspect()
+
+	assert.Equal(t, "/backup/dir", result.Directory, "The directory flag should be parsed correctly")
+	assert.Equal(t, 5*time.Minute, result.HistogramInterval, "The histogram-interval flag should be parsed correctly")
+	assert.True(t, result.Dump, "The dump flag should be parsed correctly")
+	assert.Equal(t, int64(100), result.DumpLimit, "The dump-limit flag should be parsed correctly")
+}
+
+func TestInspect_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+
+	inspect := NewInspect()
+
+	flagSet := inspect.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	assert.NoError(t, err)
+
+	result := inspect.GetInspect()
+
+	assert.Empty(t, result.Directory, "The default value for directory should be empty")
+	assert.Empty(t, result.InputFile, "The default value for input-file should be empty")
+	assert.Equal(t, models.DefaultInspectHistogramInterval, result.HistogramInterval,
+		"The default value for histogram-interval should be 1m")
+	assert.False(t, result.Dump, "The default value for dump should be false")
+	assert.Equal(t, int64(0), result.DumpLimit, "The default value for dump-limit should be 0")
+}
+
+func TestInspect_NewFlagSet_ShortFlags(t *testing.T) {
+	t.Parallel()
+
+	inspect := NewInspect()
+
+	flagSet := inspect.NewFlagSet()
+
+	err := flagSet.Parse([]string{"-i", "0_test_1.asbx"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "0_test_1.asbx", inspect.GetInspect().InputFile, "The input-file flag should be parsed correctly")
+}
@@ -31,9 +31,32 @@ func NewLocal(operation int) *Local {
 func (f *Local) NewFlagSet() *pflag.FlagSet {
 	flagSet := &pflag.FlagSet{}
 
-	flagSet.IntVar(&f.BufferSize, "local-buffer-size",
-		models.DefaultLocalBufferSize,
-		"Buffer size in megabytes for local file writes.")
+	switch f.operation {
+	case OperationBackup:
+		flagSet.IntVar(&f.BufferSize, "local-buffer-size",
+			models.DefaultLocalBufferSize,
+			"Buffer size in megabytes for local file writes.")
+	case OperationRestore:
+		flagSet.IntVar(&f.BufferSize, "local-buffer-size",
+			models.DefaultLocalBufferSize,
+			"Buffer size in megabytes for local file reads.")
+
+		flagSet.IntVar(&f.ReadAhead, "local-read-ahead",
+			models.DefaultLocalReadAhead,
+			"Number of buffers of local-buffer-size to read in advance for each file.\n"+
+				"Reading is done in the background while records are restored. 0 disables read-ahead.")
+
+		flagSet.BoolVar(&f.DropCache, "local-drop-cache",
+			models.DefaultLocalDropCache,
+			"Advise the kernel to read files sequentially and to drop the read data from the page cache,\n"+
+				"so a large restore doesn't evict other data. Files are still read through the page cache,\n"+
+				"this is not O_DIRECT. Only supported on Linux.")
+
+		flagSet.IntVar(&f.OpenConcurrency, "local-open-concurrency",
+			models.DefaultLocalOpenConcurrency,
+			"Number of files opened and prefetched ahead of restore workers.\n"+
+				"0 means that files are opened only when a worker needs one.")
+	}
 
 	return flagSet
 }
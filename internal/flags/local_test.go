@@ -52,3 +52,44 @@ func TestLocal_NewFlagSet_DefaultValues(t *testing.T) {
 
 	assert.Equal(t, models.DefaultLocalBufferSize, result.BufferSize, "The default value for local-buffer-size should be DefaultChunkSize")
 }
+
+func TestLocal_NewFlagSet_Restore(t *testing.T) {
+	t.Parallel()
+	local := NewLocal(OperationRestore)
+
+	flagSet := local.NewFlagSet()
+
+	args := []string{
+		"--local-buffer-size", "16",
+		"--local-read-ahead", "4",
+		"--local-drop-cache",
+		"--local-open-concurrency", "2",
+	}
+
+	err := flagSet.Parse(args)
+	assert.NoError(t, err)
+
+	result := local.GetLocal()
+
+	assert.Equal(t, 16, result.BufferSize, "The local-buffer-size flag should be parsed correctly")
+	assert.Equal(t, 4, result.ReadAhead, "The local-read-ahead flag should be parsed correctly")
+	assert.True(t, result.DropCache, "The local-drop-cache flag should be parsed correctly")
+	assert.Equal(t, 2, result.OpenConcurrency, "The local-open-concurrency flag should be parsed correctly")
+}
+
+func TestLocal_NewFlagSet_RestoreDefaultValues(t *testing.T) {
+	t.Parallel()
+	local := NewLocal(OperationRestore)
+
+	flagSet := local.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	assert.NoError(t, err)
+
+	result := local.GetLocal()
+
+	assert.Equal(t, models.DefaultLocalBufferSize, result.BufferSize)
+	assert.Equal(t, models.DefaultLocalReadAhead, result.ReadAhead)
+	assert.Equal(t, models.DefaultLocalDropCache, result.DropCache)
+	assert.Equal(t, models.DefaultLocalOpenConcurrency, result.OpenConcurrency)
+}
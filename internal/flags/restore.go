@@ -135,13 +135,13 @@ func (f *Restore) NewFlagSet() *pflag.FlagSet {
 		"Set the maximum number of retry attempts for the errors listed under --retry-base-interval.\n"+
 			"The default is 0, indicating no retries will be performed")
 
-	// Disabled mode flag until .asbx support will be added.
-	// flagSet.StringVar(&f.Mode, "mode",
-	// 	"auto",
-	// 	"Restore mode: auto, asb. According to this parameter different restore processes wil be started.\n"+
-	// 		"auto - starts restoring from both .asb and .asbx files.\n"+
-	// 		"asb - restore only .asb backup files.\n"+
-	// 		"asbx - restore only .asbx backup files.")
+	flagSet.StringVar(&f.Mode, "mode",
+		models.DefaultRestoreMode,
+		"Restore mode: auto, asb, asbx. According to this parameter different restore processes wil be started.\n"+
+			"auto - starts restoring from both .asb and .asbx files, .asbx files are replayed after .asb files.\n"+
+			"With --input-file, the mode is detected by the file extension. Stdin is restored as asb.\n"+
+			"asb - restore only .asb backup files.\n"+
+			"asbx - restore only .asbx backup files.")
 
 	flagSet.BoolVar(&f.ValidateOnly, "validate",
 		models.DefaultRestoreValidateOnly,
@@ -152,6 +152,32 @@ func (f *Restore) NewFlagSet() *pflag.FlagSet {
 		"Defines when to restore metadata (secondary indexes and UDFs).\n"+
 			"If set to true, metadata from separate file will be restored after all records have been processed.")
 
+	flagSet.StringVar(&f.PointInTime, "point-in-time",
+		models.DefaultRestorePointInTime,
+		"Restore a continuous xdr backup to the state at the given time, in RFC3339 format, e.g. 2024-05-01T10:00:00Z.\n"+
+			"The scan backup is restored first, then segments that were finished before the given time are replayed.\n"+
+			"The actual restored point is logged, it is not later than the given time.\n"+
+			"Can be used only with --directory.")
+
+	flagSet.StringVar(&f.StateFileDst, "state-file-dst",
+		models.DefaultRestoreStateFileDst,
+		"Name of a state file that will be saved in restore --directory.\n"+
+			"Files are restored in batches, after each --state-interval the restored files and offsets within them\n"+
+			"are saved to the state file. Works only with --directory.\n"+
+			"Not work with --validate, --apply-metadata-last or encryption.")
+
+	flagSet.StringVarP(&f.Continue, "continue", "c",
+		models.DefaultRestoreContinue,
+		"Resumes an interrupted/failed restore from where it was left off, given the state file\n"+
+			"that was saved by the interrupted/failed run. Restored files and records are skipped.\n"+
+			"--continue and --state-file-dst are mutually exclusive.")
+
+	flagSet.Int64Var(&f.StateInterval, "state-interval",
+		models.DefaultRestoreStateInterval,
+		"Interval in milliseconds between state saves. When it is reached, no new files are started\n"+
+			"and the files in progress are stopped after a record, then the state is saved.\n"+
+			"Used only with --state-file-dst or --continue.")
+
 	return flagSet
 }
 
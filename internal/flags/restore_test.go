@@ -39,6 +39,9 @@ func TestRestore_NewFlagSet(t *testing.T) {
 		"--warm-up", "10",
 		"--validate",
 		"--apply-metadata-last",
+		"--mode", "asbx",
+		"--continue", "restore.state",
+		"--state-interval", "30000",
 	}
 
 	err := flagSet.Parse(args)
@@ -57,6 +60,9 @@ func TestRestore_NewFlagSet(t *testing.T) {
 	assert.Equal(t, 10, result.WarmUp, "The warm-up flag should be parsed correctly")
 	assert.Equal(t, true, result.ValidateOnly, "The validate flag should be parsed correctly")
 	assert.Equal(t, true, result.ApplyMetadataLast, "The apply-metadata-last flag should be parsed correctly")
+	assert.Equal(t, "asbx", result.Mode, "The mode flag should be parsed correctly")
+	assert.Equal(t, "restore.state", result.Continue, "The continue flag should be parsed correctly")
+	assert.Equal(t, int64(30000), result.StateInterval, "The state-interval flag should be parsed correctly")
 }
 
 func TestRestore_NewFlagSet_DefaultValues(t *testing.T) {
@@ -78,9 +84,13 @@ func TestRestore_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.Equal(t, 32, result.MaxAsyncBatches, "The default value for max-async-batches should be 32")
 	assert.Equal(t, 128, result.BatchSize, "The default value for batch-size should be 128")
 	assert.Equal(t, int64(0), result.ExtraTTL, "The default value for extra-ttl should be 0")
+	assert.Equal(t, "auto", result.Mode, "The default value for mode should be auto")
 	assert.Equal(t, "", result.DirectoryList, "The directory-list flag should be an empty string")
 	assert.Equal(t, "", result.ParentDirectory, "The parent-directory flag should be an empty string")
 	assert.Equal(t, 0, result.WarmUp, "The warm-up flag should be 0")
 	assert.Equal(t, false, result.ValidateOnly, "The validate flag should be false")
 	assert.Equal(t, false, result.ApplyMetadataLast, "The default value for apply-metadata-last should be false")
+	assert.Equal(t, "", result.StateFileDst, "The default value for state-file-dst should be an empty string")
+	assert.Equal(t, "", result.Continue, "The default value for continue should be an empty string")
+	assert.Equal(t, int64(60000), result.StateInterval, "The default value for state-interval should be 60000")
 }
@@ -0,0 +1,82 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type Sftp struct {
+	operation int
+	models.Sftp
+}
+
+func NewSftp(operation int) *Sftp {
+	return &Sftp{operation: operation}
+}
+
+func (f *Sftp) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVar(&f.Host, "sftp-host",
+		models.DefaultSftpHost,
+		"SSH server host with an optional port, e.g. backup-host:22. Default port is 22.")
+
+	flagSet.StringVar(&f.User, "sftp-user",
+		models.DefaultSftpUser,
+		"User for the SSH connection.")
+
+	flagSet.StringVar(&f.Password, "sftp-password",
+		models.DefaultSftpPassword,
+		"Password for password authorization.")
+
+	flagSet.StringVar(&f.KeyFile, "sftp-key-file",
+		models.DefaultSftpKeyFile,
+		"Path to a private key for public key authorization.")
+
+	flagSet.StringVar(&f.KeyPassphrase, "sftp-key-passphrase",
+		models.DefaultSftpKeyPassphrase,
+		"Passphrase of an encrypted --sftp-key-file.")
+
+	flagSet.BoolVar(&f.UseAgent, "sftp-use-agent",
+		models.DefaultSftpUseAgent,
+		"Authorize with keys of the SSH agent, that is available at SSH_AUTH_SOCK.")
+
+	flagSet.StringVar(&f.KnownHostsFile, "sftp-known-hosts-file",
+		models.DefaultSftpKnownHostsFile,
+		"Path to the known_hosts file, that is used to verify the host key.\n"+
+			"If not set, ~/.ssh/known_hosts is used.")
+
+	flagSet.IntVar(&f.Connections, "sftp-connections",
+		models.DefaultSftpConnections,
+		"Number of SSH connections to the host. Files are transferred in parallel over them.")
+
+	flagSet.IntVar(&f.ConnectTimeout, "sftp-connect-timeout",
+		models.DefaultSftpConnectTimeout,
+		"Timeout (in ms) for establishing an SSH connection. 0 means no limit.")
+
+	if f.operation == OperationBackup {
+		flagSet.IntVar(&f.BufferSize, "sftp-buffer-size",
+			models.DefaultSftpBufferSize,
+			"Buffer size in megabytes for each uploaded file.")
+	}
+
+	return flagSet
+}
+
+func (f *Sftp) GetSftp() *models.Sftp {
+	return &f.Sftp
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSftp_NewFlagSet(t *testing.T) {
+	t.Parallel()
+	sftp := NewSftp(OperationBackup)
+
+	flagSet := sftp.NewFlagSet()
+
+	args := []string{
+		"--sftp-host", "backup-host:2222",
+		"--sftp-user", "backup",
+		"--sftp-password", "password",
+		"--sftp-key-file", "/home/backup/.ssh/id_ed25519",
+		"--sftp-key-passphrase", "passphrase",
+		"--sftp-use-agent",
+		"--sftp-known-hosts-file", "/etc/ssh/known_hosts",
+		"--sftp-connections", "8",
+		"--sftp-connect-timeout", "1000",
+		"--sftp-buffer-size", "10",
+	}
+
+	err := flagSet.Parse(args)
+	assert.NoError(t, err)
+
+	result := sftp.GetSftp()
+
+	assert.Equal(t, "backup-host:2222", result.Host, "The sftp-host flag should be parsed correctly")
+	assert.Equal(t, "backup", result.User, "The sftp-user flag should be parsed correctly")
+	assert.Equal(t, "password", result.Password, "The sftp-password flag should be parsed correctly")
+	assert.Equal(t, "/home/backup/.ssh/id_ed25519", result.KeyFile, "The sftp-key-file flag should be parsed correctly")
+	assert.Equal(t, "passphrase", result.KeyPassphrase, "The sftp-key-passphrase flag should be parsed correctly")
+	assert.True(t, result.UseAgent, "The sftp-use-agent flag should be parsed correctly")
+	assert.Equal(t, "/etc/ssh/known_hosts", result.KnownHostsFile,
+		"The sftp-known-hosts-file flag should be parsed correctly")
+	assert.Equal(t, 8, result.Connections, "The sftp-connections flag should be parsed correctly")
+	assert.Equal(t, 1000, result.ConnectTimeout, "The sftp-connect-timeout flag should be parsed correctly")
+	assert.Equal(t, 10, result.BufferSize, "The sftp-buffer-size flag should be parsed correctly")
+}
+
+func TestSftp_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+	sftp := NewSftp(OperationRestore)
+
+	flagSet := sftp.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	assert.NoError(t, err)
+
+	result := sftp.GetSftp()
+
+	assert.Equal(t, "", result.Host, "The default value for sftp-host should be an empty string")
+	assert.Equal(t, "", result.User, "The default value for sftp-user should be an empty string")
+	assert.False(t, result.UseAgent, "The default value for sftp-use-agent should be false")
+	assert.Equal(t, models.DefaultSftpConnections, result.Connections,
+		"The default value for sftp-connections should be DefaultSftpConnections")
+	assert.Equal(t, models.DefaultSftpConnectTimeout, result.ConnectTimeout,
+		"The default value for sftp-connect-timeout should be DefaultSftpConnectTimeout")
+	assert.Nil(t, flagSet.Lookup("sftp-buffer-size"), "The sftp-buffer-size flag should be defined only for backup")
+}
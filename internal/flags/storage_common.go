@@ -0,0 +1,44 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package flags
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/spf13/pflag"
+)
+
+// newStorageTransportFlagSet returns TLS and proxy flags of a cloud storage, prefixed with the storage name.
+func newStorageTransportFlagSet(s *models.StorageCommon, prefix string) *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVar(&s.TLSCAFile, prefix+"-tls-cafile",
+		models.DefaultCloudTLSCAFile,
+		"Path to a PEM file with CA certificates, that are trusted in addition to system CAs,\n"+
+			"e.g. a private CA of on-prem storage.")
+
+	flagSet.StringVar(&s.TLSCertFile, prefix+"-tls-certfile",
+		models.DefaultCloudTLSCertFile,
+		"Path to a PEM file with the client certificate for mutual TLS.")
+
+	flagSet.StringVar(&s.TLSKeyFile, prefix+"-tls-keyfile",
+		models.DefaultCloudTLSKeyFile,
+		"Path to a PEM file with the key of --"+prefix+"-tls-certfile.")
+
+	flagSet.StringVar(&s.ProxyURL, prefix+"-proxy-url",
+		models.DefaultCloudProxyURL,
+		"URL of the proxy for requests to the storage, e.g. http://proxy:3128.\n"+
+			"If not set, HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are used.")
+
+	return flagSet
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type XDRCleanup struct {
+	models.XDRCleanup
+}
+
+func NewXDRCleanup() *XDRCleanup {
+	return &XDRCleanup{}
+}
+
+func (f *XDRCleanup) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVarP(&f.Namespace, "namespace", "n",
+		models.DefaultBackupXDRNamespace,
+		"The namespace of the xdr backup. Required, if --cleanup-state-file is not set.")
+
+	flagSet.StringVar(&f.DC, "dc",
+		models.DefaultBackupXDRDC,
+		"DC that is created on the database by the xdr backup.")
+
+	flagSet.StringVar(&f.StateFile, "cleanup-state-file",
+		models.DefaultBackupXDRCleanupStateFile,
+		"Cleanup state file of the xdr backup. If set, the namespace and the DC are taken from it.\n"+
+			"If not set, the default state file of the DC and the namespace is used, if it exists.")
+
+	flagSet.BoolVar(&f.DryRun, "dry-run",
+		models.DefaultXDRCleanupDryRun,
+		"Only report the DC and MRT writes blocking that would be removed, without changing the database.")
+
+	flagSet.Int64VarP(&f.InfoTimeout, "info-timeout", "T",
+		models.DefaultBackupXDRInfoTimeout,
+		"Set the timeout (ms) for asinfo commands sent from abs-backup-cli to the database.")
+
+	flagSet.Int64Var(&f.InfoRetryIntervalMilliseconds, "info-retry-interval",
+		models.DefaultBackupXDRInfoRetryInterval,
+		"Set the initial interval for a retry (in ms) when info commands are sent.")
+
+	flagSet.Float64Var(&f.InfoRetriesMultiplier, "info-retry-multiplier",
+		models.DefaultBackupXDRInfoRetriesMultiplier,
+		"Increases the delay between subsequent retry attempts.\n"+
+			"The actual delay is calculated as: info-retry-interval * (info-retry-multiplier ^ attemptNumber)")
+
+	flagSet.UintVar(&f.InfoMaxRetries, "info-max-retries",
+		models.DefaultBackupXDRInfoMaxRetries,
+		"How many times to retry sending info commands before failing.")
+
+	return flagSet
+}
+
+func (f *XDRCleanup) GetXDRCleanup() *models.XDRCleanup {
+	return &f.XDRCleanup
+}
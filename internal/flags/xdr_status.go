@@ -0,0 +1,73 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type XDRStatus struct {
+	models.XDRStatus
+}
+
+func NewXDRStatus() *XDRStatus {
+	return &XDRStatus{}
+}
+
+func (f *XDRStatus) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVarP(&f.Namespace, "namespace", "n",
+		models.DefaultBackupXDRNamespace,
+		"The namespace of the xdr backup. Required.")
+
+	flagSet.StringVar(&f.DC, "dc",
+		models.DefaultBackupXDRDC,
+		"DC that is created on the database by the xdr backup.")
+
+	flagSet.Int64Var(&f.MaxLag, "max-lag",
+		models.DefaultXDRStatusMaxLag,
+		"Maximum lag (in seconds) of the DC on a node. If the lag is greater, the DC is unhealthy.\n"+
+			"If 0, the lag is not checked.")
+
+	flagSet.BoolVar(&f.AllowIdle, "allow-idle",
+		models.DefaultXDRStatusAllowIdle,
+		"Don't fail if the DC doesn't exist on any node.\n"+
+			"Use it for a continuous backup, as the DC exists only while a segment is running.")
+
+	flagSet.Int64VarP(&f.InfoTimeout, "info-timeout", "T",
+		models.DefaultBackupXDRInfoTimeout,
+		"Set the timeout (ms) for asinfo commands sent from abs-backup-cli to the database.")
+
+	flagSet.Int64Var(&f.InfoRetryIntervalMilliseconds, "info-retry-interval",
+		models.DefaultBackupXDRInfoRetryInterval,
+		"Set the initial interval for a retry (in ms) when info commands are sent.")
+
+	flagSet.Float64Var(&f.InfoRetriesMultiplier, "info-retry-multiplier",
+		models.DefaultBackupXDRInfoRetriesMultiplier,
+		"Increases the delay between subsequent retry attempts.\n"+
+			"The actual delay is calculated as: info-retry-interval * (info-retry-multiplier ^ attemptNumber)")
+
+	flagSet.UintVar(&f.InfoMaxRetries, "info-max-retries",
+		models.DefaultBackupXDRInfoMaxRetries,
+		"How many times to retry sending info commands before failing.")
+
+	return flagSet
+}
+
+func (f *XDRStatus) GetXDRStatus() *models.XDRStatus {
+	return &f.XDRStatus
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXDRStatus_NewFlagSet(t *testing.T) {
+	t.Parallel()
+
+	xdrStatus := NewXDRStatus()
+
+	flagSet := xdrStatus.NewFlagSet()
+
+	args := []string{
+		"--namespace", "test-ns",
+		"--dc", "dc1",
+		"--max-lag", "60",
+		"--allow-idle",
+		"--info-timeout", "1000",
+	}
+
+	err := flagSet.Parse(args)
+	assert.NoError(t, err)
+
+	result := xdrStatus.GetXDRStatus()
+
+	assert.Equal(t, "test-ns", result.Namespace, "The namespace flag should be parsed correctly")
+	assert.Equal(t, "dc1", result.DC, "The dc flag should be parsed correctly")
+	assert.Equal(t, int64(60), result.MaxLag, "The max-lag flag should be parsed correctly")
+	assert.True(t, result.AllowIdle, "The allow-idle flag should be parsed correctly")
+	assert.Equal(t, int64(1000), result.InfoTimeout, "The info-timeout flag should be parsed correctly")
+}
+
+func TestXDRStatus_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+
+	xdrStatus := NewXDRStatus()
+
+	flagSet := xdrStatus.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	assert.NoError(t, err)
+
+	result := xdrStatus.GetXDRStatus()
+
+	assert.Equal(t, models.DefaultBackupXDRDC, result.DC, "The default value for dc should be 'dc'")
+	assert.Equal(t, int64(models.DefaultXDRStatusMaxLag), result.MaxLag, "The default value for max-lag should be 0")
+	assert.False(t, result.AllowIdle, "The default value for allow-idle should be false")
+	assert.Equal(t, int64(models.DefaultBackupXDRInfoTimeout), result.InfoTimeout,
+		"The default value for info-timeout should be 10000")
+}
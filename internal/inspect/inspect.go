@@ -0,0 +1,383 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inspect reads .asbx files of an xdr backup and reports the changes they contain.
+package inspect
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/checkpoint"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/logging"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/aerospike-backup-cli/internal/xdrmsg"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	"github.com/aerospike/backup-go/io/storage/common"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Change is a record change shipped by XDR. It is printed as a JSON line by --dump.
+type Change struct {
+	File      string `json:"file"`
+	Namespace string `json:"namespace"`
+	Set       string `json:"set,omitempty"`
+	// Key is set only if the user key is sent with the record.
+	Key    any    `json:"key,omitempty"`
+	Digest string `json:"digest"`
+	// LUT is nil if the message doesn't contain the last update time.
+	LUT        *time.Time `json:"lut,omitempty"`
+	Delete     bool       `json:"delete"`
+	Generation int32      `json:"generation"`
+	TTL        int32      `json:"ttl"`
+	Operations int16      `json:"operations"`
+}
+
+// Service inspects .asbx files in a directory, or in segments of a continuous xdr backup.
+type Service struct {
+	params      *config.InspectServiceConfig
+	sa          *backup.SecretAgentConfig
+	compression *backup.CompressionPolicy
+
+	// dump receives changes printed by --dump.
+	dump   io.Writer
+	dumped int64
+
+	files []logging.InspectFile
+	// histogram contains the number of changes by the bucket start in unix milliseconds.
+	histogram map[int64]uint64
+
+	isLogJSON bool
+
+	logger *slog.Logger
+}
+
+// NewService returns a new inspect Service.
+func NewService(params *config.InspectServiceConfig, logger *slog.Logger) (*Service, error) {
+	if err := params.Inspect.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := config.ValidateStorages(
+		false,
+		params.StoragePaths(),
+		params.AwsS3,
+		params.GcpStorage,
+		params.AzureBlob,
+		params.Sftp,
+		params.Local,
+	); err != nil {
+		return nil, err
+	}
+
+	if params.EncryptionPolicy() != nil {
+		return nil, fmt.Errorf("inspect of encrypted backup is not supported")
+	}
+
+	return &Service{
+		params:      params,
+		sa:          params.SecretAgentConfig(),
+		compression: params.CompressionPolicy(),
+		dump:        os.Stdout,
+		histogram:   make(map[int64]uint64),
+		isLogJSON:   params.App.LogJSON,
+		logger:      logger,
+	}, nil
+}
+
+// Run inspects the files and prints the report.
+// If the directory contains a checkpoint of a continuous xdr backup, its segments are inspected in order.
+func (s *Service) Run(ctx context.Context) error {
+	segments, err := s.segments(ctx)
+	if err != nil {
+		return err
+	}
+
+	if segments == nil {
+		if err = s.inspectDirectory(ctx, "", ""); err != nil {
+			return err
+		}
+	}
+
+	for _, segment := range segments {
+		err = s.inspectDirectory(ctx, path.Join(s.params.Inspect.Directory, segment), segment)
+
+		switch {
+		case errors.Is(err, common.ErrEmptyStorage):
+			// Nothing was changed during the segment.
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to inspect segment %s: %w", segment, err)
+		}
+	}
+
+	logging.ReportInspect(s.files, s.buckets(), s.isLogJSON, s.logger)
+
+	return nil
+}
+
+// segments returns segment directories of a continuous xdr backup, or nil if there is no checkpoint.
+func (s *Service) segments(ctx context.Context) ([]string, error) {
+	if s.params.Inspect.Directory == "" {
+		return nil, nil
+	}
+
+	restoreParams := s.params.ToRestoreServiceConfig("")
+
+	reader, err := storage.NewCheckpointReader(ctx, restoreParams, s.sa, s.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint reader: %w", err)
+	}
+
+	cp, err := checkpoint.Read(ctx, reader, s.params.Inspect.Directory)
+	if err != nil || cp == nil {
+		return nil, err
+	}
+
+	s.logger.Info("inspecting continuous xdr backup",
+		slog.Time("checkpoint", cp.Time),
+		slog.Int("segments", len(cp.Segments)),
+	)
+
+	segments := make([]string, 0, len(cp.Segments))
+	for _, segment := range cp.Segments {
+		segments = append(segments, segment.Directory)
+	}
+
+	return segments, nil
+}
+
+// inspectDirectory inspects .asbx files of the directory. If directory is empty, the configured one is used.
+func (s *Service) inspectDirectory(ctx context.Context, directory, segment string) error {
+	_, reader, err := storage.NewRestoreReader(ctx, s.params.ToRestoreServiceConfig(directory), s.sa, s.logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	filesCh := make(chan bModels.File)
+	errCh := make(chan error, 1)
+
+	go reader.StreamFiles(ctx, filesCh, errCh, nil)
+
+	for {
+		select {
+		case err = <-errCh:
+			return err
+		case file, ok := <-filesCh:
+			if !ok {
+				return nil
+			}
+
+			result, err := s.inspectFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to inspect %s: %w", file.Name, err)
+			}
+
+			result.Segment = segment
+			s.files = append(s.files, *result)
+		}
+	}
+}
+
+// inspectFile decodes the file and adds its changes to the histogram.
+func (s *Service) inspectFile(file bModels.File) (*logging.InspectFile, error) {
+	defer file.Reader.Close()
+
+	name := filepath.Base(file.Name)
+
+	fileNumber, err := fileNumber(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader = file.Reader
+
+	if s.compression != nil {
+		zstdDecoder, err := zstd.NewReader(file.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create decompression reader: %w", err)
+		}
+
+		defer zstdDecoder.Close()
+
+		reader = zstdDecoder
+	}
+
+	decoder, err := asbx.NewDecoder[*bModels.ASBXToken](reader, fileNumber, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &logging.InspectFile{Name: name}
+
+	for {
+		token, err := decoder.NextToken()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return result, nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to decode record %d: %w", result.Records+1, err)
+		}
+
+		change, err := parseChange(token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse record %d: %w", result.Records+1, err)
+		}
+
+		change.File = name
+		s.addChange(result, change)
+
+		if err = s.dumpChange(change); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// addChange adds the change to the file summary and to the histogram.
+func (s *Service) addChange(file *logging.InspectFile, change *Change) {
+	file.Namespace = change.Namespace
+	file.Records++
+
+	if change.Delete {
+		file.Deletes++
+	}
+
+	if change.LUT == nil {
+		return
+	}
+
+	lut := *change.LUT
+
+	if file.FirstLUT.IsZero() || lut.Before(file.FirstLUT) {
+		file.FirstLUT = lut
+	}
+
+	if lut.After(file.LastLUT) {
+		file.LastLUT = lut
+	}
+
+	s.histogram[lut.Truncate(s.params.Inspect.HistogramInterval).UnixMilli()]++
+}
+
+// dumpChange prints the change as a JSON line, if --dump is set.
+func (s *Service) dumpChange(change *Change) error {
+	if !s.params.Inspect.Dump {
+		return nil
+	}
+
+	if s.params.Inspect.DumpLimit > 0 && s.dumped >= s.params.Inspect.DumpLimit {
+		return nil
+	}
+
+	s.dumped++
+
+	if err := json.NewEncoder(s.dump).Encode(change); err != nil {
+		return fmt.Errorf("failed to dump record: %w", err)
+	}
+
+	return nil
+}
+
+// buckets returns the histogram sorted by time.
+func (s *Service) buckets() []logging.InspectBucket {
+	result := make([]logging.InspectBucket, 0, len(s.histogram))
+	for start, records := range s.histogram {
+		result = append(result, logging.InspectBucket{Start: time.UnixMilli(start).UTC(), Records: records})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+
+	return result
+}
+
+// parseChange parses the XDR message stored in the token payload.
+func parseChange(token *bModels.ASBXToken) (*Change, error) {
+	msg, err := xdrmsg.Parse(token.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &Change{
+		Namespace:  token.Key.Namespace(),
+		Digest:     hex.EncodeToString(token.Key.Digest()),
+		Delete:     msg.Info2&xdr.MsgInfo2Delete != 0,
+		Generation: msg.Generation,
+		TTL:        msg.RecordTTL,
+		Operations: msg.NumOps,
+	}
+
+	for _, field := range msg.Fields {
+		if field == nil {
+			continue
+		}
+
+		switch field.Type {
+		case xdr.FieldTypeNamespace:
+			change.Namespace = string(field.Data)
+		case xdr.FieldTypeSet:
+			change.Set = string(field.Data)
+		}
+	}
+
+	if lut, ok := xdrmsg.LUT(msg.Fields); ok {
+		change.LUT = &lut
+	}
+
+	// The key is parsed only to get the user key, records without it are not an error.
+	if key, err := xdr.NewAerospikeKey(msg.Fields); err == nil && key.Value() != nil {
+		change.Key = key.Value().GetObject()
+	}
+
+	return change, nil
+}
+
+// fileNumber returns the number of the file, that is written to the file header.
+// File names are in the <prefix>_<namespace>_<number>.asbx format.
+func fileNumber(name string) (uint64, error) {
+	base := strings.TrimSuffix(name, ".asbx")
+
+	i := strings.LastIndex(base, "_")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid file name %s", name)
+	}
+
+	number, err := strconv.ParseUint(base[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file number in %s: %w", name, err)
+	}
+
+	return number, nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/checkpoint"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/xdrmsg"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNamespace = "test"
+	testSet       = "demo"
+)
+
+var testStart = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+type testChange struct {
+	key    int
+	lut    time.Time
+	delete bool
+}
+
+// newTestToken returns a token with an XDR write message, as it is received by the backup.
+func newTestToken(t *testing.T, c testChange) *bModels.ASBXToken {
+	t.Helper()
+
+	key, err := aerospike.NewKey(testNamespace, testSet, c.key)
+	require.NoError(t, err)
+
+	var fields []byte
+
+	addField := func(fieldType byte, data []byte) {
+		size := make([]byte, 4)
+		binary.BigEndian.PutUint32(size, uint32(len(data)+1))
+		fields = append(fields, size...)
+		fields = append(fields, fieldType)
+		fields = append(fields, data...)
+	}
+
+	lut := make([]byte, 8)
+	binary.BigEndian.PutUint64(lut, uint64(c.lut.UnixMilli()-xdrmsg.CitrusleafEpoch*1000))
+
+	addField(xdr.FieldTypeNamespace, []byte(testNamespace))
+	addField(xdr.FieldTypeSet, []byte(testSet))
+	addField(xdr.FieldTypeDigest, key.Digest())
+	addField(xdrmsg.FieldTypeLUT, lut)
+
+	header := make([]byte, xdr.LenMessageHeader)
+	header[0] = xdr.LenMessageHeader
+
+	header[2] = xdr.MsgInfo2Write
+	// Generation and the number of fields.
+	binary.BigEndian.PutUint32(header[6:10], 3)
+	binary.BigEndian.PutUint16(header[18:20], 4)
+
+	if c.delete {
+		header[2] |= xdr.MsgInfo2Delete
+	} else {
+		// A bin is written.
+		binary.BigEndian.PutUint16(header[20:22], 1)
+	}
+
+	return bModels.NewASBXToken(key, xdr.NewPayload(append(header, fields...)))
+}
+
+// writeTestFile writes an .asbx file with the changes to the directory.
+func writeTestFile(t *testing.T, dir string, number uint64, changes ...testChange) {
+	t.Helper()
+
+	encoder := asbx.NewEncoder[*bModels.ASBXToken](testNamespace)
+	data := encoder.GetHeader(number, false)
+
+	for _, c := range changes {
+		b, err := encoder.EncodeToken(newTestToken(t, c))
+		require.NoError(t, err)
+
+		data = append(data, b...)
+	}
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, fmt.Sprintf("0_%s_%d.asbx", testNamespace, number)), data, 0o600))
+}
+
+func newTestService(t *testing.T, inspect *models.Inspect) (*Service, *bytes.Buffer) {
+	t.Helper()
+
+	s, err := NewService(&config.InspectServiceConfig{
+		App:     &models.App{},
+		Inspect: inspect,
+	}, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+
+	var dump bytes.Buffer
+	s.dump = &dump
+
+	return s, &dump
+}
+
+func TestService_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTestFile(t, dir, 1,
+		testChange{key: 1, lut: testStart.Add(10 * time.Second)},
+		testChange{key: 2, lut: testStart.Add(70 * time.Second), delete: true},
+	)
+	writeTestFile(t, dir, 2,
+		testChange{key: 3, lut: testStart.Add(80 * time.Second)},
+	)
+
+	s, dump := newTestService(t, &models.Inspect{
+		Directory:         dir,
+		HistogramInterval: time.Minute,
+		Dump:              true,
+		DumpLimit:         2,
+	})
+
+	require.NoError(t, s.Run(context.Background()))
+
+	require.Len(t, s.files, 2)
+	require.Equal(t, "0_test_1.asbx", s.files[0].Name)
+	require.Equal(t, testNamespace, s.files[0].Namespace)
+	require.Equal(t, uint64(2), s.files[0].Records)
+	require.Equal(t, uint64(1), s.files[0].Deletes)
+	require.Equal(t, testStart.Add(10*time.Second), s.files[0].FirstLUT)
+	require.Equal(t, testStart.Add(70*time.Second), s.files[0].LastLUT)
+	require.Equal(t, uint64(1), s.files[1].Records)
+
+	buckets := s.buckets()
+	require.Len(t, buckets, 2)
+	require.Equal(t, testStart, buckets[0].Start)
+	require.Equal(t, uint64(1), buckets[0].Records)
+	require.Equal(t, testStart.Add(time.Minute), buckets[1].Start)
+	require.Equal(t, uint64(2), buckets[1].Records)
+
+	// Only the first changes are dumped.
+	lines := strings.Split(strings.TrimSpace(dump.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var change Change
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &change))
+	require.Equal(t, "0_test_1.asbx", change.File)
+	require.Equal(t, testNamespace, change.Namespace)
+	require.Equal(t, testSet, change.Set)
+	require.True(t, change.Delete)
+	require.Equal(t, int32(3), change.Generation)
+	require.NotNil(t, change.LUT)
+	require.Equal(t, testStart.Add(70*time.Second), *change.LUT)
+}
+
+func TestService_RunSegments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	cp := checkpoint.New(testNamespace)
+
+	for i := range 3 {
+		to := testStart.Add(time.Duration(i) * 10 * time.Minute)
+		cp.Add(checkpoint.Segment{Directory: checkpoint.SegmentName(to), To: to})
+	}
+
+	// The second segment has no changes.
+	writeTestFile(t, filepath.Join(dir, cp.Segments[0].Directory), 1, testChange{key: 1, lut: testStart})
+	writeTestFile(t, filepath.Join(dir, cp.Segments[2].Directory), 1, testChange{key: 1, lut: testStart.Add(time.Hour)})
+
+	writer, err := local.NewWriter(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+	require.NoError(t, checkpoint.Write(ctx, writer, cp))
+
+	s, _ := newTestService(t, &models.Inspect{Directory: dir, HistogramInterval: time.Hour})
+
+	require.NoError(t, s.Run(ctx))
+	require.Len(t, s.files, 2)
+	require.Equal(t, cp.Segments[0].Directory, s.files[0].Segment)
+	require.Equal(t, cp.Segments[2].Directory, s.files[1].Segment)
+	require.Len(t, s.buckets(), 2)
+}
+
+func TestService_RunCompressed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, 1, testChange{key: 1, lut: testStart})
+
+	// Compress the file, as it is written by the backup with --compress zstd.
+	name := filepath.Join(dir, "0_test_1.asbx")
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(name, encoder.EncodeAll(data, nil), 0o600))
+
+	s, err := NewService(&config.InspectServiceConfig{
+		App:         &models.App{},
+		Inspect:     &models.Inspect{InputFile: name, HistogramInterval: time.Minute},
+		Compression: &models.Compression{Mode: "zstd"},
+	}, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Run(context.Background()))
+	require.Len(t, s.files, 1)
+	require.Equal(t, uint64(1), s.files[0].Records)
+}
+
+func TestNewService_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewService(&config.InspectServiceConfig{
+		App:        &models.App{},
+		Inspect:    &models.Inspect{Directory: "backup", HistogramInterval: time.Minute},
+		Encryption: &models.Encryption{Mode: "AES256", KeyFile: "key.pem"},
+	}, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.ErrorContains(t, err, "inspect of encrypted backup is not supported")
+}
+
+func TestFileNumber(t *testing.T) {
+	t.Parallel()
+
+	number, err := fileNumber("0_test_15.asbx")
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), number)
+
+	// Namespaces can contain underscores.
+	number, err = fileNumber("3_source_ns_2.asbx")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), number)
+
+	_, err = fileNumber("test.asbx")
+	require.ErrorContains(t, err, "invalid file name")
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -29,27 +30,272 @@ const (
 	headerRestoreReport    = "Restore report"
 	headerEstimateReport   = "Estimate report"
 	headerValidationReport = "Validation report"
+	headerBackupJobReport  = "Backup job report"
+	headerBackupJobsReport = "Backup jobs report"
+	headerBackupNodeReport = "Backup node report"
+	headerBackupRackReport = "Backup rack report"
+	headerXDRStatusReport  = "XDR status report"
+	headerInspectReport    = "Inspect report"
+	headerInspectSummary   = "Inspect summary"
+	headerInspectTimeline  = "Changes over time"
 )
 
-// ReportBackup prints the backup report.
+// BackupJobResult contains the result of a single job of a multi-job backup.
+type BackupJobResult struct {
+	Name string
+	// Destination is the directory or file, where the job is written.
+	Destination string
+	Stats       *bModels.BackupStats
+	Err         error
+}
+
+// BackupNodeResult contains the part of a rack-pinned backup that was read from a node.
+type BackupNodeResult struct {
+	// Node is empty for partitions that were read from other racks.
+	Node string
+	// Rack is -1 for partitions that were read from other racks.
+	Rack       int
+	Partitions int
+	// Fallback is true if the node was lost during the backup,
+	// so a part of its partitions can be read from replicas on other racks.
+	Fallback bool
+	Stats    *bModels.BackupStats
+}
+
+// XDRNodeStatus contains the state of the xdr backup DC on a node.
+type XDRNodeStatus struct {
+	Node string
+	// DCFound is false if the DC doesn't exist on the node.
+	DCFound bool
+	// Lag in seconds.
+	Lag               int64
+	InQueue           int64
+	InProgress        int64
+	Success           int64
+	Abandoned         int64
+	Recoveries        int64
+	RecoveriesPending int64
+	MRTWritesBlocked  bool
+	Err               error
+}
+
+// InspectFile contains the summary of changes in an .asbx file.
+type InspectFile struct {
+	Name string
+	// Segment of a continuous xdr backup, empty if the files are not in a segment.
+	Segment   string
+	Namespace string
+	Records   uint64
+	Deletes   uint64
+	// FirstLUT and LastLUT are zero if no record contains the last update time.
+	FirstLUT time.Time
+	LastLUT  time.Time
+}
+
+// InspectBucket is a bucket of the histogram of changes over time.
+type InspectBucket struct {
+	Start   time.Time
+	Records uint64
+}
+
+// ReportBackup prints the backup report, destination is the directory or file, where the backup is written.
+// if isJSON is true, it prints the report in JSON format, but logger must be passed
+func ReportBackup(stats *bModels.BackupStats, destination string, isXdr, isJSON bool, logger *slog.Logger) {
+	if isJSON {
+		logBackupReport(headerBackupReport, destination, stats, isXdr, logger)
+		return
+	}
+
+	printBackupReport(headerBackupReport, destination, stats, isXdr)
+}
+
+// ReportBackupJobs prints a report for each job of a multi-job backup, followed by the aggregated report.
+// if isJSON is true, it prints the report in JSON format, but logger must be passed
+func ReportBackupJobs(results []BackupJobResult, isJSON bool, logger *slog.Logger) {
+	stats := make([]*bModels.BackupStats, 0, len(results))
+
+	var failed int
+
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+
+			if isJSON {
+				logger.Error(strings.ToLower(headerBackupJobReport),
+					slog.String("job", r.Name),
+					slog.Any("error", r.Err),
+				)
+
+				continue
+			}
+
+			header := fmt.Sprintf("%s: %s", headerBackupJobReport, r.Name)
+			printToStderr("")
+			printToStderr(header)
+			printToStderr(strings.Repeat("-", len(header)))
+			printMetric("Error", r.Err)
+
+			continue
+		}
+
+		stats = append(stats, r.Stats)
+
+		if isJSON {
+			logBackupReport(strings.ToLower(headerBackupJobReport), r.Destination, r.Stats, false,
+				logger.With(slog.String("job", r.Name)))
+
+			continue
+		}
+
+		printBackupReport(fmt.Sprintf("%s: %s", headerBackupJobReport, r.Name), r.Destination, r.Stats, false)
+	}
+
+	total := bModels.SumBackupStats(stats...)
+
+	if isJSON {
+		logBackupReport(strings.ToLower(headerBackupJobsReport), "", total, false,
+			logger.With(slog.Int("jobs", len(results)), slog.Int("jobs_failed", failed)))
+
+		return
+	}
+
+	printBackupReport(headerBackupJobsReport, "", total, false)
+	printMetric("Jobs", len(results))
+	printMetric("Jobs Failed", failed)
+}
+
+// ReportBackupRack prints the report of a rack-pinned backup, followed by records and bytes
+// of each node and rack.
 // if isJSON is true, it prints the report in JSON format, but logger must be passed
-func ReportBackup(stats *bModels.BackupStats, isXdr, isJSON bool, logger *slog.Logger) {
+func ReportBackupRack(results []BackupNodeResult, destination string, isJSON bool, logger *slog.Logger) {
+	stats := make([]*bModels.BackupStats, 0, len(results))
+	for _, r := range results {
+		stats = append(stats, r.Stats)
+	}
+
+	total := bModels.SumBackupStats(stats...)
+	racks := summarizeRacks(results)
+
 	if isJSON {
-		logBackupReport(stats, isXdr, logger)
+		logBackupReport(headerBackupReport, destination, total, false, logger)
+
+		for _, r := range results {
+			logger.Info(strings.ToLower(headerBackupNodeReport),
+				slog.String("node", r.Node),
+				slog.Int("rack", r.Rack),
+				slog.Int("partitions", r.Partitions),
+				slog.Bool("fallback", r.Fallback),
+				slog.Uint64("records_read", r.Stats.GetReadRecords()),
+				slog.Uint64("bytes_written", r.Stats.GetBytesWritten()),
+			)
+		}
+
+		for _, r := range racks {
+			logger.Info(strings.ToLower(headerBackupRackReport),
+				slog.Int("rack", r.rack),
+				slog.Int("nodes", len(r.nodes)),
+				slog.Uint64("records_read", r.records),
+				slog.Uint64("bytes_written", r.bytes),
+			)
+		}
+
 		return
 	}
 
-	printBackupReport(stats, isXdr)
+	printBackupReport(headerBackupReport, destination, total, false)
+
+	for _, r := range results {
+		header := fmt.Sprintf("%s: %s", headerBackupNodeReport, nodeName(r.Node))
+		printToStderr("")
+		printToStderr(header)
+		printToStderr(strings.Repeat("-", len(header)))
+		printMetric("Rack", rackName(r.Rack))
+		printMetric("Partitions", r.Partitions)
+		printMetric("Fallback", r.Fallback)
+		printMetric("Records Read", r.Stats.GetReadRecords())
+		printMetric("Bytes Written", r.Stats.GetBytesWritten())
+	}
+
+	for _, r := range racks {
+		header := fmt.Sprintf("%s: %s", headerBackupRackReport, rackName(r.rack))
+		printToStderr("")
+		printToStderr(header)
+		printToStderr(strings.Repeat("-", len(header)))
+		printMetric("Nodes", len(r.nodes))
+		printMetric("Records Read", r.records)
+		printMetric("Bytes Written", r.bytes)
+	}
+}
+
+// rackSummary contains records and bytes read from a rack.
+type rackSummary struct {
+	rack    int
+	nodes   []string
+	records uint64
+	bytes   uint64
+}
+
+// summarizeRacks sums results by rack, racks are sorted by id, other racks are last.
+func summarizeRacks(results []BackupNodeResult) []rackSummary {
+	racks := make([]rackSummary, 0)
+
+	for _, r := range results {
+		i := slices.IndexFunc(racks, func(s rackSummary) bool { return s.rack == r.Rack })
+		if i < 0 {
+			racks = append(racks, rackSummary{rack: r.Rack})
+			i = len(racks) - 1
+		}
+
+		if r.Node != "" && !slices.Contains(racks[i].nodes, r.Node) {
+			racks[i].nodes = append(racks[i].nodes, r.Node)
+		}
+
+		racks[i].records += r.Stats.GetReadRecords()
+		racks[i].bytes += r.Stats.GetBytesWritten()
+	}
+
+	slices.SortFunc(racks, func(a, b rackSummary) int {
+		switch {
+		case a.rack < 0:
+			return 1
+		case b.rack < 0:
+			return -1
+		default:
+			return a.rack - b.rack
+		}
+	})
+
+	return racks
 }
 
-func printBackupReport(stats *bModels.BackupStats, isXdr bool) {
+func nodeName(node string) string {
+	if node == "" {
+		return "other racks"
+	}
+
+	return node
+}
+
+func rackName(rack int) string {
+	if rack < 0 {
+		return "other"
+	}
+
+	return fmt.Sprint(rack)
+}
+
+func printBackupReport(header, destination string, stats *bModels.BackupStats, isXdr bool) {
 	printToStderr("")
-	printToStderr(headerBackupReport)
-	printToStderr(strings.Repeat("-", len(headerBackupReport)))
+	printToStderr(header)
+	printToStderr(strings.Repeat("-", len(header)))
 
 	printMetric("Start Time", stats.StartTime.Format(time.RFC1123))
 	printMetric("Duration", stats.GetDuration())
 
+	if destination != "" {
+		printMetric("Destination", destination)
+	}
+
 	printToStderr("")
 
 	recordsMetric := "Records Read"
@@ -68,13 +314,17 @@ func printBackupReport(stats *bModels.BackupStats, isXdr bool) {
 	printMetric("Files Written", stats.GetFileCount())
 }
 
-func logBackupReport(stats *bModels.BackupStats, isXdr bool, logger *slog.Logger) {
+func logBackupReport(header, destination string, stats *bModels.BackupStats, isXdr bool, logger *slog.Logger) {
 	recordsMetric := "records_read"
 	if isXdr {
 		recordsMetric = "records_received"
 	}
 
-	logger.Info(strings.ToLower(headerBackupReport),
+	if destination != "" {
+		logger = logger.With(slog.String("destination", destination))
+	}
+
+	logger.Info(strings.ToLower(header),
 		slog.Time("start_time", stats.StartTime),
 		slog.Duration("duration", stats.GetDuration()),
 		slog.Uint64(recordsMetric, stats.GetReadRecords()),
@@ -196,6 +446,211 @@ func logEstimateReport(estimate uint64, logger *slog.Logger) {
 	)
 }
 
+// ReportXDRStatus prints the xdr status of each node, followed by the health of the DC.
+// if isJSON is true, it prints the report in JSON format, but logger must be passed.
+func ReportXDRStatus(statuses []XDRNodeStatus, problems []string, isJSON bool, logger *slog.Logger) {
+	if isJSON {
+		logXDRStatusReport(statuses, problems, logger)
+		return
+	}
+
+	printXDRStatusReport(statuses, problems)
+}
+
+func printXDRStatusReport(statuses []XDRNodeStatus, problems []string) {
+	for _, s := range statuses {
+		header := fmt.Sprintf("%s: %s", headerXDRStatusReport, s.Node)
+		printToStderr("")
+		printToStderr(header)
+		printToStderr(strings.Repeat("-", len(header)))
+
+		if s.Err != nil {
+			printMetric("Error", s.Err)
+			continue
+		}
+
+		printMetric("DC Found", s.DCFound)
+		printMetric("MRT Writes Blocked", s.MRTWritesBlocked)
+
+		if !s.DCFound {
+			continue
+		}
+
+		printMetric("Lag (seconds)", s.Lag)
+		printMetric("In Queue", s.InQueue)
+		printMetric("In Progress", s.InProgress)
+		printMetric("Acknowledged", s.Success)
+		printMetric("Abandoned", s.Abandoned)
+		printMetric("Recoveries", s.Recoveries)
+		printMetric("Recoveries Pending", s.RecoveriesPending)
+	}
+
+	printToStderr("")
+	printMetric("Healthy", len(problems) == 0)
+
+	for _, p := range problems {
+		printMetric("Problem", p)
+	}
+}
+
+func logXDRStatusReport(statuses []XDRNodeStatus, problems []string, logger *slog.Logger) {
+	header := strings.ToLower(headerXDRStatusReport)
+
+	for _, s := range statuses {
+		if s.Err != nil {
+			logger.Error(header, slog.String("node", s.Node), slog.Any("error", s.Err))
+			continue
+		}
+
+		logger.Info(header,
+			slog.String("node", s.Node),
+			slog.Bool("dc_found", s.DCFound),
+			slog.Bool("mrt_writes_blocked", s.MRTWritesBlocked),
+			slog.Int64("lag", s.Lag),
+			slog.Int64("in_queue", s.InQueue),
+			slog.Int64("in_progress", s.InProgress),
+			slog.Int64("acknowledged", s.Success),
+			slog.Int64("abandoned", s.Abandoned),
+			slog.Int64("recoveries", s.Recoveries),
+			slog.Int64("recoveries_pending", s.RecoveriesPending),
+		)
+	}
+
+	logger.Info(header,
+		slog.Bool("healthy", len(problems) == 0),
+		slog.Any("problems", problems),
+	)
+}
+
+// ReportInspect prints the report of inspected .asbx files and the histogram of changes over time.
+// if isJSON is true, it prints the report in JSON format, but logger must be passed.
+func ReportInspect(files []InspectFile, histogram []InspectBucket, isJSON bool, logger *slog.Logger) {
+	if isJSON {
+		logInspectReport(files, histogram, logger)
+		return
+	}
+
+	printInspectReport(files, histogram)
+}
+
+func printInspectReport(files []InspectFile, histogram []InspectBucket) {
+	for _, f := range files {
+		header := fmt.Sprintf("%s: %s", headerInspectReport, f.Name)
+		printToStderr("")
+		printToStderr(header)
+		printToStderr(strings.Repeat("-", len(header)))
+
+		if f.Segment != "" {
+			printMetric("Segment", f.Segment)
+		}
+
+		printMetric("Namespace", f.Namespace)
+		printMetric("Records", f.Records)
+		printMetric("Deletes", f.Deletes)
+		printMetric("First LUT", formatLUT(f.FirstLUT))
+		printMetric("Last LUT", formatLUT(f.LastLUT))
+	}
+
+	summary := summarizeInspect(files)
+
+	printToStderr("")
+	printToStderr(headerInspectSummary)
+	printToStderr(strings.Repeat("-", len(headerInspectSummary)))
+	printMetric("Files", len(files))
+	printMetric("Namespaces", strings.Join(summary.Namespaces, ", "))
+	printMetric("Records", summary.Records)
+	printMetric("Deletes", summary.Deletes)
+	printMetric("First LUT", formatLUT(summary.FirstLUT))
+	printMetric("Last LUT", formatLUT(summary.LastLUT))
+
+	if len(histogram) == 0 {
+		return
+	}
+
+	printToStderr("")
+	printToStderr(headerInspectTimeline)
+	printToStderr(strings.Repeat("-", len(headerInspectTimeline)))
+
+	for _, b := range histogram {
+		printMetric(b.Start.UTC().Format(time.RFC3339), b.Records)
+	}
+}
+
+func logInspectReport(files []InspectFile, histogram []InspectBucket, logger *slog.Logger) {
+	header := strings.ToLower(headerInspectReport)
+
+	for _, f := range files {
+		logger.Info(header,
+			slog.String("file", f.Name),
+			slog.String("segment", f.Segment),
+			slog.String("namespace", f.Namespace),
+			slog.Uint64("records", f.Records),
+			slog.Uint64("deletes", f.Deletes),
+			slog.String("first_lut", formatLUT(f.FirstLUT)),
+			slog.String("last_lut", formatLUT(f.LastLUT)),
+		)
+	}
+
+	summary := summarizeInspect(files)
+
+	logger.Info(strings.ToLower(headerInspectSummary),
+		slog.Int("files", len(files)),
+		slog.Any("namespaces", summary.Namespaces),
+		slog.Uint64("records", summary.Records),
+		slog.Uint64("deletes", summary.Deletes),
+		slog.String("first_lut", formatLUT(summary.FirstLUT)),
+		slog.String("last_lut", formatLUT(summary.LastLUT)),
+	)
+
+	for _, b := range histogram {
+		logger.Info(strings.ToLower(headerInspectTimeline),
+			slog.String("start", b.Start.UTC().Format(time.RFC3339)),
+			slog.Uint64("records", b.Records),
+		)
+	}
+}
+
+// inspectSummary contains totals of inspected files.
+type inspectSummary struct {
+	Namespaces []string
+	Records    uint64
+	Deletes    uint64
+	FirstLUT   time.Time
+	LastLUT    time.Time
+}
+
+func summarizeInspect(files []InspectFile) inspectSummary {
+	var summary inspectSummary
+
+	for _, f := range files {
+		if !slices.Contains(summary.Namespaces, f.Namespace) {
+			summary.Namespaces = append(summary.Namespaces, f.Namespace)
+		}
+
+		summary.Records += f.Records
+		summary.Deletes += f.Deletes
+
+		if !f.FirstLUT.IsZero() && (summary.FirstLUT.IsZero() || f.FirstLUT.Before(summary.FirstLUT)) {
+			summary.FirstLUT = f.FirstLUT
+		}
+
+		if f.LastLUT.After(summary.LastLUT) {
+			summary.LastLUT = f.LastLUT
+		}
+	}
+
+	return summary
+}
+
+// formatLUT returns the last update time in RFC3339 format with milliseconds, or "-" if it is unknown.
+func formatLUT(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
 func printMetric(key string, value any) {
 	fmt.Fprintf(os.Stderr, "%s%v\n", indent(key), value)
 }
@@ -16,6 +16,7 @@ package logging
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
@@ -124,7 +125,7 @@ func TestPrintBackupReport(t *testing.T) {
 	os.Stderr = w
 
 	// Call the function
-	printBackupReport(stats, false)
+	printBackupReport(headerBackupReport, "/backups/test/2024-05-01", stats, false)
 
 	// Close writer and restore stdout
 	w.Close()
@@ -144,6 +145,8 @@ func TestPrintBackupReport(t *testing.T) {
 	assert.Contains(t, output, "Start Time")
 	assert.Contains(t, output, stats.StartTime.Format(time.RFC1123))
 	assert.Contains(t, output, "Duration")
+	assert.Contains(t, output, "Destination")
+	assert.Contains(t, output, "/backups/test/2024-05-01")
 	assert.Contains(t, output, "Records Read")
 	assert.Contains(t, output, "1000")
 	assert.Contains(t, output, "sIndex Read")
@@ -182,7 +185,7 @@ func TestPrintBackupReportXdr(t *testing.T) {
 	os.Stderr = w
 
 	// Call the function with isXdr=true
-	printBackupReport(stats, true)
+	printBackupReport(headerBackupReport, "", stats, true)
 
 	// Close writer and restore stdout
 	w.Close()
@@ -201,6 +204,7 @@ func TestPrintBackupReportXdr(t *testing.T) {
 	assert.Contains(t, output, headerBackupReport)
 	assert.Contains(t, output, "Records Received")
 	assert.NotContains(t, output, "Records Read")
+	assert.NotContains(t, output, "Destination")
 }
 
 func TestLogBackupReport(t *testing.T) {
@@ -229,7 +233,7 @@ func TestLogBackupReport(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
 
 	// Call the function
-	logBackupReport(stats, false, logger)
+	logBackupReport(headerBackupReport, "/backups/test/2024-05-01", stats, false, logger)
 
 	// Verify log output
 	logOutput := buf.String()
@@ -241,6 +245,7 @@ func TestLogBackupReport(t *testing.T) {
 	assert.Contains(t, logOutput, "udf_read=3")
 	assert.Contains(t, logOutput, "bytes_written=5000000")
 	assert.Contains(t, logOutput, "files_written=10")
+	assert.Contains(t, logOutput, "destination=/backups/test/2024-05-01")
 }
 
 func TestLogBackupReportXdr(t *testing.T) {
@@ -269,7 +274,7 @@ func TestLogBackupReportXdr(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
 
 	// Call the function with isXdr=true
-	logBackupReport(stats, true, logger)
+	logBackupReport(headerBackupReport, "", stats, true, logger)
 
 	// Verify log output
 	logOutput := buf.String()
@@ -306,7 +311,7 @@ func TestReportBackup(t *testing.T) {
 		os.Stderr = w
 
 		// Call the function
-		ReportBackup(stats, false, false, nil)
+		ReportBackup(stats, "/backups", false, false, nil)
 
 		// Close writer and restore stdout
 		w.Close()
@@ -333,7 +338,7 @@ func TestReportBackup(t *testing.T) {
 		logger := slog.New(slog.NewTextHandler(&buf, nil))
 
 		// Call the function
-		ReportBackup(stats, false, true, logger)
+		ReportBackup(stats, "/backups", false, true, logger)
 
 		// Verify log output
 		logOutput := buf.String()
@@ -772,3 +777,123 @@ func TestReportEstimate(t *testing.T) {
 		assert.Contains(t, logOutput, "file_size_bytes=5000000")
 	})
 }
+
+func TestReportBackupJobs(t *testing.T) {
+	stats := bModels.NewBackupStats()
+	stats.StartTime = time.Now().Add(-1 * time.Hour) // 1 hour ago
+	stats.ReadRecords.Add(1000)
+	stats.BytesWritten.Add(5000000)
+	stats.IncFiles()
+
+	results := []BackupJobResult{
+		{Name: "users", Destination: "/backups/users", Stats: stats},
+		{Name: "events", Err: errors.New("connection refused")},
+	}
+
+	// Test with isJSON=false
+	t.Run("Console output", func(t *testing.T) {
+		oldStdout := os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stderr = w
+
+		ReportBackupJobs(results, false, nil)
+
+		w.Close()
+
+		os.Stderr = oldStdout
+
+		var buf bytes.Buffer
+		_, err := io.Copy(&buf, r)
+		require.NoError(t, err)
+
+		output := buf.String()
+
+		assert.Contains(t, output, headerBackupJobReport+": users")
+		assert.Contains(t, output, "/backups/users")
+		assert.Contains(t, output, headerBackupJobReport+": events")
+		assert.Contains(t, output, "connection refused")
+		assert.Contains(t, output, headerBackupJobsReport)
+		assert.Contains(t, output, "Jobs Failed:")
+	})
+
+	// Test with isJSON=true
+	t.Run("JSON output", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+		ReportBackupJobs(results, true, logger)
+
+		logOutput := buf.String()
+		assert.Contains(t, logOutput, "job=users")
+		assert.Contains(t, logOutput, "destination=/backups/users")
+		assert.Contains(t, logOutput, "job=events")
+		assert.Contains(t, logOutput, "error=\"connection refused\"")
+		assert.Contains(t, logOutput, "backup jobs report")
+		assert.Contains(t, logOutput, "jobs=2")
+		assert.Contains(t, logOutput, "jobs_failed=1")
+		assert.Contains(t, logOutput, "records_read=1000")
+	})
+}
+
+func TestLogInspectReport(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	files := []InspectFile{
+		{Name: "0_test_1.asbx", Namespace: "test", Records: 10, Deletes: 2, FirstLUT: start, LastLUT: start.Add(time.Minute)},
+		{Name: "0_test_2.asbx", Namespace: "test", Records: 5, FirstLUT: start.Add(-time.Minute), LastLUT: start},
+		{Name: "0_test_3.asbx", Namespace: "test"},
+	}
+	histogram := []InspectBucket{{Start: start, Records: 15}}
+
+	ReportInspect(files, histogram, true, logger)
+
+	logOutput := buf.String()
+	assert.Contains(t, logOutput, "file=0_test_1.asbx")
+	assert.Contains(t, logOutput, "first_lut=2024-05-01T10:00:00.000Z")
+	// Files without records have no LUT.
+	assert.Contains(t, logOutput, "first_lut=- last_lut=-")
+	assert.Contains(t, logOutput, "msg=\"inspect summary\" files=3 namespaces=[test] records=15 deletes=2 "+
+		"first_lut=2024-05-01T09:59:00.000Z last_lut=2024-05-01T10:01:00.000Z")
+	assert.Contains(t, logOutput, "msg=\"changes over time\" start=2024-05-01T10:00:00Z records=15")
+}
+
+func TestReportBackupRack(t *testing.T) {
+	t.Parallel()
+
+	newStats := func(records, bytes uint64) *bModels.BackupStats {
+		stats := bModels.NewBackupStats()
+		stats.ReadRecords.Add(records)
+		stats.BytesWritten.Add(bytes)
+
+		return stats
+	}
+
+	results := []BackupNodeResult{
+		{Node: "A1", Rack: 1, Partitions: 2048, Stats: newStats(100, 1000)},
+		{Node: "", Rack: -1, Partitions: 10, Fallback: true, Stats: newStats(5, 50)},
+		{Node: "A2", Rack: 1, Partitions: 2038, Stats: newStats(200, 2000)},
+		{Node: "A2", Rack: 1, Partitions: 10, Stats: newStats(1, 10)},
+	}
+
+	racks := summarizeRacks(results)
+	assert.Equal(t, []rackSummary{
+		{rack: 1, nodes: []string{"A1", "A2"}, records: 301, bytes: 3010},
+		{rack: -1, records: 5, bytes: 50},
+	}, racks)
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	ReportBackupRack(results, "/backups", true, logger)
+
+	logOutput := buf.String()
+	assert.Contains(t, logOutput, "records_read=306")
+	assert.Contains(t, logOutput, "bytes_written=3060")
+	assert.Contains(t, logOutput, `msg="backup node report" node=A1 rack=1 partitions=2048 fallback=false`)
+	assert.Contains(t, logOutput, `msg="backup rack report" rack=1 nodes=2 records_read=301 bytes_written=3010`)
+	assert.Contains(t, logOutput, `msg="backup rack report" rack=-1 nodes=0 records_read=5 bytes_written=50`)
+}
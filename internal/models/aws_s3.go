@@ -16,11 +16,29 @@
 package models
 
 import (
+	"encoding/base64"
 	"fmt"
 
 	"github.com/aerospike/backup-go"
 )
 
+const (
+	// S3SSEAES256 encrypts objects with S3 managed keys (SSE-S3).
+	S3SSEAES256 = "AES256"
+	// S3SSEKMS encrypts objects with AWS KMS keys (SSE-KMS).
+	S3SSEKMS = "aws:kms"
+	// S3SSECustomer encrypts objects with a key provided by the client (SSE-C).
+	S3SSECustomer = "customer"
+
+	// S3AddressingStylePath addresses buckets in the path, e.g. https://s3.amazonaws.com/bucket/key.
+	S3AddressingStylePath = "path"
+	// S3AddressingStyleVirtualHosted addresses buckets in the host name, e.g. https://bucket.s3.amazonaws.com/key.
+	S3AddressingStyleVirtualHosted = "virtual-hosted"
+
+	// s3SSECustomerKeyLen is the length of AES-256 key, that S3 requires for SSE-C.
+	s3SSECustomerKeyLen = 32
+)
+
 // AwsS3 represents the configuration for AWS S3 storage integration.
 type AwsS3 struct {
 	BucketName      string
@@ -29,6 +47,22 @@ type AwsS3 struct {
 	Endpoint        string
 	AccessKeyID     string
 	SecretAccessKey string
+	// AddressingStyle is S3AddressingStylePath or S3AddressingStyleVirtualHosted.
+	AddressingStyle string
+
+	// RoleArn is the IAM role that is assumed with STS, base credentials are used to assume it.
+	RoleArn string
+	// ExternalID is passed to STS when the role is assumed, e.g. for a cross-account role.
+	ExternalID  string
+	SessionName string
+	// WebIdentityTokenFile contains an OIDC token, that is used to assume the role instead of base credentials.
+	WebIdentityTokenFile string
+
+	// SSE is the server-side encryption of uploaded objects: S3SSEAES256, S3SSEKMS or S3SSECustomer.
+	SSE         string
+	SSEKmsKeyID string
+	// SSECustomerKey is a base64-encoded 256-bit key for SSE-C, it is required to read objects back.
+	SSECustomerKey string
 
 	StorageClass        string
 	AccessTier          string
@@ -77,6 +111,36 @@ func (a *AwsS3) LoadSecrets(cfg *backup.SecretAgentConfig) error {
 		return fmt.Errorf("failed to load secret access key from secret agent: %w", err)
 	}
 
+	a.RoleArn, err = backup.ParseSecret(cfg, a.RoleArn)
+	if err != nil {
+		return fmt.Errorf("failed to load role arn from secret agent: %w", err)
+	}
+
+	a.ExternalID, err = backup.ParseSecret(cfg, a.ExternalID)
+	if err != nil {
+		return fmt.Errorf("failed to load external id from secret agent: %w", err)
+	}
+
+	a.SessionName, err = backup.ParseSecret(cfg, a.SessionName)
+	if err != nil {
+		return fmt.Errorf("failed to load session name from secret agent: %w", err)
+	}
+
+	a.WebIdentityTokenFile, err = backup.ParseSecret(cfg, a.WebIdentityTokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to load web identity token file from secret agent: %w", err)
+	}
+
+	a.SSEKmsKeyID, err = backup.ParseSecret(cfg, a.SSEKmsKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to load sse kms key id from secret agent: %w", err)
+	}
+
+	a.SSECustomerKey, err = backup.ParseSecret(cfg, a.SSECustomerKey)
+	if err != nil {
+		return fmt.Errorf("failed to load sse customer key from secret agent: %w", err)
+	}
+
 	a.StorageClass, err = backup.ParseSecret(cfg, a.StorageClass)
 	if err != nil {
 		return fmt.Errorf("failed to load storage class from secret agent: %w", err)
@@ -100,6 +164,25 @@ func (a *AwsS3) Validate(isBackup bool) error {
 		return fmt.Errorf("retry maximum attempts must be non-negative")
 	}
 
+	if a.RoleArn == "" && (a.ExternalID != "" || a.SessionName != "" || a.WebIdentityTokenFile != "") {
+		return fmt.Errorf("role arn is required with external id, session name or web identity token file")
+	}
+
+	if a.ExternalID != "" && a.WebIdentityTokenFile != "" {
+		return fmt.Errorf("external id is not supported with web identity token file")
+	}
+
+	switch a.AddressingStyle {
+	case "", S3AddressingStylePath, S3AddressingStyleVirtualHosted:
+	default:
+		return fmt.Errorf("invalid addressing style %s, must be %s or %s",
+			a.AddressingStyle, S3AddressingStylePath, S3AddressingStyleVirtualHosted)
+	}
+
+	if err := a.validateSSE(isBackup); err != nil {
+		return err
+	}
+
 	if a.RetryMaxBackoff < 0 {
 		return fmt.Errorf("retry max backoff must be non-negative")
 	}
@@ -125,3 +208,33 @@ func (a *AwsS3) Validate(isBackup bool) error {
 
 	return nil
 }
+
+func (a *AwsS3) validateSSE(isBackup bool) error {
+	switch a.SSE {
+	case "", S3SSEAES256, S3SSEKMS, S3SSECustomer:
+	default:
+		return fmt.Errorf("invalid sse %q, must be one of %s, %s, %s", a.SSE, S3SSEAES256, S3SSEKMS, S3SSECustomer)
+	}
+
+	if a.SSEKmsKeyID != "" && a.SSE != S3SSEKMS {
+		return fmt.Errorf("sse kms key id can be used only with sse %s", S3SSEKMS)
+	}
+
+	// On restore, objects are decrypted with the customer key only, other sse types are transparent for reads.
+	if isBackup && (a.SSE == S3SSECustomer) != (a.SSECustomerKey != "") {
+		return fmt.Errorf("sse customer key must be set if and only if sse is %s", S3SSECustomer)
+	}
+
+	if a.SSECustomerKey != "" {
+		key, err := base64.StdEncoding.DecodeString(a.SSECustomerKey)
+		if err != nil {
+			return fmt.Errorf("failed to decode sse customer key: %w", err)
+		}
+
+		if len(key) != s3SSECustomerKeyLen {
+			return fmt.Errorf("sse customer key must be %d bytes long, got %d", s3SSECustomerKeyLen, len(key))
+		}
+	}
+
+	return nil
+}
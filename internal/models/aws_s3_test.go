@@ -61,6 +61,116 @@ func TestAwsS3_Validate(t *testing.T) {
 			isBackup: false,
 			wantErr:  "",
 		},
+		{
+			name: "valid role configuration",
+			aws: &AwsS3{
+				BucketName:  testBucketName,
+				ChunkSize:   5,
+				RoleArn:     "arn:aws:iam::123456789012:role/backup",
+				ExternalID:  "external",
+				SessionName: "backup",
+			},
+			isBackup: true,
+			wantErr:  "",
+		},
+		{
+			name: "external id without role",
+			aws: &AwsS3{
+				BucketName: testBucketName,
+				ChunkSize:  5,
+				ExternalID: "external",
+			},
+			isBackup: true,
+			wantErr:  "role arn is required with external id, session name or web identity token file",
+		},
+		{
+			name: "external id with web identity",
+			aws: &AwsS3{
+				BucketName:           testBucketName,
+				ChunkSize:            5,
+				RoleArn:              "arn:aws:iam::123456789012:role/backup",
+				ExternalID:           "external",
+				WebIdentityTokenFile: "/var/run/secrets/token",
+			},
+			isBackup: true,
+			wantErr:  "external id is not supported with web identity token file",
+		},
+		{
+			name: "invalid addressing style",
+			aws: &AwsS3{
+				BucketName:      testBucketName,
+				ChunkSize:       5,
+				AddressingStyle: "virtual",
+			},
+			isBackup: true,
+			wantErr:  "invalid addressing style virtual, must be path or virtual-hosted",
+		},
+		{
+			name: "valid kms configuration",
+			aws: &AwsS3{
+				BucketName:  testBucketName,
+				ChunkSize:   5,
+				SSE:         S3SSEKMS,
+				SSEKmsKeyID: "alias/backup",
+			},
+			isBackup: true,
+			wantErr:  "",
+		},
+		{
+			name: "invalid sse",
+			aws: &AwsS3{
+				BucketName: testBucketName,
+				ChunkSize:  5,
+				SSE:        "aws:kms:dsse",
+			},
+			isBackup: true,
+			wantErr:  `invalid sse "aws:kms:dsse", must be one of AES256, aws:kms, customer`,
+		},
+		{
+			name: "kms key id without kms sse",
+			aws: &AwsS3{
+				BucketName:  testBucketName,
+				ChunkSize:   5,
+				SSE:         S3SSEAES256,
+				SSEKmsKeyID: "alias/backup",
+			},
+			isBackup: true,
+			wantErr:  "sse kms key id can be used only with sse aws:kms",
+		},
+		{
+			name: "customer sse without key",
+			aws: &AwsS3{
+				BucketName: testBucketName,
+				ChunkSize:  5,
+				SSE:        S3SSECustomer,
+			},
+			isBackup: true,
+			wantErr:  "sse customer key must be set if and only if sse is customer",
+		},
+		{
+			name: "customer key of wrong length",
+			aws: &AwsS3{
+				BucketName:          testBucketName,
+				RestorePollDuration: 10,
+				SSECustomerKey:      "c2VjcmV0",
+			},
+			isBackup: false,
+			wantErr:  "sse customer key must be 32 bytes long, got 6",
+		},
+		{
+			name: "valid customer key on restore",
+			aws: &AwsS3{
+				BucketName:          testBucketName,
+				RestorePollDuration: 10,
+				SSECustomerKey:      "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+				StorageCommon: StorageCommon{
+					RetryReadMultiplier: 3,
+					RetryReadBackoff:    3,
+				},
+			},
+			isBackup: false,
+			wantErr:  "",
+		},
 		{
 			name: "empty bucket name",
 			aws: &AwsS3{
@@ -17,10 +17,21 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aerospike/backup-go"
 )
 
+// Azure authentication modes.
+const (
+	AzureAuthModeSharedKey        = "shared-key"
+	AzureAuthModeClientSecret     = "client-secret"
+	AzureAuthModeManagedIdentity  = "managed-identity"
+	AzureAuthModeWorkloadIdentity = "workload-identity"
+	AzureAuthModeSAS              = "sas"
+	AzureAuthModeDefault          = "default"
+)
+
 // AzureBlob represents the configuration for Azure Blob storage integration.
 type AzureBlob struct {
 	// Account name + key auth
@@ -30,6 +41,12 @@ type AzureBlob struct {
 	TenantID     string
 	ClientID     string
 	ClientSecret string
+	// SASToken is a shared access signature, appended to the endpoint.
+	SASToken string
+	// FederatedTokenFile contains a token for workload identity authorization.
+	FederatedTokenFile string
+	// AuthMode selects the credential type. If empty, it is detected from the set credentials.
+	AuthMode string
 
 	Endpoint      string
 	ContainerName string
@@ -76,6 +93,21 @@ func (a *AzureBlob) LoadSecrets(cfg *backup.SecretAgentConfig) error {
 		return fmt.Errorf("failed to load client secret from secret agent: %w", err)
 	}
 
+	a.SASToken, err = backup.ParseSecret(cfg, a.SASToken)
+	if err != nil {
+		return fmt.Errorf("failed to load sas token from secret agent: %w", err)
+	}
+
+	a.FederatedTokenFile, err = backup.ParseSecret(cfg, a.FederatedTokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to load federated token file from secret agent: %w", err)
+	}
+
+	a.AuthMode, err = backup.ParseSecret(cfg, a.AuthMode)
+	if err != nil {
+		return fmt.Errorf("failed to load auth mode from secret agent: %w", err)
+	}
+
 	a.Endpoint, err = backup.ParseSecret(cfg, a.Endpoint)
 	if err != nil {
 		return fmt.Errorf("failed to load endpoint from secret agent: %w", err)
@@ -104,6 +136,10 @@ func (a *AzureBlob) Validate(isBackup bool) error {
 		return fmt.Errorf("endpoint is required")
 	}
 
+	if err := a.validateAuthMode(); err != nil {
+		return err
+	}
+
 	if a.RetryMaxAttempts < 0 {
 		return fmt.Errorf("retry maximum attempts must be non-negative")
 	}
@@ -137,3 +173,36 @@ func (a *AzureBlob) Validate(isBackup bool) error {
 
 	return nil
 }
+
+func (a *AzureBlob) validateAuthMode() error {
+	switch a.AuthMode {
+	case "", AzureAuthModeManagedIdentity, AzureAuthModeWorkloadIdentity, AzureAuthModeDefault:
+	case AzureAuthModeSharedKey:
+		if a.AccountName == "" || a.AccountKey == "" {
+			return fmt.Errorf("account name and account key are required for %s auth mode", a.AuthMode)
+		}
+	case AzureAuthModeClientSecret:
+		if a.TenantID == "" || a.ClientID == "" || a.ClientSecret == "" {
+			return fmt.Errorf("tenant id, client id and client secret are required for %s auth mode", a.AuthMode)
+		}
+	case AzureAuthModeSAS:
+		if a.SASToken == "" {
+			return fmt.Errorf("sas token is required for %s auth mode", a.AuthMode)
+		}
+	default:
+		return fmt.Errorf("invalid auth mode %s, must be one of %s", a.AuthMode, strings.Join([]string{
+			AzureAuthModeSharedKey, AzureAuthModeClientSecret, AzureAuthModeManagedIdentity,
+			AzureAuthModeWorkloadIdentity, AzureAuthModeSAS, AzureAuthModeDefault,
+		}, ", "))
+	}
+
+	if a.SASToken != "" && a.AuthMode != "" && a.AuthMode != AzureAuthModeSAS {
+		return fmt.Errorf("sas token can be used only with %s auth mode", AzureAuthModeSAS)
+	}
+
+	if a.FederatedTokenFile != "" && a.AuthMode != AzureAuthModeWorkloadIdentity {
+		return fmt.Errorf("federated token file can be used only with %s auth mode", AzureAuthModeWorkloadIdentity)
+	}
+
+	return nil
+}
@@ -154,6 +154,71 @@ func TestAzureBlob_Validate(t *testing.T) {
 			isBackup: true,
 			wantErr:  "",
 		},
+		{
+			name: "invalid auth mode",
+			azure: &AzureBlob{
+				ContainerName: testBucketName,
+				Endpoint:      testEndpoint,
+				AuthMode:      "token",
+			},
+			isBackup: true,
+			wantErr: "invalid auth mode token, must be one of shared-key, client-secret, managed-identity, " +
+				"workload-identity, sas, default",
+		},
+		{
+			name: "sas auth mode without sas token",
+			azure: &AzureBlob{
+				ContainerName: testBucketName,
+				Endpoint:      testEndpoint,
+				AuthMode:      AzureAuthModeSAS,
+			},
+			isBackup: true,
+			wantErr:  "sas token is required for sas auth mode",
+		},
+		{
+			name: "shared key auth mode without account key",
+			azure: &AzureBlob{
+				ContainerName: testBucketName,
+				Endpoint:      testEndpoint,
+				AccountName:   "account",
+				AuthMode:      AzureAuthModeSharedKey,
+			},
+			isBackup: true,
+			wantErr:  "account name and account key are required for shared-key auth mode",
+		},
+		{
+			name: "client secret auth mode without tenant id",
+			azure: &AzureBlob{
+				ContainerName: testBucketName,
+				Endpoint:      testEndpoint,
+				ClientID:      "client",
+				ClientSecret:  "secret",
+				AuthMode:      AzureAuthModeClientSecret,
+			},
+			isBackup: true,
+			wantErr:  "tenant id, client id and client secret are required for client-secret auth mode",
+		},
+		{
+			name: "sas token with managed identity auth mode",
+			azure: &AzureBlob{
+				ContainerName: testBucketName,
+				Endpoint:      testEndpoint,
+				SASToken:      "sv=2024-01-01&sig=abc",
+				AuthMode:      AzureAuthModeManagedIdentity,
+			},
+			isBackup: true,
+			wantErr:  "sas token can be used only with sas auth mode",
+		},
+		{
+			name: "federated token file without workload identity auth mode",
+			azure: &AzureBlob{
+				ContainerName:      testBucketName,
+				Endpoint:           testEndpoint,
+				FederatedTokenFile: "/var/run/secrets/token",
+			},
+			isBackup: true,
+			wantErr:  "federated token file can be used only with workload-identity auth mode",
+		},
 		{
 			name: "zero values are valid",
 			azure: &AzureBlob{
@@ -16,9 +16,33 @@ package models
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
+// StateFileName is the name of the state file, that is saved in the backup directory
+// when state-file-dst is not set.
+const StateFileName = "backup.state"
+
+// MaxShards is the maximum number of shards of a distributed backup, one partition per shard.
+const MaxShards = 4096
+
+const (
+	// RackFallbackFail fails the backup when a node of the backup rack is lost.
+	RackFallbackFail = "fail"
+	// RackFallbackWait waits for migrations to finish and continues from the nodes left in the backup rack.
+	RackFallbackWait = "wait"
+	// RackFallbackOtherRack reads partitions of the lost node from replicas on other racks.
+	RackFallbackOtherRack = "other-rack"
+)
+
+const (
+	// RetentionModeGovernance allows users with special permissions to remove or shorten the retention.
+	RetentionModeGovernance = "governance"
+	// RetentionModeCompliance doesn't allow anyone to remove or shorten the retention.
+	RetentionModeCompliance = "compliance"
+)
+
 // Backup flags that will be mapped to (scan) backup config.
 // (common for backup and restore flags are in Common).
 type Backup struct {
@@ -47,6 +71,31 @@ type Backup struct {
 	ScanPageSize        int64
 	OutputFilePrefix    string
 	RackList            string
+	// BackupRack pins the backup to a single rack, records are read only from nodes of this rack.
+	BackupRack string
+	// RackFallback is the policy that is applied when a node of the backup rack is lost.
+	RackFallback string
+	// RackWaitTimeout is the time in milliseconds to wait for migrations with the wait fallback policy.
+	RackWaitTimeout int64
+	// Shard is the part of a distributed backup in the <shard>/<shards> format, e.g. 1/4.
+	Shard string
+	// Resume continues the backup from the state file in the backup directory, if it exists.
+	Resume bool
+	// NoState disables the state file, that is saved by default.
+	NoState bool
+	// RetentionDays is the number of days backup files can't be removed or overwritten.
+	RetentionDays int
+	// RetentionMode is the mode of the retention, RetentionModeGovernance or RetentionModeCompliance.
+	RetentionMode string
+	// LegalHold protects backup files from removal until the hold is released.
+	LegalHold bool
+	// ParallelJobs is the number of jobs that run at the same time.
+	// Used only when jobs are configured in the config file.
+	ParallelJobs int
+	// PreflightOnly runs the storage preflight check and exits without the backup.
+	PreflightOnly bool
+	// SkipPreflight disables the storage preflight check.
+	SkipPreflight bool
 }
 
 // ShouldClearTarget check if we should clean target directory.
@@ -54,10 +103,25 @@ func (b *Backup) ShouldClearTarget() bool {
 	return (b.RemoveFiles || b.RemoveArtifacts) && b.Continue == ""
 }
 
+// HasRetention returns true if backup files must be protected from removal.
+func (b *Backup) HasRetention() bool {
+	return b.RetentionDays > 0 || b.LegalHold
+}
+
 func (b *Backup) ShouldSaveState() bool {
 	return b.StateFileDst != "" || b.Continue != ""
 }
 
+// SupportsState returns true if the state of the backup can be saved to a state file.
+func (b *Backup) SupportsState() bool {
+	return b.Directory != "" &&
+		b.FileLimit > 0 &&
+		!b.Estimate &&
+		b.NodeList == "" &&
+		b.RackList == "" &&
+		b.BackupRack == ""
+}
+
 //nolint:gocyclo // Long validation function.
 func (b *Backup) Validate() error {
 	if b == nil {
@@ -89,6 +153,26 @@ func (b *Backup) Validate() error {
 		return fmt.Errorf("continue and state-file-dst are mutually exclusive")
 	}
 
+	if err := b.validateResume(); err != nil {
+		return err
+	}
+
+	if err := b.validateBackupRack(); err != nil {
+		return err
+	}
+
+	if err := b.validateShard(); err != nil {
+		return err
+	}
+
+	if err := b.validateRetention(); err != nil {
+		return err
+	}
+
+	if err := b.validatePreflight(); err != nil {
+		return err
+	}
+
 	if b.Estimate {
 		// Estimate with filter not allowed.
 		if b.PartitionList != "" ||
@@ -114,6 +198,156 @@ func (b *Backup) Validate() error {
 	return b.Common.Validate()
 }
 
+// validateBackupRack validates the rack-pinned backup settings.
+func (b *Backup) validateBackupRack() error {
+	switch b.RackFallback {
+	case "", RackFallbackFail, RackFallbackWait, RackFallbackOtherRack:
+	default:
+		return fmt.Errorf("invalid rack-fallback %s, must be %s, %s or %s",
+			b.RackFallback, RackFallbackFail, RackFallbackWait, RackFallbackOtherRack)
+	}
+
+	if b.BackupRack == "" {
+		return nil
+	}
+
+	rack, err := strconv.Atoi(strings.TrimSpace(b.BackupRack))
+	if err != nil || rack < 0 {
+		return fmt.Errorf("invalid backup-rack %s, must be a single rack id", b.BackupRack)
+	}
+
+	if b.PreferRacks != "" || b.RackList != "" || b.NodeList != "" ||
+		b.PartitionList != "" || b.AfterDigest != "" {
+		return fmt.Errorf("backup-rack is not allowed with prefer-racks, rack-list, node-list, " +
+			"partition-list or after-digest")
+	}
+
+	if b.OutputFile != "" || b.Estimate {
+		return fmt.Errorf("backup-rack is not allowed with output-file or estimate")
+	}
+
+	if b.ShouldSaveState() {
+		return fmt.Errorf("backup-rack is not allowed with state-file-dst or continue")
+	}
+
+	if b.RackFallback == RackFallbackWait && b.RackWaitTimeout <= 0 {
+		return fmt.Errorf("rack-wait-timeout must be positive with rack-fallback %s", RackFallbackWait)
+	}
+
+	return nil
+}
+
+func (b *Backup) validateResume() error {
+	if b.NoState && (b.Resume || b.ShouldSaveState()) {
+		return fmt.Errorf("no-state is not allowed with resume, state-file-dst or continue")
+	}
+
+	if !b.Resume {
+		return nil
+	}
+
+	if b.Continue != "" {
+		return fmt.Errorf("resume and continue are mutually exclusive")
+	}
+
+	if !b.SupportsState() {
+		return fmt.Errorf("resume requires a backup to directory with file-limit, " +
+			"it is not allowed with estimate, node-list, rack-list or backup-rack")
+	}
+
+	return nil
+}
+
+func (b *Backup) validateRetention() error {
+	if b.RetentionDays < 0 {
+		return fmt.Errorf("retention-days can't be negative")
+	}
+
+	switch b.RetentionMode {
+	case "", RetentionModeGovernance, RetentionModeCompliance:
+	default:
+		return fmt.Errorf("invalid retention-mode %s, must be %s or %s",
+			b.RetentionMode, RetentionModeGovernance, RetentionModeCompliance)
+	}
+
+	if b.HasRetention() && (b.Estimate || b.RemoveArtifacts) {
+		return fmt.Errorf("retention-days and legal-hold are not allowed with estimate or remove-artifacts")
+	}
+
+	return nil
+}
+
+func (b *Backup) validatePreflight() error {
+	if b.PreflightOnly && b.SkipPreflight {
+		return fmt.Errorf("preflight-only and skip-preflight can't be used together")
+	}
+
+	if b.PreflightOnly && (b.Estimate || b.RemoveArtifacts || b.OutputFile == "-") {
+		return fmt.Errorf("preflight-only is not allowed with estimate, remove-artifacts or output to stdout")
+	}
+
+	return nil
+}
+
+// ParseShard returns the shard number and the number of shards.
+// Returns zeros if the backup is not sharded.
+func (b *Backup) ParseShard() (shard, shards int, err error) {
+	if b.Shard == "" {
+		return 0, 0, nil
+	}
+
+	shardStr, shardsStr, ok := strings.Cut(b.Shard, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid shard %s, must be in <shard>/<shards> format, e.g. 1/4", b.Shard)
+	}
+
+	shard, err = strconv.Atoi(shardStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard %s: %w", b.Shard, err)
+	}
+
+	shards, err = strconv.Atoi(shardsStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard %s: %w", b.Shard, err)
+	}
+
+	if shards < 1 || shards > MaxShards {
+		return 0, 0, fmt.Errorf("invalid shard %s, number of shards must be between 1 and %d", b.Shard, MaxShards)
+	}
+
+	if shard < 1 || shard > shards {
+		return 0, 0, fmt.Errorf("invalid shard %s, shard must be between 1 and %d", b.Shard, shards)
+	}
+
+	return shard, shards, nil
+}
+
+// validateShard validates the distributed backup settings.
+func (b *Backup) validateShard() error {
+	if b.Shard == "" {
+		return nil
+	}
+
+	if _, _, err := b.ParseShard(); err != nil {
+		return err
+	}
+
+	if b.PartitionList != "" || b.AfterDigest != "" || b.NodeList != "" || b.RackList != "" || b.BackupRack != "" {
+		return fmt.Errorf("shard is not allowed with partition-list, after-digest, node-list, rack-list or backup-rack")
+	}
+
+	if b.OutputFile != "" || b.Estimate {
+		return fmt.Errorf("shard is not allowed with output-file or estimate")
+	}
+
+	// Other shards write to the same directory.
+	if b.ShouldClearTarget() {
+		return fmt.Errorf("shard is not allowed with remove-files or remove-artifacts")
+	}
+
+	return nil
+}
+
 // validateSingleFilter ensures only one filtering option is specified.
 func (b *Backup) validateSingleFilter() error {
 	filtersSet := 0
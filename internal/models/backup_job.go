@@ -0,0 +1,89 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"path"
+)
+
+// BackupJob represents a single job of a multi-job backup.
+// Backup contains the resolved parameters of the job: values from the common backup section
+// with the job-specific fields applied on top.
+type BackupJob struct {
+	Name   string
+	Backup *Backup
+}
+
+// ValidateBackupJobs validates each job and checks that jobs don't write to the same location.
+func ValidateBackupJobs(jobs []*BackupJob, parallelJobs int) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	if parallelJobs < 1 {
+		return fmt.Errorf("parallel jobs can't be less than 1")
+	}
+
+	names := make(map[string]struct{}, len(jobs))
+	directories := make(map[string]string, len(jobs))
+
+	for i, job := range jobs {
+		if job == nil || job.Backup == nil {
+			return fmt.Errorf("job %d is empty", i)
+		}
+
+		if job.Name == "" {
+			return fmt.Errorf("job %d name is required", i)
+		}
+
+		if _, ok := names[job.Name]; ok {
+			return fmt.Errorf("duplicate job name %s", job.Name)
+		}
+
+		names[job.Name] = struct{}{}
+
+		if job.Backup.Directory == "" {
+			return fmt.Errorf("job %s: directory is required", job.Name)
+		}
+
+		if job.Backup.Estimate {
+			return fmt.Errorf("job %s: estimate is not allowed for multi-job backup", job.Name)
+		}
+
+		// Shard markers and the rack pinning are handled only by a single backup.
+		if job.Backup.Shard != "" {
+			return fmt.Errorf("job %s: shard is not allowed for multi-job backup", job.Name)
+		}
+
+		if job.Backup.BackupRack != "" {
+			return fmt.Errorf("job %s: backup-rack is not allowed for multi-job backup", job.Name)
+		}
+
+		if err := job.Backup.Validate(); err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+
+		// Each writer checks and may clean its directory, so jobs can't share it.
+		directory := path.Clean(job.Backup.Directory)
+		if other, ok := directories[directory]; ok {
+			return fmt.Errorf("jobs %s and %s write to the same directory %s", other, job.Name, job.Backup.Directory)
+		}
+
+		directories[directory] = job.Name
+	}
+
+	return nil
+}
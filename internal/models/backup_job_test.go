@@ -0,0 +1,132 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testBackupJob(name, directory string) *BackupJob {
+	return &BackupJob{
+		Name: name,
+		Backup: &Backup{
+			Common: Common{
+				Namespace: testNamespace,
+				Directory: directory,
+			},
+		},
+	}
+}
+
+func TestValidateBackupJobs(t *testing.T) {
+	t.Parallel()
+
+	estimateJob := testBackupJob("estimate", "estimate")
+	estimateJob.Backup.Estimate = true
+
+	shardJob := testBackupJob("shard", "shard")
+	shardJob.Backup.Shard = "1/2"
+
+	rackJob := testBackupJob("rack", "rack")
+	rackJob.Backup.BackupRack = "1"
+
+	tests := []struct {
+		name         string
+		jobs         []*BackupJob
+		parallelJobs int
+		expectedErr  string
+	}{
+		{
+			name:         "No jobs",
+			jobs:         nil,
+			parallelJobs: 0,
+		},
+		{
+			name:         "Valid jobs",
+			jobs:         []*BackupJob{testBackupJob("a", "dir-a"), testBackupJob("b", "dir-b")},
+			parallelJobs: 2,
+		},
+		{
+			name:         "Invalid parallel jobs",
+			jobs:         []*BackupJob{testBackupJob("a", "dir-a")},
+			parallelJobs: 0,
+			expectedErr:  "parallel jobs can't be less than 1",
+		},
+		{
+			name:         "Empty job",
+			jobs:         []*BackupJob{nil},
+			parallelJobs: 1,
+			expectedErr:  "job 0 is empty",
+		},
+		{
+			name:         "Empty name",
+			jobs:         []*BackupJob{testBackupJob("", "dir-a")},
+			parallelJobs: 1,
+			expectedErr:  "job 0 name is required",
+		},
+		{
+			name:         "Duplicate name",
+			jobs:         []*BackupJob{testBackupJob("a", "dir-a"), testBackupJob("a", "dir-b")},
+			parallelJobs: 1,
+			expectedErr:  "duplicate job name a",
+		},
+		{
+			name:         "Missing directory",
+			jobs:         []*BackupJob{testBackupJob("a", "")},
+			parallelJobs: 1,
+			expectedErr:  "job a: directory is required",
+		},
+		{
+			name:         "Estimate",
+			jobs:         []*BackupJob{estimateJob},
+			parallelJobs: 1,
+			expectedErr:  "job estimate: estimate is not allowed for multi-job backup",
+		},
+		{
+			name:         "Shard",
+			jobs:         []*BackupJob{shardJob},
+			parallelJobs: 1,
+			expectedErr:  "job shard: shard is not allowed for multi-job backup",
+		},
+		{
+			name:         "Backup rack",
+			jobs:         []*BackupJob{rackJob},
+			parallelJobs: 1,
+			expectedErr:  "job rack: backup-rack is not allowed for multi-job backup",
+		},
+		{
+			name:         "Same directory",
+			jobs:         []*BackupJob{testBackupJob("a", "dir"), testBackupJob("b", "dir/")},
+			parallelJobs: 1,
+			expectedErr:  "jobs a and b write to the same directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateBackupJobs(tt.jobs, tt.parallelJobs)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
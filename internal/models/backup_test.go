@@ -180,6 +180,148 @@ func TestValidateBackup(t *testing.T) {
 
 			wantErr: false,
 		},
+		{
+			name: "Resume with directory",
+			backup: &Backup{
+				Resume:    true,
+				FileLimit: 250,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Resume with continue",
+			backup: &Backup{
+				Resume:    true,
+				Continue:  "state.json",
+				FileLimit: 250,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr:     true,
+			expectedErr: "resume and continue are mutually exclusive",
+		},
+		{
+			name: "Resume with node list",
+			backup: &Backup{
+				Resume:    true,
+				NodeList:  "node1",
+				FileLimit: 250,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr: true,
+			expectedErr: "resume requires a backup to directory with file-limit, " +
+				"it is not allowed with estimate, node-list, rack-list or backup-rack",
+		},
+		{
+			name: "No state with state file dst",
+			backup: &Backup{
+				NoState:      true,
+				StateFileDst: "state.json",
+				FileLimit:    250,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr:     true,
+			expectedErr: "no-state is not allowed with resume, state-file-dst or continue",
+		},
+		{
+			name: "Retention with compliance mode",
+			backup: &Backup{
+				RetentionDays: 30,
+				RetentionMode: RetentionModeCompliance,
+				LegalHold:     true,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Retention with invalid mode",
+			backup: &Backup{
+				RetentionDays: 30,
+				RetentionMode: "locked",
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr:     true,
+			expectedErr: "invalid retention-mode locked, must be governance or compliance",
+		},
+		{
+			name: "Negative retention days",
+			backup: &Backup{
+				RetentionDays: -1,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr:     true,
+			expectedErr: "retention-days can't be negative",
+		},
+		{
+			name: "Legal hold with remove artifacts",
+			backup: &Backup{
+				LegalHold:       true,
+				RemoveArtifacts: true,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr:     true,
+			expectedErr: "retention-days and legal-hold are not allowed with estimate or remove-artifacts",
+		},
+		{
+			name: "Preflight only with skip preflight",
+			backup: &Backup{
+				PreflightOnly: true,
+				SkipPreflight: true,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr:     true,
+			expectedErr: "preflight-only and skip-preflight can't be used together",
+		},
+		{
+			name: "Preflight only with estimate",
+			backup: &Backup{
+				PreflightOnly: true,
+				Estimate:      true,
+				Common: Common{
+					Namespace: testNamespace,
+				},
+			},
+			wantErr:     true,
+			expectedErr: "preflight-only is not allowed with estimate, remove-artifacts or output to stdout",
+		},
+		{
+			name: "Preflight only",
+			backup: &Backup{
+				PreflightOnly: true,
+				Common: Common{
+					Directory: testDir,
+					Namespace: testNamespace,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "NodeList with parallel nodes",
 			backup: &Backup{
@@ -287,6 +429,120 @@ func TestValidateBackup(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "using output-file-prefix is not allowed with output-file",
 		},
+		{
+			name: "Backup rack with fallback",
+			backup: &Backup{
+				BackupRack:      "1",
+				RackFallback:    RackFallbackWait,
+				RackWaitTimeout: 1000,
+				Common:          Common{Directory: testDir, Namespace: testNamespace},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid backup rack",
+			backup: &Backup{
+				BackupRack: "1,2",
+				Common:     Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "invalid backup-rack 1,2, must be a single rack id",
+		},
+		{
+			name: "Backup rack with prefer racks",
+			backup: &Backup{
+				BackupRack:  "1",
+				PreferRacks: "2",
+				Common:      Common{Directory: testDir},
+			},
+			wantErr: true,
+			expectedErr: "backup-rack is not allowed with prefer-racks, rack-list, node-list, " +
+				"partition-list or after-digest",
+		},
+		{
+			name: "Backup rack with state file",
+			backup: &Backup{
+				BackupRack:   "1",
+				StateFileDst: "state",
+				Common:       Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "backup-rack is not allowed with state-file-dst or continue",
+		},
+		{
+			name: "Invalid rack fallback",
+			backup: &Backup{
+				RackFallback: "any",
+				Common:       Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "invalid rack-fallback any, must be fail, wait or other-rack",
+		},
+		{
+			name: "Shard",
+			backup: &Backup{
+				Shard:  "2/4",
+				Common: Common{Directory: testDir, Namespace: testNamespace},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid shard format",
+			backup: &Backup{
+				Shard:  "2",
+				Common: Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "invalid shard 2, must be in <shard>/<shards> format, e.g. 1/4",
+		},
+		{
+			name: "Shard out of range",
+			backup: &Backup{
+				Shard:  "5/4",
+				Common: Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "invalid shard 5/4, shard must be between 1 and 4",
+		},
+		{
+			name: "Too many shards",
+			backup: &Backup{
+				Shard:  "1/5000",
+				Common: Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "invalid shard 1/5000, number of shards must be between 1 and 4096",
+		},
+		{
+			name: "Shard with partition list",
+			backup: &Backup{
+				Shard:         "1/4",
+				PartitionList: "0-100",
+				Common:        Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "shard is not allowed with partition-list, after-digest, node-list, rack-list or backup-rack",
+		},
+		{
+			name: "Shard with remove files",
+			backup: &Backup{
+				Shard:       "1/4",
+				RemoveFiles: true,
+				Common:      Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "shard is not allowed with remove-files or remove-artifacts",
+		},
+		{
+			name: "Wait fallback without timeout",
+			backup: &Backup{
+				BackupRack:   "1",
+				RackFallback: RackFallbackWait,
+				Common:       Common{Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "rack-wait-timeout must be positive with rack-fallback wait",
+		},
 	}
 
 	for _, tt := range tests {
@@ -16,6 +16,7 @@ package models
 
 import (
 	"fmt"
+	"time"
 )
 
 // BackupXDR flags that will be mapped to xdr backup config.
@@ -47,6 +48,15 @@ type BackupXDR struct {
 	InfoRetryIntervalMilliseconds int64
 
 	Forward bool
+
+	// RotateInterval enables continuous backup. The backup is restarted every interval,
+	// each run is written to a new segment directory and saved to a checkpoint.
+	RotateInterval time.Duration
+	// Scan takes a scan backup after the continuous backup is started, for point-in-time recovery.
+	Scan bool
+	// CleanupStateFile is a local file, where changes made by the backup on the cluster are saved.
+	// If the backup is interrupted, they are undone on the next start. If empty, a file in the temp directory is used.
+	CleanupStateFile string
 }
 
 func (b *BackupXDR) Validate() error {
@@ -110,5 +120,17 @@ func (b *BackupXDR) Validate() error {
 		return fmt.Errorf("backup xdr info retries multiplier can't be negative")
 	}
 
+	if b.RotateInterval < 0 {
+		return fmt.Errorf("backup xdr rotate interval can't be negative")
+	}
+
+	if b.RotateInterval > 0 && b.RotateInterval < time.Second {
+		return fmt.Errorf("backup xdr rotate interval can't be less than 1s")
+	}
+
+	if b.Scan && b.RotateInterval == 0 {
+		return fmt.Errorf("backup xdr scan requires rotate interval")
+	}
+
 	return nil
 }
@@ -16,6 +16,7 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -239,6 +240,45 @@ func Test_validateBackupXDR(t *testing.T) {
 			},
 			wantErr: "backup xdr info retries multiplier can't be negative",
 		},
+		{
+			name: "negative rotate interval",
+			backup: &BackupXDR{
+				Namespace:      testNamespace,
+				DC:             testDC,
+				LocalAddress:   testLocalAddress,
+				MaxConnections: 1,
+				ParallelWrite:  1,
+				FileLimit:      1,
+				RotateInterval: -time.Minute,
+			},
+			wantErr: "backup xdr rotate interval can't be negative",
+		},
+		{
+			name: "too small rotate interval",
+			backup: &BackupXDR{
+				Namespace:      testNamespace,
+				DC:             testDC,
+				LocalAddress:   testLocalAddress,
+				MaxConnections: 1,
+				ParallelWrite:  1,
+				FileLimit:      1,
+				RotateInterval: time.Millisecond,
+			},
+			wantErr: "backup xdr rotate interval can't be less than 1s",
+		},
+		{
+			name: "scan without rotate interval",
+			backup: &BackupXDR{
+				Namespace:      testNamespace,
+				DC:             testDC,
+				LocalAddress:   testLocalAddress,
+				MaxConnections: 1,
+				ParallelWrite:  1,
+				FileLimit:      1,
+				Scan:           true,
+			},
+			wantErr: "backup xdr scan requires rotate interval",
+		},
 	}
 
 	for _, tt := range tests {
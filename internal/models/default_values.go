@@ -14,6 +14,8 @@
 
 package models
 
+import "time"
+
 // App.
 const (
 	DefaultAppHelp           = false
@@ -32,6 +34,14 @@ const (
 	DefaultS3Endpoint            = ""
 	DefaultS3AccessKeyID         = ""
 	DefaultS3SecretAccessKey     = ""
+	DefaultS3RoleArn             = ""
+	DefaultS3ExternalID          = ""
+	DefaultS3SessionName         = ""
+	DefaultS3WebIdentityToken    = ""
+	DefaultS3SSE                 = ""
+	DefaultS3AddressingStyle     = S3AddressingStylePath
+	DefaultS3SSEKmsKeyID         = ""
+	DefaultS3SSECustomerKey      = ""
 	DefaultS3StorageClass        = ""
 	DefaultS3AccessTier          = ""
 	DefaultS3RestorePollDuration = int64(60000)
@@ -48,6 +58,9 @@ const (
 	DefaultAzureTenantID            = ""
 	DefaultAzureClientID            = ""
 	DefaultAzureClientSecret        = ""
+	DefaultAzureSASToken            = ""
+	DefaultAzureFederatedTokenFile  = ""
+	DefaultAzureAuthMode            = ""
 	DefaultAzureEndpoint            = ""
 	DefaultAzureContainerName       = ""
 	DefaultAzureAccessTier          = ""
@@ -62,6 +75,7 @@ const (
 // Gpc Storage.
 const (
 	DefaultGcpKeyFile                = ""
+	DefaultGcpImpersonateAccount     = ""
 	DefaultGcpBucketName             = ""
 	DefaultGcpEndpoint               = ""
 	DefaultGcpRetryMaxAttempts       = 10
@@ -71,15 +85,43 @@ const (
 	DefaultGcpChunkSize              = 5
 )
 
+// SFTP Storage.
+const (
+	DefaultSftpHost           = ""
+	DefaultSftpUser           = ""
+	DefaultSftpPassword       = ""
+	DefaultSftpKeyFile        = ""
+	DefaultSftpKeyPassphrase  = ""
+	DefaultSftpUseAgent       = false
+	DefaultSftpKnownHostsFile = ""
+	DefaultSftpConnections    = 4
+	DefaultSftpConnectTimeout = 30000
+	DefaultSftpBufferSize     = 5
+)
+
+// Adaptive throttle.
+const (
+	DefaultAdaptiveCheckInterval    = int64(10000)
+	DefaultAdaptiveLatencyThreshold = int64(8)
+	DefaultAdaptiveMinRatePct       = int64(10)
+)
+
 // Local Storage.
 const (
-	DefaultLocalBufferSize = 5
+	DefaultLocalBufferSize      = 5
+	DefaultLocalReadAhead       = 0
+	DefaultLocalDropCache       = false
+	DefaultLocalOpenConcurrency = 0
 )
 
 // Cloud common.
 const (
 	DefaultCloudMaxConnsPerHost      = 0
 	DefaultCloudRequestTimeout       = 600000
+	DefaultCloudTLSCAFile            = ""
+	DefaultCloudTLSCertFile          = ""
+	DefaultCloudTLSKeyFile           = ""
+	DefaultCloudProxyURL             = ""
 	DefaultCloudCalculateChecksum    = false
 	DefaultCloudRetryReadBackoff     = 1000
 	DefaultCloudRetryReadMultiplier  = float64(2.0)
@@ -162,11 +204,23 @@ const (
 	DefaultBackupStateFileDst        = ""
 	DefaultBackupContinue            = ""
 	DefaultBackupScanPageSize        = 10000
+	DefaultBackupResume              = false
+	DefaultBackupNoState             = false
 	DefaultBackupOutputFilePrefix    = ""
 	DefaultBackupRackList            = ""
+	DefaultBackupBackupRack          = ""
+	DefaultBackupRackFallback        = RackFallbackFail
+	DefaultBackupRackWaitTimeout     = 600000
+	DefaultBackupShard               = ""
+	DefaultBackupRetentionDays       = 0
+	DefaultBackupRetentionMode       = RetentionModeGovernance
+	DefaultBackupLegalHold           = false
 	DefaultBackupTotalTimeout        = 0
 	DefaultBackupParallel            = 1
 	DefaultBackupMaxRetries          = 5
+	DefaultBackupParallelJobs        = 1
+	DefaultBackupPreflightOnly       = false
+	DefaultBackupSkipPreflight       = false
 )
 
 // Restore.
@@ -188,9 +242,14 @@ const (
 	DefaultRestoreRetryBaseInterval  = 1000
 	DefaultRestoreRetryMultiplier    = 1.0
 	DefaultRestoreRetryMaxAttempts   = 0
+	DefaultRestoreMode               = RestoreModeAuto
 
 	DefaultRestoreValidateOnly      = false
 	DefaultRestoreApplyMetadataLast = false
+	DefaultRestorePointInTime       = ""
+	DefaultRestoreStateFileDst      = ""
+	DefaultRestoreContinue          = ""
+	DefaultRestoreStateInterval     = 60000
 )
 
 const (
@@ -218,4 +277,24 @@ const (
 	DefaultBackupXDRInfoRetriesMultiplier = 1.0
 	DefaultBackupXDRInfoRetryInterval     = 1000
 	DefaultBackupXDRForward               = false
+	DefaultBackupXDRRotateInterval        = 0
+	DefaultBackupXDRScan                  = false
+	DefaultBackupXDRCleanupStateFile      = ""
+)
+
+const (
+	DefaultXDRStatusMaxLag    = 0
+	DefaultXDRStatusAllowIdle = false
+)
+
+const (
+	DefaultXDRCleanupDryRun = false
+)
+
+const (
+	DefaultInspectDirectory         = ""
+	DefaultInspectInputFile         = ""
+	DefaultInspectHistogramInterval = time.Minute
+	DefaultInspectDump              = false
+	DefaultInspectDumpLimit         = 0
 )
@@ -22,4 +22,6 @@ const (
 
 var (
 	ErrNodeNotFound = errors.New(ErrNodeNotFoundText)
+	// ErrXDRUnhealthy is returned by xdr status, if the DC is not healthy.
+	ErrXDRUnhealthy = errors.New("xdr is unhealthy")
 )
@@ -22,8 +22,11 @@ import (
 
 // GcpStorage represents the configuration for GCP storage integration.
 type GcpStorage struct {
-	// Path to file containing Service Account JSON Key.
+	// Path to file containing Service Account JSON Key, or other credentials JSON,
+	// e.g. external account config for workload identity federation.
 	KeyFile string
+	// Service account to impersonate, base credentials must have the Token Creator role on it.
+	ImpersonateServiceAccount string
 	// For GPC storage bucket is not part of the path as in S3.
 	// So we should set it separately.
 	BucketName string
@@ -50,6 +53,11 @@ func (g *GcpStorage) LoadSecrets(cfg *backup.SecretAgentConfig) error {
 		return fmt.Errorf("failed to load key file from secret agent: %w", err)
 	}
 
+	g.ImpersonateServiceAccount, err = backup.ParseSecret(cfg, g.ImpersonateServiceAccount)
+	if err != nil {
+		return fmt.Errorf("failed to load impersonate service account from secret agent: %w", err)
+	}
+
 	g.BucketName, err = backup.ParseSecret(cfg, g.BucketName)
 	if err != nil {
 		return fmt.Errorf("failed to load bucket name from secret agent: %w", err)
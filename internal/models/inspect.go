@@ -0,0 +1,56 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Inspect flags that are used to inspect .asbx files of an xdr backup.
+type Inspect struct {
+	Directory string
+	InputFile string
+	// HistogramInterval is the width of the histogram buckets of changes over time.
+	HistogramInterval time.Duration
+	// Dump prints each change as a JSON line to stdout.
+	Dump bool
+	// DumpLimit is the maximum number of dumped changes. 0 means no limit.
+	DumpLimit int64
+}
+
+func (i *Inspect) Validate() error {
+	if i == nil {
+		return nil
+	}
+
+	if i.Directory == "" && i.InputFile == "" {
+		return fmt.Errorf("directory or input file is required")
+	}
+
+	if i.Directory != "" && i.InputFile != "" {
+		return fmt.Errorf("only one of directory and input file can be set")
+	}
+
+	if i.HistogramInterval <= 0 {
+		return fmt.Errorf("inspect histogram interval must be positive")
+	}
+
+	if i.DumpLimit < 0 {
+		return fmt.Errorf("inspect dump limit can't be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		inspect *Inspect
+		wantErr string
+	}{
+		{
+			name:    "valid directory",
+			inspect: &Inspect{Directory: "backup", HistogramInterval: time.Minute},
+		},
+		{
+			name:    "valid input file",
+			inspect: &Inspect{InputFile: "0_test_1.asbx", HistogramInterval: time.Minute, Dump: true, DumpLimit: 10},
+		},
+		{
+			name: "nil",
+		},
+		{
+			name:    "missing directory and input file",
+			inspect: &Inspect{HistogramInterval: time.Minute},
+			wantErr: "directory or input file is required",
+		},
+		{
+			name:    "both directory and input file",
+			inspect: &Inspect{Directory: "backup", InputFile: "0_test_1.asbx", HistogramInterval: time.Minute},
+			wantErr: "only one of directory and input file can be set",
+		},
+		{
+			name:    "zero histogram interval",
+			inspect: &Inspect{Directory: "backup"},
+			wantErr: "inspect histogram interval must be positive",
+		},
+		{
+			name:    "negative dump limit",
+			inspect: &Inspect{Directory: "backup", HistogramInterval: time.Minute, DumpLimit: -1},
+			wantErr: "inspect dump limit can't be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.inspect.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
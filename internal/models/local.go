@@ -18,14 +18,32 @@ import "fmt"
 
 // Local represents local storage.
 type Local struct {
+	// BufferSize in MiB is used for writes on backup and for reads on restore.
 	BufferSize int
+	// ReadAhead is the number of buffers read in advance for each file on restore.
+	ReadAhead int
+	// DropCache advises the kernel to read files sequentially on restore and to drop
+	// the read data from the page cache. Reads still go through the page cache.
+	DropCache bool
+	// OpenConcurrency is the number of files opened and prefetched ahead of restore workers.
+	OpenConcurrency int
 }
 
 func (l *Local) Validate(isBackup bool) error {
+	if l.BufferSize < 1 {
+		return fmt.Errorf("buffer size can't be less than 1")
+	}
+
 	if isBackup {
-		if l.BufferSize < 1 {
-			return fmt.Errorf("buffer size can't be less than 1")
-		}
+		return nil
+	}
+
+	if l.ReadAhead < 0 {
+		return fmt.Errorf("read ahead can't be negative")
+	}
+
+	if l.OpenConcurrency < 0 {
+		return fmt.Errorf("open concurrency can't be negative")
 	}
 
 	return nil
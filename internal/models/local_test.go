@@ -22,11 +22,13 @@ import (
 
 func TestLocal_Validate(t *testing.T) {
 	tests := []struct {
-		name       string
-		bufferSize int
-		isBackup   bool
-		wantErr    bool
-		errMsg     string
+		name            string
+		bufferSize      int
+		readAhead       int
+		openConcurrency int
+		isBackup        bool
+		wantErr         bool
+		errMsg          string
 	}{
 		{
 			name:       "backup with positive buffer size",
@@ -57,14 +59,41 @@ func TestLocal_Validate(t *testing.T) {
 			name:       "restore with negative buffer size",
 			bufferSize: -1,
 			isBackup:   false,
-			wantErr:    false, // validation not applied for restore
+			wantErr:    true,
+			errMsg:     "buffer size can't be less than 1",
+		},
+		{
+			name:       "restore with negative read ahead",
+			bufferSize: 1,
+			readAhead:  -1,
+			isBackup:   false,
+			wantErr:    true,
+			errMsg:     "read ahead can't be negative",
+		},
+		{
+			name:            "restore with negative open concurrency",
+			bufferSize:      1,
+			openConcurrency: -1,
+			isBackup:        false,
+			wantErr:         true,
+			errMsg:          "open concurrency can't be negative",
+		},
+		{
+			name:            "backup ignores restore fields",
+			bufferSize:      1,
+			readAhead:       -1,
+			openConcurrency: -1,
+			isBackup:        true,
+			wantErr:         false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			l := &Local{
-				BufferSize: tt.bufferSize,
+				BufferSize:      tt.bufferSize,
+				ReadAhead:       tt.readAhead,
+				OpenConcurrency: tt.openConcurrency,
 			}
 
 			err := l.Validate(tt.isBackup)
@@ -14,7 +14,10 @@
 
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	RestoreModeAuto = "auto"
@@ -49,12 +52,50 @@ type Restore struct {
 
 	ValidateOnly      bool
 	ApplyMetadataLast bool
+
+	// PointInTime limits restore of a continuous xdr backup to changes made before it, in RFC3339 format.
+	PointInTime string
+
+	// StateFileDst is the name of a state file in the restore directory, where restored files are saved.
+	StateFileDst string
+	// Continue is the name of a state file of an interrupted restore, files saved in it are skipped.
+	Continue string
+	// StateInterval is the interval in milliseconds between state saves.
+	StateInterval int64
 }
 
 func (r *Restore) IsDirectoryRestore() bool {
 	return r.DirectoryList == "" && r.InputFile == ""
 }
 
+// GetPointInTime returns the parsed point in time, or zero time if it is not set.
+func (r *Restore) GetPointInTime() (time.Time, error) {
+	if r.PointInTime == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, r.PointInTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid point in time %s, must be in RFC3339 format", r.PointInTime)
+	}
+
+	return t, nil
+}
+
+// ShouldSaveState returns true if restored files must be saved to a state file.
+func (r *Restore) ShouldSaveState() bool {
+	return r.StateFileDst != "" || r.Continue != ""
+}
+
+// StateFile returns the name of the state file to read and save.
+func (r *Restore) StateFile() string {
+	if r.Continue != "" {
+		return r.Continue
+	}
+
+	return r.StateFileDst
+}
+
 func (r *Restore) Validate() error {
 	if r == nil {
 		return nil
@@ -85,6 +126,18 @@ func (r *Restore) Validate() error {
 		return fmt.Errorf("must specify directory-list list")
 	}
 
+	if r.PointInTime != "" && r.Directory == "" {
+		return fmt.Errorf("point in time can be used only with directory")
+	}
+
+	if _, err := r.GetPointInTime(); err != nil {
+		return err
+	}
+
+	if err := r.validateState(); err != nil {
+		return err
+	}
+
 	if r.WarmUp < 0 {
 		return fmt.Errorf("warm-up must be non-negative")
 	}
@@ -98,3 +151,27 @@ func (r *Restore) Validate() error {
 
 	return nil
 }
+
+func (r *Restore) validateState() error {
+	if !r.ShouldSaveState() {
+		return nil
+	}
+
+	if r.Continue != "" && r.StateFileDst != "" {
+		return fmt.Errorf("continue and state-file-dst are mutually exclusive")
+	}
+
+	if r.Directory == "" {
+		return fmt.Errorf("state file can be used only with directory")
+	}
+
+	if r.ValidateOnly || r.ApplyMetadataLast {
+		return fmt.Errorf("state file is not allowed with validate or apply-metadata-last")
+	}
+
+	if r.StateInterval <= 0 {
+		return fmt.Errorf("state-interval must be positive")
+	}
+
+	return nil
+}
@@ -99,6 +99,115 @@ func TestValidateRestore(t *testing.T) {
 			wantErr: true,
 			errMsg:  "only one of directory and input-file may be configured at the same time",
 		},
+		{
+			name: "Valid restore configuration with point in time",
+			restore: &Restore{
+				Mode:        RestoreModeAuto,
+				PointInTime: "2024-05-01T10:00:00Z",
+				Common: Common{
+					Directory: "restore-dir",
+					Namespace: "test",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid point in time format",
+			restore: &Restore{
+				Mode:        RestoreModeAuto,
+				PointInTime: "2024-05-01 10:00:00",
+				Common: Common{
+					Directory: "restore-dir",
+					Namespace: "test",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid point in time 2024-05-01 10:00:00, must be in RFC3339 format",
+		},
+		{
+			name: "Invalid point in time with input file",
+			restore: &Restore{
+				InputFile:   "backup.asb",
+				Mode:        RestoreModeAuto,
+				PointInTime: "2024-05-01T10:00:00Z",
+				Common: Common{
+					Namespace: "test",
+				},
+			},
+			wantErr: true,
+			errMsg:  "point in time can be used only with directory",
+		},
+		{
+			name: "Valid restore with state file",
+			restore: &Restore{
+				Mode:          RestoreModeAuto,
+				StateFileDst:  "restore.state",
+				StateInterval: 60000,
+				Common: Common{
+					Directory: "restore-dir",
+					Namespace: "test",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid restore with state file and continue",
+			restore: &Restore{
+				Mode:          RestoreModeAuto,
+				StateFileDst:  "restore.state",
+				Continue:      "restore.state",
+				StateInterval: 60000,
+				Common: Common{
+					Directory: "restore-dir",
+					Namespace: "test",
+				},
+			},
+			wantErr: true,
+			errMsg:  "continue and state-file-dst are mutually exclusive",
+		},
+		{
+			name: "Invalid restore with continue and input file",
+			restore: &Restore{
+				InputFile:     "backup.asb",
+				Mode:          RestoreModeASB,
+				Continue:      "restore.state",
+				StateInterval: 60000,
+				Common: Common{
+					Namespace: "test",
+				},
+			},
+			wantErr: true,
+			errMsg:  "state file can be used only with directory",
+		},
+		{
+			name: "Invalid restore with state file and apply metadata last",
+			restore: &Restore{
+				Mode:              RestoreModeAuto,
+				StateFileDst:      "restore.state",
+				StateInterval:     60000,
+				ApplyMetadataLast: true,
+				Common: Common{
+					Directory: "restore-dir",
+					Namespace: "test",
+				},
+			},
+			wantErr: true,
+			errMsg:  "state file is not allowed with validate or apply-metadata-last",
+		},
+		{
+			name: "Invalid restore with zero state interval",
+			restore: &Restore{
+				Mode:          RestoreModeAuto,
+				Continue:      "restore.state",
+				StateInterval: 0,
+				Common: Common{
+					Directory: "restore-dir",
+					Namespace: "test",
+				},
+			},
+			wantErr: true,
+			errMsg:  "state-interval must be positive",
+		},
 		{
 			name: "Invalid common restore - missing namespace",
 			restore: &Restore{
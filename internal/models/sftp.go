@@ -0,0 +1,114 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/aerospike/backup-go"
+)
+
+// Sftp represents the configuration for storage on a remote host, accessed over SFTP.
+type Sftp struct {
+	// Host of the SSH server, with an optional port.
+	Host string
+	User string
+	// Password for password authorization.
+	Password string
+	// KeyFile is the private key for public key authorization, it is decrypted with KeyPassphrase if set.
+	KeyFile       string
+	KeyPassphrase string
+	// UseAgent enables authorization with keys of the SSH agent from SSH_AUTH_SOCK.
+	UseAgent bool
+	// KnownHostsFile is used to verify the host key. If empty, ~/.ssh/known_hosts is used.
+	KnownHostsFile string
+
+	// Connections is the number of SSH connections, files are uploaded and downloaded in parallel over them.
+	Connections int
+	// ConnectTimeout (in ms) limits the time of establishing an SSH connection.
+	ConnectTimeout int
+	// BufferSize (in MiB) is the size of the buffer for each uploaded file.
+	BufferSize int
+}
+
+// LoadSecrets tries to load field values from secret agent.
+func (s *Sftp) LoadSecrets(cfg *backup.SecretAgentConfig) error {
+	var err error
+
+	s.Host, err = backup.ParseSecret(cfg, s.Host)
+	if err != nil {
+		return fmt.Errorf("failed to load host from secret agent: %w", err)
+	}
+
+	s.User, err = backup.ParseSecret(cfg, s.User)
+	if err != nil {
+		return fmt.Errorf("failed to load user from secret agent: %w", err)
+	}
+
+	s.Password, err = backup.ParseSecret(cfg, s.Password)
+	if err != nil {
+		return fmt.Errorf("failed to load password from secret agent: %w", err)
+	}
+
+	s.KeyFile, err = backup.ParseSecret(cfg, s.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key file from secret agent: %w", err)
+	}
+
+	s.KeyPassphrase, err = backup.ParseSecret(cfg, s.KeyPassphrase)
+	if err != nil {
+		return fmt.Errorf("failed to load key passphrase from secret agent: %w", err)
+	}
+
+	s.KnownHostsFile, err = backup.ParseSecret(cfg, s.KnownHostsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load known hosts file from secret agent: %w", err)
+	}
+
+	return nil
+}
+
+// Validate internal validation for struct params.
+func (s *Sftp) Validate(isBackup bool) error {
+	if s.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+
+	if s.User == "" {
+		return fmt.Errorf("user is required")
+	}
+
+	if s.Password == "" && s.KeyFile == "" && !s.UseAgent {
+		return fmt.Errorf("password, key file or agent authorization is required")
+	}
+
+	if s.KeyPassphrase != "" && s.KeyFile == "" {
+		return fmt.Errorf("key passphrase can be used only with key file")
+	}
+
+	if s.Connections < 1 {
+		return fmt.Errorf("connections can't be less than 1")
+	}
+
+	if s.ConnectTimeout < 0 {
+		return fmt.Errorf("connect timeout must be non-negative")
+	}
+
+	if isBackup && s.BufferSize < 1 {
+		return fmt.Errorf("buffer size can't be less than 1")
+	}
+
+	return nil
+}
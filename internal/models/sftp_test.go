@@ -0,0 +1,138 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSftp_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		sftp     *Sftp
+		isBackup bool
+		wantErr  string
+	}{
+		{
+			name: "valid backup configuration",
+			sftp: &Sftp{
+				Host:        "backup-host:22",
+				User:        "backup",
+				KeyFile:     "/home/backup/.ssh/id_ed25519",
+				Connections: 4,
+				BufferSize:  5,
+			},
+			isBackup: true,
+		},
+		{
+			name: "valid restore configuration with agent",
+			sftp: &Sftp{
+				Host:        "backup-host",
+				User:        "backup",
+				UseAgent:    true,
+				Connections: 1,
+			},
+			isBackup: false,
+		},
+		{
+			name:     "empty host",
+			sftp:     &Sftp{User: "backup"},
+			isBackup: true,
+			wantErr:  "host is required",
+		},
+		{
+			name:     "empty user",
+			sftp:     &Sftp{Host: "backup-host"},
+			isBackup: true,
+			wantErr:  "user is required",
+		},
+		{
+			name: "no authorization",
+			sftp: &Sftp{
+				Host:        "backup-host",
+				User:        "backup",
+				Connections: 1,
+			},
+			isBackup: true,
+			wantErr:  "password, key file or agent authorization is required",
+		},
+		{
+			name: "key passphrase without key file",
+			sftp: &Sftp{
+				Host:          "backup-host",
+				User:          "backup",
+				Password:      "password",
+				KeyPassphrase: "passphrase",
+				Connections:   1,
+			},
+			isBackup: true,
+			wantErr:  "key passphrase can be used only with key file",
+		},
+		{
+			name: "zero connections",
+			sftp: &Sftp{
+				Host:     "backup-host",
+				User:     "backup",
+				Password: "password",
+			},
+			isBackup: true,
+			wantErr:  "connections can't be less than 1",
+		},
+		{
+			name: "negative connect timeout",
+			sftp: &Sftp{
+				Host:           "backup-host",
+				User:           "backup",
+				Password:       "password",
+				Connections:    1,
+				ConnectTimeout: -1,
+			},
+			isBackup: true,
+			wantErr:  "connect timeout must be non-negative",
+		},
+		{
+			name: "zero buffer size on backup",
+			sftp: &Sftp{
+				Host:        "backup-host",
+				User:        "backup",
+				Password:    "password",
+				Connections: 1,
+			},
+			isBackup: true,
+			wantErr:  "buffer size can't be less than 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.sftp.LoadSecrets(nil)
+			require.NoError(t, err)
+
+			err = tt.sftp.Validate(tt.isBackup)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -14,12 +14,23 @@
 
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+)
 
 type StorageCommon struct {
 	MaxConnsPerHost int
 	RequestTimeout  int
 
+	// TLSCAFile is a PEM bundle of CAs, that are trusted in addition to system CAs, e.g. a private CA of on-prem storage.
+	TLSCAFile string
+	// TLSCertFile and TLSKeyFile are the client certificate and key for mTLS.
+	TLSCertFile string
+	TLSKeyFile  string
+	// ProxyURL is the proxy for all requests to the storage. If not set, proxy environment variables are used.
+	ProxyURL string
+
 	CalculateChecksum bool
 
 	RetryReadBackoff     int
@@ -36,6 +47,23 @@ func (s *StorageCommon) Validate(isBackup bool) error {
 		return fmt.Errorf("request timeout must be non-negative")
 	}
 
+	if (s.TLSCertFile == "") != (s.TLSKeyFile == "") {
+		return fmt.Errorf("tls cert file and tls key file must be set together")
+	}
+
+	if s.ProxyURL != "" {
+		u, err := url.Parse(s.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %w", err)
+		}
+
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("invalid proxy url %s, scheme must be http, https or socks5", s.ProxyURL)
+		}
+	}
+
 	if !isBackup {
 		if s.RetryReadMultiplier < 1 {
 			return fmt.Errorf("retry read multiplier must be positive")
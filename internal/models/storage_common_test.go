@@ -195,3 +195,42 @@ func TestStorageCommon_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageCommon_ValidateTransport(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage StorageCommon
+		errMsg  string
+	}{
+		{
+			name: "mtls with proxy",
+			storage: StorageCommon{
+				TLSCAFile:   "ca.pem",
+				TLSCertFile: "cert.pem",
+				TLSKeyFile:  "key.pem",
+				ProxyURL:    "http://proxy:3128",
+			},
+		},
+		{
+			name:    "cert without key",
+			storage: StorageCommon{TLSCertFile: "cert.pem"},
+			errMsg:  "tls cert file and tls key file must be set together",
+		},
+		{
+			name:    "proxy without scheme",
+			storage: StorageCommon{ProxyURL: "proxy:3128"},
+			errMsg:  "invalid proxy url proxy:3128, scheme must be http, https or socks5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.storage.Validate(true)
+			if tt.errMsg != "" {
+				require.ErrorContains(t, err, tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// ThrottleWindow is a daily time window during which a limit is applied.
+// Start and End are offsets from midnight in local time, End is exclusive.
+// If End is less than Start, the window wraps around midnight.
+type ThrottleWindow struct {
+	Start time.Duration
+	End   time.Duration
+	Value int64
+}
+
+// Contains checks if the time of day of t is inside the window.
+func (w ThrottleWindow) Contains(t time.Time) bool {
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+
+	return offset >= w.Start || offset < w.End
+}
+
+// Throttle contains schedules that change rate limits during a run.
+// Outside of all windows, the records-per-second and bandwidth values of the operation are used.
+type Throttle struct {
+	RecordsPerSecond []ThrottleWindow
+	// Bandwidth values are in MiB/s.
+	Bandwidth []ThrottleWindow
+	// Adaptive reduces limits while the cluster is under pressure.
+	Adaptive *AdaptiveThrottle
+}
+
+// AdaptiveThrottle contains cluster load thresholds.
+// When any threshold is exceeded on any node, limits are reduced, then they are restored step by step.
+// Zero threshold is not checked.
+type AdaptiveThrottle struct {
+	// CheckInterval is how often node statistics are polled.
+	CheckInterval time.Duration
+	// LatencyThreshold is the latency histogram bucket, that is checked by MaxLatencyPct.
+	LatencyThreshold time.Duration
+	// MaxLatencyPct is the max percentage of reads or writes slower than LatencyThreshold.
+	MaxLatencyPct float64
+	// MaxCPUPct is the max CPU usage of a node.
+	MaxCPUPct int64
+	// MaxErrorsPerSecond is the max rate of client errors and timeouts in the namespace on a node.
+	MaxErrorsPerSecond int64
+	// MinRatePct is the lowest percentage of the limit, that can be set.
+	MinRatePct int64
+}
+
+// IsEmpty checks if neither a schedule nor adaptive throttling is configured.
+func (t *Throttle) IsEmpty() bool {
+	return t == nil || (len(t.RecordsPerSecond) == 0 && len(t.Bandwidth) == 0 && t.Adaptive == nil)
+}
+
+func (t *Throttle) Validate() error {
+	if t == nil {
+		return nil
+	}
+
+	if err := validateThrottleWindows(t.RecordsPerSecond); err != nil {
+		return fmt.Errorf("invalid rps schedule: %w", err)
+	}
+
+	if err := validateThrottleWindows(t.Bandwidth); err != nil {
+		return fmt.Errorf("invalid bandwidth schedule: %w", err)
+	}
+
+	if err := t.Adaptive.Validate(); err != nil {
+		return fmt.Errorf("invalid adaptive throttle: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AdaptiveThrottle) Validate() error {
+	if a == nil {
+		return nil
+	}
+
+	if a.CheckInterval <= 0 {
+		return fmt.Errorf("check interval must be positive, got %s", a.CheckInterval)
+	}
+
+	if a.MaxLatencyPct == 0 && a.MaxCPUPct == 0 && a.MaxErrorsPerSecond == 0 {
+		return fmt.Errorf("at least one of max-latency-pct, max-cpu-pct, max-errors-per-second must be set")
+	}
+
+	if a.MaxLatencyPct < 0 || a.MaxLatencyPct > 100 {
+		return fmt.Errorf("max latency pct must be between 0 and 100, got %v", a.MaxLatencyPct)
+	}
+
+	if a.MaxLatencyPct > 0 && a.LatencyThreshold < time.Millisecond {
+		return fmt.Errorf("latency threshold must be at least 1ms, got %s", a.LatencyThreshold)
+	}
+
+	if a.MaxCPUPct < 0 || a.MaxCPUPct > 100 {
+		return fmt.Errorf("max cpu pct must be between 0 and 100, got %d", a.MaxCPUPct)
+	}
+
+	if a.MaxErrorsPerSecond < 0 {
+		return fmt.Errorf("max errors per second can't be negative, got %d", a.MaxErrorsPerSecond)
+	}
+
+	if a.MinRatePct < 1 || a.MinRatePct > 100 {
+		return fmt.Errorf("min rate pct must be between 1 and 100, got %d", a.MinRatePct)
+	}
+
+	return nil
+}
+
+// ThrottleValueAt returns the value of the window that contains t.
+// The second value is false if t is outside all windows.
+func ThrottleValueAt(windows []ThrottleWindow, t time.Time) (int64, bool) {
+	for _, w := range windows {
+		if w.Contains(t) {
+			return w.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseThrottleWindows parses a schedule where keys are windows in HH:MM-HH:MM format and values are limits.
+// 24:00 can be used as the end of the day. The result is sorted by window start.
+func ParseThrottleWindows(schedule map[string]int64) ([]ThrottleWindow, error) {
+	if len(schedule) == 0 {
+		return nil, nil
+	}
+
+	windows := make([]ThrottleWindow, 0, len(schedule))
+
+	for key, value := range schedule {
+		startStr, endStr, ok := strings.Cut(key, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid window %q, expected HH:MM-HH:MM", key)
+		}
+
+		start, err := parseTimeOfDay(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q: %w", key, err)
+		}
+
+		end, err := parseTimeOfDay(endStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q: %w", key, err)
+		}
+
+		if start == end || start == day {
+			return nil, fmt.Errorf("invalid window %q: start must differ from end and be before 24:00", key)
+		}
+
+		windows = append(windows, ThrottleWindow{Start: start, End: end, Value: value})
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start < windows[j].Start
+	})
+
+	return windows, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	hoursStr, minutesStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours in %q: %w", s, err)
+	}
+
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid minutes in %q: %w", s, err)
+	}
+
+	result := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	if hours < 0 || minutes < 0 || minutes > 59 || result > day {
+		return 0, fmt.Errorf("time %q is out of range", s)
+	}
+
+	return result, nil
+}
+
+func validateThrottleWindows(windows []ThrottleWindow) error {
+	// Minutes of the day that are already covered by a window.
+	var covered [24 * 60]bool
+
+	for _, w := range windows {
+		if w.Value < 0 {
+			return fmt.Errorf("value can't be negative, got %d", w.Value)
+		}
+
+		end := w.End
+		if end < w.Start {
+			end += day
+		}
+
+		for m := w.Start; m < end; m += time.Minute {
+			i := int((m % day) / time.Minute)
+			if covered[i] {
+				return fmt.Errorf("windows overlap at %02d:%02d", i/60, i%60)
+			}
+
+			covered[i] = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThrottleWindows(t *testing.T) {
+	t.Parallel()
+
+	windows, err := ParseThrottleWindows(map[string]int64{
+		"06:00-23:59": 20000,
+		"00:00-06:00": 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ThrottleWindow{
+		{Start: 0, End: 6 * time.Hour, Value: 0},
+		{Start: 6 * time.Hour, End: 23*time.Hour + 59*time.Minute, Value: 20000},
+	}, windows)
+
+	windows, err = ParseThrottleWindows(nil)
+	require.NoError(t, err)
+	require.Nil(t, windows)
+
+	for _, key := range []string{"06:00", "6-7", "25:00-26:00", "06:60-07:00", "06:00-06:00", "24:00-01:00"} {
+		_, err = ParseThrottleWindows(map[string]int64{key: 1})
+		require.Error(t, err, key)
+	}
+}
+
+func TestThrottleValueAt(t *testing.T) {
+	t.Parallel()
+
+	windows, err := ParseThrottleWindows(map[string]int64{
+		"22:00-06:00": 0,
+		"09:00-18:00": 1000,
+	})
+	require.NoError(t, err)
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		value int64
+		found bool
+	}{
+		{"before midnight", at(23, 30), 0, true},
+		{"after midnight", at(1, 0), 0, true},
+		{"end is exclusive", at(6, 0), 0, false},
+		{"day window", at(12, 0), 1000, true},
+		{"between windows", at(19, 0), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			value, found := ThrottleValueAt(windows, tt.at)
+			require.Equal(t, tt.found, found)
+			require.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestThrottle_Validate(t *testing.T) {
+	t.Parallel()
+
+	var empty *Throttle
+	require.NoError(t, empty.Validate())
+	require.True(t, empty.IsEmpty())
+
+	overlapping, err := ParseThrottleWindows(map[string]int64{
+		"00:00-08:00": 0,
+		"07:00-20:00": 100,
+	})
+	require.NoError(t, err)
+
+	err = (&Throttle{RecordsPerSecond: overlapping}).Validate()
+	require.ErrorContains(t, err, "invalid rps schedule: windows overlap at 07:00")
+
+	wrapped, err := ParseThrottleWindows(map[string]int64{
+		"22:00-02:00": 0,
+		"01:00-03:00": 100,
+	})
+	require.NoError(t, err)
+
+	err = (&Throttle{Bandwidth: wrapped}).Validate()
+	require.ErrorContains(t, err, "invalid bandwidth schedule: windows overlap at 01:00")
+
+	err = (&Throttle{Bandwidth: []ThrottleWindow{{Start: 0, End: time.Hour, Value: -1}}}).Validate()
+	require.ErrorContains(t, err, "value can't be negative")
+
+	valid, err := ParseThrottleWindows(map[string]int64{
+		"00:00-06:00": 0,
+		"06:00-24:00": 20000,
+	})
+	require.NoError(t, err)
+	require.NoError(t, (&Throttle{RecordsPerSecond: valid}).Validate())
+}
+
+func TestAdaptiveThrottle_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *AdaptiveThrottle {
+		return &AdaptiveThrottle{
+			CheckInterval:    10 * time.Second,
+			LatencyThreshold: 8 * time.Millisecond,
+			MaxLatencyPct:    5,
+			MinRatePct:       10,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *AdaptiveThrottle)
+		errMsg string
+	}{
+		{"valid", func(*AdaptiveThrottle) {}, ""},
+		{"zero interval", func(a *AdaptiveThrottle) { a.CheckInterval = 0 }, "check interval must be positive"},
+		{"no thresholds", func(a *AdaptiveThrottle) { a.MaxLatencyPct = 0 }, "at least one of"},
+		{"latency pct over 100", func(a *AdaptiveThrottle) { a.MaxLatencyPct = 101 }, "max latency pct"},
+		{"small latency threshold", func(a *AdaptiveThrottle) { a.LatencyThreshold = 0 }, "latency threshold"},
+		{"cpu pct over 100", func(a *AdaptiveThrottle) { a.MaxCPUPct = 101 }, "max cpu pct"},
+		{"negative errors", func(a *AdaptiveThrottle) { a.MaxErrorsPerSecond = -1 }, "max errors per second"},
+		{"zero min rate", func(a *AdaptiveThrottle) { a.MinRatePct = 0 }, "min rate pct"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := valid()
+			tt.modify(a)
+
+			err := (&Throttle{Adaptive: a}).Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+
+	require.False(t, (&Throttle{Adaptive: valid()}).IsEmpty())
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "fmt"
+
+// XDRCleanup flags that are used to undo changes made on the database by an interrupted xdr backup.
+type XDRCleanup struct {
+	Namespace string
+	DC        string
+	// StateFile is the cleanup state file of the backup. If set, the namespace and the DC are taken from it.
+	StateFile string
+	// DryRun only reports changes that would be undone.
+	DryRun bool
+
+	InfoTimeout                   int64
+	InfoMaxRetries                uint
+	InfoRetriesMultiplier         float64
+	InfoRetryIntervalMilliseconds int64
+}
+
+func (c *XDRCleanup) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.StateFile == "" {
+		if c.Namespace == "" {
+			return fmt.Errorf("namespace or cleanup state file is required")
+		}
+
+		if c.DC == "" {
+			return fmt.Errorf("dc or cleanup state file is required")
+		}
+	}
+
+	if c.InfoRetryIntervalMilliseconds < 0 {
+		return fmt.Errorf("xdr cleanup info retry interval can't be negative")
+	}
+
+	if c.InfoRetriesMultiplier < 0 {
+		return fmt.Errorf("xdr cleanup info retries multiplier can't be negative")
+	}
+
+	return nil
+}
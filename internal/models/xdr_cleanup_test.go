@@ -0,0 +1,71 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXDRCleanup_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cleanup *XDRCleanup
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			cleanup: &XDRCleanup{Namespace: testNamespace, DC: testDC},
+		},
+		{
+			name:    "state file without namespace and dc",
+			cleanup: &XDRCleanup{StateFile: "state.json", DryRun: true},
+		},
+		{
+			name: "nil",
+		},
+		{
+			name:    "missing namespace",
+			cleanup: &XDRCleanup{DC: testDC},
+			wantErr: "namespace or cleanup state file is required",
+		},
+		{
+			name:    "missing dc",
+			cleanup: &XDRCleanup{Namespace: testNamespace},
+			wantErr: "dc or cleanup state file is required",
+		},
+		{
+			name:    "negative info retry interval",
+			cleanup: &XDRCleanup{Namespace: testNamespace, DC: testDC, InfoRetryIntervalMilliseconds: -1},
+			wantErr: "xdr cleanup info retry interval can't be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cleanup.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
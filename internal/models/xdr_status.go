@@ -0,0 +1,60 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "fmt"
+
+// XDRStatus flags that are used to check the status of an xdr backup.
+type XDRStatus struct {
+	Namespace string
+	DC        string
+	// MaxLag in seconds. If the lag on any node is greater, the DC is unhealthy. 0 means no limit.
+	MaxLag int64
+	// AllowIdle allows the DC to be missing on all nodes, e.g. between segments of a continuous backup.
+	AllowIdle bool
+
+	InfoTimeout                   int64
+	InfoMaxRetries                uint
+	InfoRetriesMultiplier         float64
+	InfoRetryIntervalMilliseconds int64
+}
+
+func (s *XDRStatus) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Namespace == "" {
+		return fmt.Errorf("namespace is required")
+	}
+
+	if s.DC == "" {
+		return fmt.Errorf("dc is required")
+	}
+
+	if s.MaxLag < 0 {
+		return fmt.Errorf("xdr status max lag can't be negative")
+	}
+
+	if s.InfoRetryIntervalMilliseconds < 0 {
+		return fmt.Errorf("xdr status info retry interval can't be negative")
+	}
+
+	if s.InfoRetriesMultiplier < 0 {
+		return fmt.Errorf("xdr status info retries multiplier can't be negative")
+	}
+
+	return nil
+}
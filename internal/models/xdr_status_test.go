@@ -0,0 +1,67 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXDRStatus_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  *XDRStatus
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			status: &XDRStatus{Namespace: testNamespace, DC: testDC, MaxLag: 60},
+		},
+		{
+			name: "nil",
+		},
+		{
+			name:    "missing namespace",
+			status:  &XDRStatus{DC: testDC},
+			wantErr: "namespace is required",
+		},
+		{
+			name:    "missing dc",
+			status:  &XDRStatus{Namespace: testNamespace},
+			wantErr: "dc is required",
+		},
+		{
+			name:    "negative max lag",
+			status:  &XDRStatus{Namespace: testNamespace, DC: testDC, MaxLag: -1},
+			wantErr: "xdr status max lag can't be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.status.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/storage/objectpath"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// State is the progress of a restore. It is saved after each batch of files is restored.
+type State struct {
+	// Directory is the restore directory, a state of another directory can't be continued.
+	Directory string `json:"directory"`
+	// Updated is the time when the state was saved.
+	Updated time.Time `json:"updated"`
+	// Files are restored fully or partially, in the order they were started.
+	Files []File `json:"files"`
+	// Records is the number of records restored from the files.
+	Records uint64 `json:"records"`
+}
+
+// File is a restored file.
+type File struct {
+	Name string `json:"name"`
+	// Offset is the number of restored bytes of the file, including its header.
+	// Bytes are counted after decompression. Restore of a partially restored file is continued from it.
+	Offset int64 `json:"offset"`
+	// Records is the number of records restored from the file.
+	Records uint64 `json:"records"`
+	// Done is set when the file is restored to the end.
+	Done bool `json:"done"`
+}
+
+// New returns an empty state for the directory.
+func New(directory string) *State {
+	return &State{Directory: directory}
+}
+
+// Restored returns restored files by name.
+func (s *State) Restored() map[string]File {
+	result := make(map[string]File, len(s.Files))
+	for _, f := range s.Files {
+		result[f.Name] = f
+	}
+
+	return result
+}
+
+// Add updates restored files and adds restored records.
+// A file that is already in the state is replaced by its later progress.
+func (s *State) Add(files []File, records uint64) {
+	index := make(map[string]int, len(s.Files))
+	for i, f := range s.Files {
+		index[f.Name] = i
+	}
+
+	for _, f := range files {
+		if i, ok := index[f.Name]; ok {
+			s.Files[i] = f
+			continue
+		}
+
+		index[f.Name] = len(s.Files)
+		s.Files = append(s.Files, f)
+	}
+
+	s.Records += records
+}
+
+// Read reads the state file from the directory.
+// Returns nil if the directory doesn't contain the file.
+func Read(ctx context.Context, reader backup.StreamingReader, dir, name string) (*State, error) {
+	objects, err := reader.ListObjects(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	for _, object := range objects {
+		if objectpath.BaseName(object) != name {
+			continue
+		}
+
+		s, err := readFile(ctx, reader, object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state %s: %w", object, err)
+		}
+
+		return s, nil
+	}
+
+	return nil, nil
+}
+
+func readFile(ctx context.Context, reader backup.StreamingReader, name string) (*State, error) {
+	readCh := make(chan bModels.File, 1)
+	errCh := make(chan error, 1)
+
+	go reader.StreamFile(ctx, name, readCh, errCh)
+
+	var file bModels.File
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-errCh:
+		return nil, err
+	case file = <-readCh:
+	}
+
+	defer file.Reader.Close()
+
+	var s State
+	// The decoder reads only the first value, so bytes left from a longer previous state are ignored.
+	if err := json.NewDecoder(file.Reader).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to decode: %w", err)
+	}
+
+	return &s, nil
+}
+
+// Write writes the state file to the writer directory.
+func Write(ctx context.Context, writer backup.Writer, name string, s *State) error {
+	s.Updated = time.Now().UTC()
+
+	file, err := writer.NewWriter(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	if err = json.NewEncoder(file).Encode(s); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", name, err)
+	}
+
+	return nil
+}
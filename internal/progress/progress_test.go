@@ -0,0 +1,69 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package progress
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/require"
+)
+
+const testStateFile = "restore.state"
+
+func TestState_WriteRead(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	reader, err := local.NewReader(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	// No state in a new directory.
+	s, err := Read(ctx, reader, dir, testStateFile)
+	require.NoError(t, err)
+	require.Nil(t, s)
+
+	writer, err := local.NewWriter(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	s = New(dir)
+	s.Add([]File{
+		{Name: "0_test_1.asb", Offset: 100, Records: 4, Done: true},
+		{Name: "1_test_1.asb", Offset: 200, Records: 6},
+	}, 10)
+	require.NoError(t, Write(ctx, writer, testStateFile, s))
+
+	// The partially restored file is continued.
+	s.Add([]File{{Name: "1_test_1.asb", Offset: 300, Records: 9, Done: true}, {Name: "0_test_2.asb", Offset: 50}}, 5)
+	require.NoError(t, Write(ctx, writer, testStateFile, s))
+
+	result, err := Read(ctx, reader, dir, testStateFile)
+	require.NoError(t, err)
+	require.Equal(t, dir, result.Directory)
+	require.Equal(t, uint64(15), result.Records)
+	require.Len(t, result.Files, 3)
+	require.False(t, result.Updated.IsZero())
+
+	restored := result.Restored()
+	require.Len(t, restored, 3)
+	require.Equal(t, File{Name: "1_test_1.asb", Offset: 300, Records: 9, Done: true}, restored["1_test_1.asb"])
+	require.Equal(t, File{Name: "0_test_2.asb", Offset: 50}, restored["0_test_2.asb"])
+	require.NotContains(t, restored, "1_test_2.asb")
+}
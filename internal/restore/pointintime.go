@@ -0,0 +1,204 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/xdrmsg"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+// asbxHeaderSize is the size of the .asbx file header, bytes 1-8 contain the file number.
+var asbxHeaderSize = len(asbx.NewEncoder[*bModels.ASBXToken]("").GetHeader(0, false))
+
+// pointInTimeReader skips changes made after the point in time in .asbx files
+// of a segment, that was finished after it.
+type pointInTimeReader struct {
+	backup.StreamingReader
+	until       time.Time
+	compression *backup.CompressionPolicy
+	logger      *slog.Logger
+}
+
+// newPointInTimeReader wraps r, so that records changed after until are not restored.
+// Compressed files are decompressed to be filtered and compressed again.
+func newPointInTimeReader(
+	r backup.StreamingReader, until time.Time, compression *backup.CompressionPolicy, logger *slog.Logger,
+) backup.StreamingReader {
+	if r == nil {
+		return nil
+	}
+
+	return &pointInTimeReader{StreamingReader: r, until: until, compression: compression, logger: logger}
+}
+
+func (r *pointInTimeReader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	filesCh := make(chan bModels.File)
+
+	go r.StreamingReader.StreamFiles(ctx, filesCh, errorsCh, skipPrefixes)
+
+	defer close(readersCh)
+
+	for file := range filesCh {
+		readersCh <- r.wrapFile(file)
+	}
+}
+
+func (r *pointInTimeReader) StreamFile(
+	ctx context.Context, filename string, readersCh chan<- bModels.File, errorsCh chan<- error,
+) {
+	filesCh := make(chan bModels.File, 1)
+
+	r.StreamingReader.StreamFile(ctx, filename, filesCh, errorsCh)
+	close(filesCh)
+
+	for file := range filesCh {
+		readersCh <- r.wrapFile(file)
+	}
+}
+
+// wrapFile replaces the file reader with a pipe, that receives only records changed by the point in time.
+func (r *pointInTimeReader) wrapFile(file bModels.File) bModels.File {
+	pr, pw := io.Pipe()
+	src := file.Reader
+
+	go func() {
+		defer src.Close()
+
+		// If the pipe is already closed with an error, it is not overwritten.
+		_ = pw.CloseWithError(r.filter(pw, src, file.Name))
+	}()
+
+	file.Reader = pr
+
+	return file
+}
+
+// filter copies records of the .asbx file from src to w, skipping records changed after the point in time.
+func (r *pointInTimeReader) filter(w io.WriteCloser, src io.Reader, name string) error {
+	if r.compression != nil {
+		zstdDecoder, err := zstd.NewReader(src)
+		if err != nil {
+			return fmt.Errorf("failed to create decompression reader: %w", err)
+		}
+
+		defer zstdDecoder.Close()
+
+		src = zstdDecoder
+
+		zstdEncoder, err := zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(r.compression.Level)))
+		if err != nil {
+			return fmt.Errorf("failed to create compression writer: %w", err)
+		}
+
+		// Closing the encoder finishes the stream, the pipe is closed by the caller.
+		w = zstdEncoder
+	}
+
+	skipped, err := r.filterRecords(w, src, name)
+	if err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	r.logger.Debug("filtered file by point in time",
+		slog.String("file", name),
+		slog.Int("skipped_records", skipped),
+	)
+
+	return nil
+}
+
+// filterRecords copies decoded records to w, returns the number of skipped records.
+func (r *pointInTimeReader) filterRecords(w io.Writer, src io.Reader, name string) (int, error) {
+	header := make([]byte, asbxHeaderSize)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return 0, fmt.Errorf("failed to read header of %s: %w", name, err)
+	}
+
+	fileNumber := binary.BigEndian.Uint64(header[1:9])
+
+	// The header is validated by the decoder.
+	decoder, err := asbx.NewDecoder[*bModels.ASBXToken](
+		io.MultiReader(bytes.NewReader(header), src), fileNumber, filepath.Base(name))
+	if err != nil {
+		return 0, err
+	}
+
+	encoder := asbx.NewEncoder[*bModels.ASBXToken]("")
+	out := bufio.NewWriter(w)
+
+	if _, err = out.Write(header); err != nil {
+		return 0, err
+	}
+
+	var records, skipped int
+
+	for {
+		token, err := decoder.NextToken()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return skipped, out.Flush()
+		case err != nil:
+			return 0, fmt.Errorf("failed to decode record %d of %s: %w", records+1, name, err)
+		}
+
+		records++
+
+		msg, err := xdrmsg.Parse(token.Payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse record %d of %s: %w", records, name, err)
+		}
+
+		lut, ok := xdrmsg.LUT(msg.Fields)
+		if !ok {
+			// Without the last update time, it is unknown whether the record is changed by the point in time.
+			return 0, fmt.Errorf("record %d of %s doesn't contain the last update time", records, name)
+		}
+
+		if lut.After(r.until) {
+			skipped++
+			continue
+		}
+
+		b, err := encoder.EncodeToken(token)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = out.Write(b); err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/xdrmsg"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+// newLUTToken returns a token with an XDR message of the key, changed at lut.
+// If lut is zero, the message doesn't contain the last update time.
+func newLUTToken(t *testing.T, userKey int, lut time.Time) *bModels.ASBXToken {
+	t.Helper()
+
+	key, err := aerospike.NewKey(testNamespace, testSet, userKey)
+	require.NoError(t, err)
+
+	var fields []byte
+
+	addField := func(fieldType byte, data []byte) {
+		size := make([]byte, 4)
+		binary.BigEndian.PutUint32(size, uint32(len(data)+1))
+		fields = append(fields, size...)
+		fields = append(fields, fieldType)
+		fields = append(fields, data...)
+	}
+
+	addField(xdr.FieldTypeNamespace, []byte(testNamespace))
+	addField(xdr.FieldTypeDigest, key.Digest())
+
+	numFields := 2
+
+	if !lut.IsZero() {
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(lut.UnixMilli()-xdrmsg.CitrusleafEpoch*1000))
+		addField(xdrmsg.FieldTypeLUT, data)
+
+		numFields++
+	}
+
+	header := make([]byte, xdr.LenMessageHeader)
+	header[0] = xdr.LenMessageHeader
+	header[2] = xdr.MsgInfo2Write
+	binary.BigEndian.PutUint16(header[18:20], uint16(numFields))
+
+	return bModels.NewASBXToken(key, xdr.NewPayload(append(header, fields...)))
+}
+
+func encodeASBX(t *testing.T, tokens ...*bModels.ASBXToken) []byte {
+	t.Helper()
+
+	encoder := asbx.NewEncoder[*bModels.ASBXToken](testNamespace)
+	data := encoder.GetHeader(7, false)
+
+	for _, token := range tokens {
+		b, err := encoder.EncodeToken(token)
+		require.NoError(t, err)
+
+		data = append(data, b...)
+	}
+
+	return data
+}
+
+func decodeASBX(t *testing.T, data []byte) []*bModels.ASBXToken {
+	t.Helper()
+
+	decoder, err := asbx.NewDecoder[*bModels.ASBXToken](bytes.NewReader(data), 7, "0_test_7.asbx")
+	require.NoError(t, err)
+
+	var result []*bModels.ASBXToken
+
+	for {
+		token, err := decoder.NextToken()
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+
+		require.NoError(t, err)
+
+		result = append(result, token)
+	}
+}
+
+func TestPointInTimeReader_wrapFile(t *testing.T) {
+	t.Parallel()
+
+	until := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tokens := []*bModels.ASBXToken{
+		newLUTToken(t, 1, until.Add(-time.Minute)),
+		newLUTToken(t, 2, until.Add(time.Second)),
+		newLUTToken(t, 3, until),
+		newLUTToken(t, 4, until.Add(time.Minute)),
+	}
+
+	zstdEncoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		compression *backup.CompressionPolicy
+		data        []byte
+		want        []*bModels.ASBXToken
+		wantErr     string
+	}{
+		{
+			name: "changes after point in time are skipped",
+			data: encodeASBX(t, tokens...),
+			want: []*bModels.ASBXToken{tokens[0], tokens[2]},
+		},
+		{
+			name:        "compressed",
+			compression: backup.NewCompressionPolicy(backup.CompressZSTD, 3),
+			data:        zstdEncoder.EncodeAll(encodeASBX(t, tokens...), nil),
+			want:        []*bModels.ASBXToken{tokens[0], tokens[2]},
+		},
+		{
+			name: "empty file",
+			data: encodeASBX(t),
+		},
+		{
+			name:    "record without last update time",
+			data:    encodeASBX(t, tokens[0], newLUTToken(t, 5, time.Time{})),
+			wantErr: "record 2 of 0_test_7.asbx doesn't contain the last update time",
+		},
+		{
+			name:    "truncated header",
+			data:    encodeASBX(t)[:10],
+			wantErr: "failed to read header of 0_test_7.asbx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &pointInTimeReader{
+				until:       until,
+				compression: tt.compression,
+				logger:      slog.New(slog.NewTextHandler(os.Stderr, nil)),
+			}
+
+			file := r.wrapFile(bModels.File{Name: "0_test_7.asbx", Reader: io.NopCloser(bytes.NewReader(tt.data))})
+			data, err := io.ReadAll(file.Reader)
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.compression != nil {
+				zstdDecoder, err := zstd.NewReader(nil)
+				require.NoError(t, err)
+
+				data, err = zstdDecoder.DecodeAll(data, nil)
+				require.NoError(t, err)
+			}
+
+			got := decodeASBX(t, data)
+			require.Len(t, got, len(tt.want))
+
+			// Decoded keys contain only the digest.
+			for i, token := range got {
+				require.Equal(t, tt.want[i].Key.Digest(), token.Key.Digest())
+				require.Equal(t, tt.want[i].Payload, token.Payload)
+			}
+		})
+	}
+}
@@ -18,12 +18,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 
 	"github.com/aerospike/aerospike-backup-cli/internal/config"
 	"github.com/aerospike/aerospike-backup-cli/internal/logging"
 	"github.com/aerospike/aerospike-backup-cli/internal/models"
 	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/aerospike-backup-cli/internal/throttle"
+	"github.com/aerospike/aerospike-client-go/v8"
 	"github.com/aerospike/backup-go"
 	bModels "github.com/aerospike/backup-go/models"
 )
@@ -37,6 +40,12 @@ type Service struct {
 
 	reader    backup.StreamingReader
 	xdrReader backup.StreamingReader
+	// throttle is set when limits are changed by a schedule.
+	throttle *throttle.Controller
+	// segments are set when restoring a continuous xdr backup.
+	segments []segment
+	// state is set when restored files are saved to a state file.
+	state *restoreState
 	// Restore Mode: auto, asb, asbx
 	mode string
 
@@ -56,29 +65,42 @@ func NewService(
 		// Important! To describe variable as interface not exact *a.Client.
 		// So we can run backup files validation with the 'nil' aerospike client.
 		aerospikeClient backup.AerospikeClient
-		err             error
+		// nodesClient is used to get cluster statistics for adaptive throttling.
+		nodesClient *aerospike.Client
+		err         error
 	)
-	// Set default restore mode to asb.
-	// This should be removed once asbx is released.
-	params.Restore.Mode = models.RestoreModeASB
+	params.Restore.Mode = resolveMode(params.Restore)
 	// Validations.
 	if err := params.Restore.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := config.ValidateStorages(false, params.AwsS3, params.GcpStorage, params.AzureBlob, nil); err != nil {
+	if err := config.ValidateStorages(
+		false, params.StoragePaths(), params.AwsS3, params.GcpStorage, params.AzureBlob, params.Sftp, params.Local,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := params.Throttle.Validate(); err != nil {
 		return nil, err
 	}
 
 	// Initializations.
 	restoreConfig := config.NewRestoreConfig(params, logger)
 
+	// Cluster statistics are polled for the destination namespace, so it must be resolved.
+	if !restoreConfig.ValidateOnly && params.Throttle != nil && params.Throttle.Adaptive != nil &&
+		restoreConfig.Namespace == nil {
+		return nil, fmt.Errorf("adaptive throttling requires namespace in the <source>[,<destination>] format, got %q",
+			params.Restore.Namespace)
+	}
+
 	// Skip this part on validation.
 	if !restoreConfig.ValidateOnly {
 		warmUp := GetWarmUp(params.Restore.WarmUp, params.Restore.MaxAsyncBatches)
 		logger.Debug("warm up is set", slog.Int("value", warmUp))
 
-		aerospikeClient, err = storage.NewAerospikeClient(
+		nodesClient, err = storage.NewAerospikeClient(
 			params.ClientConfig,
 			params.ClientPolicy,
 			"",
@@ -89,11 +111,44 @@ func NewService(
 		if err != nil {
 			return nil, fmt.Errorf("failed to create aerospike client: %w", err)
 		}
+
+		aerospikeClient = nodesClient
 	}
 
-	reader, xdrReader, err := storage.NewRestoreReader(ctx, params, restoreConfig.SecretAgentConfig, logger)
+	throttleController := throttle.NewController(
+		params.Throttle, params.Restore.RecordsPerSecond, params.Restore.Bandwidth, logger)
+
+	segments, err := newSegments(ctx, params, restoreConfig.SecretAgentConfig, throttleController, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create restore reader: %w", err)
+		return nil, err
+	}
+
+	var state *restoreState
+	if params.Restore.ShouldSaveState() {
+		if len(segments) > 0 {
+			return nil, fmt.Errorf("state file is not supported for a continuous xdr backup")
+		}
+
+		state, err = newRestoreState(ctx, params, restoreConfig.SecretAgentConfig, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		// Files are decompressed by the state reader, to be continued from an offset.
+		restoreConfig.CompressionPolicy = nil
+	}
+
+	var reader, xdrReader backup.StreamingReader
+	// A continuous xdr backup directory contains only segments.
+	if len(segments) == 0 {
+		if err = checkShards(ctx, params, restoreConfig.SecretAgentConfig, logger); err != nil {
+			return nil, err
+		}
+
+		reader, xdrReader, err = storage.NewRestoreReader(ctx, params, restoreConfig.SecretAgentConfig, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create restore reader: %w", err)
+		}
 	}
 
 	logger.Info("initializing restore client", slog.String("id", idRestore))
@@ -106,6 +161,11 @@ func NewService(
 
 	infoPolicy := config.NewInfoPolicy(params.Restore.InfoTimeout)
 
+	if throttleController != nil && nodesClient != nil && restoreConfig.Namespace != nil {
+		throttleController.WatchCluster(
+			throttle.NewInfoStats(nodesClient, infoPolicy, *restoreConfig.Namespace.Destination))
+	}
+
 	backupClient, err := backup.NewClient(
 		aerospikeClient,
 		backup.WithLogger(logger),
@@ -118,8 +178,11 @@ func NewService(
 	return &Service{
 		backupClient:  backupClient,
 		restoreConfig: restoreConfig,
-		reader:        reader,
-		xdrReader:     xdrReader,
+		reader:        throttle.NewReader(reader, throttleController),
+		xdrReader:     throttle.NewReader(xdrReader, throttleController),
+		segments:      segments,
+		state:         state,
+		throttle:      throttleController,
 		mode:          params.Restore.Mode,
 		logger:        logger,
 		isLogJSON:     params.App.LogJSON,
@@ -138,30 +201,65 @@ func (r *Service) Run(ctx context.Context) error {
 		logMessage = "validation"
 	}
 
-	switch r.mode {
-	case models.RestoreModeASB, models.RestoreModeAuto:
-		return r.run(ctx, backup.EncoderTypeASB, logMessage)
-	case models.RestoreModeASBX:
-		return r.run(ctx, backup.EncoderTypeASBX, logMessage)
+	// Stop the throttle schedule when the restore is finished.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go r.throttle.Run(ctx)
+
+	var (
+		stats *bModels.RestoreStats
+		err   error
+	)
+
+	switch {
+	case len(r.segments) > 0:
+		stats, err = r.runSegments(ctx, logMessage)
+	case r.state != nil:
+		stats, err = r.runState(ctx, logMessage)
+	case r.mode == models.RestoreModeASB:
+		stats, err = r.run(ctx, backup.EncoderTypeASB, r.reader, logMessage)
+	case r.mode == models.RestoreModeASBX:
+		stats, err = r.run(ctx, backup.EncoderTypeASBX, r.xdrReader, logMessage)
 	default:
-		return r.runAuto(ctx)
+		stats, err = r.runAuto(ctx, r.reader, r.xdrReader, logMessage)
 	}
+
+	if err != nil {
+		return err
+	}
+
+	r.throttle.Report()
+	// Print report.
+	logging.ReportRestore(stats, r.restoreConfig.ValidateOnly, r.isLogJSON, r.logger)
+
+	return nil
 }
 
-func (r *Service) run(ctx context.Context, encoderType backup.EncoderType, logMessage string) error {
-	restoreType := "asb"
+func (r *Service) run(
+	ctx context.Context, encoderType backup.EncoderType, reader backup.StreamingReader, logMessage string,
+) (*bModels.RestoreStats, error) {
+	restoreType := models.RestoreModeASB
 	if encoderType == backup.EncoderTypeASBX {
-		restoreType = "asbx"
+		restoreType = models.RestoreModeASBX
 	}
 
 	r.logger.Info(fmt.Sprintf("starting %s %s", restoreType, logMessage))
 
-	r.restoreConfig.EncoderType = encoderType
+	restoreCfg := *r.restoreConfig
+	restoreCfg.EncoderType = encoderType
 	// Run restore / validation.
-	h, err := r.backupClient.Restore(ctx, r.restoreConfig, r.reader)
+	h, err := r.backupClient.Restore(ctx, &restoreCfg, reader)
 	if err != nil {
-		return fmt.Errorf("failed to start %s %s: %w", restoreType, logMessage, err)
+		return nil, fmt.Errorf("failed to start %s %s: %w", restoreType, logMessage, err)
 	}
+
+	r.throttle.AddRecordCounter(h.GetStats().GetReadRecords)
+
+	// Stop printing estimates when this restore is finished.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Run async printing files stats.
 	var wg sync.WaitGroup
 
@@ -170,118 +268,64 @@ func (r *Service) run(ctx context.Context, encoderType backup.EncoderType, logMe
 	go func() {
 		defer wg.Done()
 
-		logging.PrintFilesNumber(ctx, r.reader.GetNumber, models.RestoreModeASB, r.logger)
+		logging.PrintFilesNumber(runCtx, reader.GetNumber, restoreType, r.logger)
 	}()
-	go logging.PrintRestoreEstimate(ctx, h.GetStats(), h.GetMetrics, r.reader.GetSize, r.logger)
+	go logging.PrintRestoreEstimate(runCtx, h.GetStats(), h.GetMetrics, reader.GetSize, r.logger)
 
 	// Wait for restore / validation to finish.
 	if err = h.Wait(ctx); err != nil {
-		return fmt.Errorf("failed to perform %s %s: %w", restoreType, logMessage, err)
+		return nil, fmt.Errorf("failed to perform %s %s: %w", restoreType, logMessage, err)
 	}
 
 	wg.Wait()
-	// Print report.
-	logging.ReportRestore(h.GetStats(), r.restoreConfig.ValidateOnly, r.isLogJSON, r.logger)
 
-	return nil
+	return h.GetStats(), nil
 }
 
-func (r *Service) runAuto(ctx context.Context) error {
-	r.logger.Info("starting auto restore")
-	// If one of restore operations fails, we cancel another.
-	ctx, cancel := context.WithCancel(ctx)
+// runAuto restores .asb files first, then replays .asbx files,
+// as they contain changes made after the .asb backup was taken.
+func (r *Service) runAuto(
+	ctx context.Context, reader, xdrReader backup.StreamingReader, logMessage string,
+) (*bModels.RestoreStats, error) {
+	r.logger.Info("starting auto " + logMessage)
 
 	var (
-		wg              sync.WaitGroup
 		xdrStats, stats *bModels.RestoreStats
+		err             error
 	)
 
-	errChan := make(chan error, 2)
-
-	if r.reader != nil {
-		wg.Add(1)
-
-		go func() {
-			defer wg.Done()
-
-			restoreCfg := *r.restoreConfig
-			restoreCfg.EncoderType = backup.EncoderTypeASB
-
-			h, err := r.backupClient.Restore(ctx, &restoreCfg, r.reader)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to start asb restore: %w", err)
-
-				cancel()
-
-				return
-			}
-
-			go logging.PrintFilesNumber(ctx, r.reader.GetNumber, models.RestoreModeASB, r.logger)
-			go logging.PrintRestoreEstimate(ctx, h.GetStats(), h.GetMetrics, r.reader.GetSize, r.logger)
-
-			if err = h.Wait(ctx); err != nil {
-				errChan <- fmt.Errorf("failed to perform asb restore: %w", err)
-
-				cancel()
-
-				return
-			}
-
-			stats = h.GetStats()
-		}()
-	}
-
-	if r.xdrReader != nil {
-		wg.Add(1)
-
-		go func() {
-			defer wg.Done()
-
-			restoreXdrCfg := *r.restoreConfig
-			restoreXdrCfg.EncoderType = backup.EncoderTypeASBX
-
-			hXdr, err := r.backupClient.Restore(ctx, &restoreXdrCfg, r.xdrReader)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to start asbx restore: %w", err)
-
-				cancel()
-
-				return
-			}
-
-			go logging.PrintFilesNumber(ctx, r.xdrReader.GetNumber, models.RestoreModeASBX, r.logger)
-			go logging.PrintRestoreEstimate(ctx, hXdr.GetStats(), hXdr.GetMetrics, r.xdrReader.GetSize, r.logger)
-
-			if err = hXdr.Wait(ctx); err != nil {
-				errChan <- fmt.Errorf("failed to perform asbx restore: %w", err)
-
-				cancel()
-
-				return
-			}
-
-			xdrStats = hXdr.GetStats()
-		}()
+	if reader != nil {
+		stats, err = r.run(ctx, backup.EncoderTypeASB, reader, logMessage)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	wg.Wait()
-	close(errChan)
-
-	// Return the first error encountered
-	for err := range errChan {
+	if xdrReader != nil {
+		xdrStats, err = r.run(ctx, backup.EncoderTypeASBX, xdrReader, logMessage)
 		if err != nil {
-			cancel()
-			return err
+			return nil, err
 		}
 	}
 
-	restStats := bModels.SumRestoreStats(xdrStats, stats)
-	logging.ReportRestore(restStats, r.restoreConfig.ValidateOnly, r.isLogJSON, r.logger)
+	return bModels.SumRestoreStats(xdrStats, stats), nil
+}
 
-	// To prevent context leaking.
-	cancel()
+// resolveMode returns the restore mode for the input, empty mode means auto.
+// Stdin and a single file can be read only once, so auto mode is resolved by the file extension.
+func resolveMode(r *models.Restore) string {
+	switch {
+	case r.Mode != "" && r.Mode != models.RestoreModeAuto:
+		return r.Mode
+	case r.InputFile == "":
+		return models.RestoreModeAuto
+	}
 
-	return nil
+	if r.InputFile != config.StdPlaceholder && strings.HasSuffix(r.InputFile, ".asbx") {
+		return models.RestoreModeASBX
+	}
+
+	return models.RestoreModeASB
 }
 
 // GetWarmUp calculates and returns the warm-up value based on the provided warmUp and maxAsyncBatches parameters.
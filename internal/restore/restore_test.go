@@ -208,3 +208,54 @@ func TestGetWarmUp(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mode      string
+		inputFile string
+		expected  string
+	}{
+		{"empty mode", "", "", models.RestoreModeAuto},
+		{"auto directory", models.RestoreModeAuto, "", models.RestoreModeAuto},
+		{"auto asb file", models.RestoreModeAuto, "backup.asb", models.RestoreModeASB},
+		{"auto asbx file", models.RestoreModeAuto, "backup/0_test_1.asbx", models.RestoreModeASBX},
+		{"auto stdin", models.RestoreModeAuto, config.StdPlaceholder, models.RestoreModeASB},
+		{"explicit mode", models.RestoreModeASBX, "", models.RestoreModeASBX},
+		{"explicit mode with file", models.RestoreModeASB, "backup.asbx", models.RestoreModeASB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &models.Restore{Mode: tt.mode, InputFile: tt.inputFile}
+			require.Equal(t, tt.expected, resolveMode(r))
+		})
+	}
+}
+
+func TestNewService_AdaptiveThrottleInvalidNamespace(t *testing.T) {
+	t.Parallel()
+
+	params := &config.RestoreServiceConfig{
+		App: &models.App{},
+		Restore: &models.Restore{
+			Common: models.Common{Directory: t.TempDir(), Namespace: "a,b,c"},
+			Mode:   models.RestoreModeAuto,
+		},
+		Local: &models.Local{BufferSize: 1},
+		Throttle: &models.Throttle{
+			Adaptive: &models.AdaptiveThrottle{
+				CheckInterval: time.Second,
+				MaxCPUPct:     80,
+				MinRatePct:    10,
+			},
+		},
+	}
+
+	_, err := NewService(context.Background(), params, slog.Default())
+	require.ErrorContains(t, err, "adaptive throttling requires namespace")
+}
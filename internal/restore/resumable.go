@@ -0,0 +1,269 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/progress"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+// asbxRecordHeaderSize is the size of the digest and the payload size, that precede the payload of an .asbx record.
+const asbxRecordHeaderSize = 26
+
+// resumableFile reads a backup file record by record, so its restore can be stopped after any record,
+// and continued from the offset in the next batch or after a restart.
+// Offsets are counted in decompressed bytes and include the file header.
+type resumableFile struct {
+	name   string
+	source io.ReadCloser
+	// zstdDecoder is set if the file is compressed.
+	zstdDecoder *zstd.Decoder
+	// header is sent at the start of each part of the file, so each part is a valid file.
+	header []byte
+	// decoded contains bytes read by the decoder, that are not sent yet.
+	decoded bytes.Buffer
+	// next decodes the next token and returns its size in bytes.
+	next func() (size uint64, isRecord bool, err error)
+
+	// offset is the number of bytes of the file sent to the restore.
+	offset int64
+	// records is the number of records sent to the restore.
+	records uint64
+	// done is set when the file is read to the end.
+	done bool
+}
+
+// newResumableFile returns a reader of the file, that skips the part of the file restored before.
+func newResumableFile(
+	file bModels.File,
+	encoderType backup.EncoderType,
+	compression *backup.CompressionPolicy,
+	restored progress.File,
+	logger *slog.Logger,
+) (*resumableFile, error) {
+	f := &resumableFile{name: file.Name, source: file.Reader, records: restored.Records}
+
+	var src io.Reader = file.Reader
+
+	if compression != nil {
+		zstdDecoder, err := zstd.NewReader(file.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create decompression reader for %s: %w", file.Name, err)
+		}
+
+		f.zstdDecoder = zstdDecoder
+		src = zstdDecoder
+	}
+
+	reader := bufio.NewReader(src)
+
+	var err error
+	if encoderType == backup.EncoderTypeASBX {
+		f.header = make([]byte, asbxHeaderSize)
+		_, err = io.ReadFull(reader, f.header)
+	} else {
+		f.header, err = readASBHeader(reader)
+	}
+
+	if err != nil {
+		f.close()
+		return nil, fmt.Errorf("failed to read header of %s: %w", file.Name, err)
+	}
+
+	f.offset = int64(len(f.header))
+
+	// The restored part of the file is skipped.
+	if skip := restored.Offset - f.offset; skip > 0 {
+		if _, err = io.CopyN(io.Discard, reader, skip); err != nil {
+			f.close()
+			return nil, fmt.Errorf("failed to skip %d restored bytes of %s: %w", restored.Offset, file.Name, err)
+		}
+
+		f.offset = restored.Offset
+	}
+
+	// The decoder reads ahead, so read bytes are kept until their token is decoded.
+	decoderSrc := io.MultiReader(bytes.NewReader(f.header), io.TeeReader(reader, &f.decoded))
+
+	if encoderType == backup.EncoderTypeASBX {
+		err = f.initASBXDecoder(decoderSrc)
+	} else {
+		err = f.initASBDecoder(decoderSrc, logger)
+	}
+
+	if err != nil {
+		f.close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *resumableFile) initASBDecoder(src io.Reader, logger *slog.Logger) error {
+	// Unknown fields are checked by the restore, the decoder is used only to find record boundaries.
+	decoder, err := asb.NewDecoder[*bModels.Token](src, f.name, true, logger)
+	if err != nil {
+		return err
+	}
+
+	f.next = func() (uint64, bool, error) {
+		token, err := decoder.NextToken()
+		if err != nil {
+			return 0, false, err
+		}
+
+		return token.Size, token.Type == bModels.TokenTypeRecord, nil
+	}
+
+	return nil
+}
+
+func (f *resumableFile) initASBXDecoder(src io.Reader) error {
+	decoder, err := asbx.NewDecoder[*bModels.ASBXToken](src, binary.BigEndian.Uint64(f.header[1:9]), f.name)
+	if err != nil {
+		return err
+	}
+
+	f.next = func() (uint64, bool, error) {
+		token, err := decoder.NextToken()
+		if err != nil {
+			return 0, false, err
+		}
+
+		return uint64(asbxRecordHeaderSize + len(token.Payload)), true, nil
+	}
+
+	return nil
+}
+
+// readASBHeader reads the version line and the metadata lines of an .asb file.
+func readASBHeader(r *bufio.Reader) ([]byte, error) {
+	header, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		b, err := r.Peek(1)
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return header, nil
+		case err != nil:
+			return nil, err
+		case b[0] != '#':
+			return header, nil
+		}
+
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		header = append(header, line...)
+	}
+}
+
+// readToken appends the bytes of the next token to buf, or marks the file as done at its end.
+func (f *resumableFile) readToken(buf []byte) ([]byte, error) {
+	size, isRecord, err := f.next()
+
+	switch {
+	case errors.Is(err, io.EOF):
+		f.done = true
+		return buf, nil
+	case err != nil:
+		return buf, err
+	}
+
+	token := f.decoded.Next(int(size))
+	if uint64(len(token)) != size {
+		return buf, fmt.Errorf("failed to read token of %d bytes at offset %d of %s", size, f.offset, f.name)
+	}
+
+	f.offset += int64(size)
+
+	if isRecord {
+		f.records++
+	}
+
+	return append(buf, token...), nil
+}
+
+// part returns a reader of the rest of the file, that ends after a token, when stop is set.
+func (f *resumableFile) part(stop *atomic.Bool) io.ReadCloser {
+	return &filePart{file: f, stop: stop, pending: bytes.Clone(f.header)}
+}
+
+// state returns the restored part of the file, when all sent tokens are restored.
+func (f *resumableFile) state() progress.File {
+	return progress.File{Name: f.name, Offset: f.offset, Records: f.records, Done: f.done}
+}
+
+func (f *resumableFile) close() {
+	if f.zstdDecoder != nil {
+		f.zstdDecoder.Close()
+	}
+
+	_ = f.source.Close()
+}
+
+// filePart is a part of the file restored in one batch.
+type filePart struct {
+	file *resumableFile
+	stop *atomic.Bool
+	// pending contains whole tokens, that are not read yet.
+	pending []byte
+}
+
+func (p *filePart) Read(b []byte) (int, error) {
+	for len(p.pending) < len(b) && !p.file.done && !p.stop.Load() {
+		var err error
+		if p.pending, err = p.file.readToken(p.pending); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(p.pending) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(b, p.pending)
+	p.pending = p.pending[n:]
+
+	return n, nil
+}
+
+// Close closes the file when it is read to the end, otherwise it is continued by the next part.
+func (p *filePart) Close() error {
+	if p.file.done {
+		p.file.close()
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/progress"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeASB returns an .asb file with the records.
+func encodeASB(t *testing.T, records int) []byte {
+	t.Helper()
+
+	encoder := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig(testNamespace, false, false))
+	data := encoder.GetHeader(0, true)
+
+	for i := range records {
+		key, aerr := aerospike.NewKey(testNamespace, testSet, i)
+		require.NoError(t, aerr)
+
+		// A line break in a value doesn't end the record.
+		record := &aerospike.Record{
+			Key:        key,
+			Bins:       aerospike.BinMap{"bin": "line\nbreak"},
+			Generation: 1,
+		}
+
+		b, err := encoder.EncodeToken(bModels.NewRecordToken(&bModels.Record{Record: record}, 0, nil))
+		require.NoError(t, err)
+
+		data = append(data, b...)
+	}
+
+	return data
+}
+
+// decodeASBRecords returns the number of records in an .asb file.
+func decodeASBRecords(t *testing.T, data []byte) int {
+	t.Helper()
+
+	decoder, err := asb.NewDecoder[*bModels.Token](bytes.NewReader(data), "test.asb", false, nil)
+	require.NoError(t, err)
+
+	var records int
+
+	for {
+		_, err := decoder.NextToken()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+
+		require.NoError(t, err)
+
+		records++
+	}
+}
+
+func newTestResumableFile(
+	t *testing.T,
+	data []byte,
+	encoderType backup.EncoderType,
+	compression *backup.CompressionPolicy,
+	restored progress.File,
+) *resumableFile {
+	t.Helper()
+
+	file := bModels.File{Name: "0_test_7.asb", Reader: io.NopCloser(bytes.NewReader(data))}
+
+	f, err := newResumableFile(file, encoderType, compression, restored, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+
+	return f
+}
+
+func TestResumableFile(t *testing.T) {
+	t.Parallel()
+
+	data := encodeASB(t, 5)
+	header := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig(testNamespace, false, false)).GetHeader(0, true)
+
+	var stop atomic.Bool
+
+	f := newTestResumableFile(t, data, backup.EncoderTypeASB, nil, progress.File{})
+
+	// The first part is stopped after a record.
+	part := f.part(&stop)
+	first := make([]byte, len(header)+1)
+	_, err := io.ReadFull(part, first)
+	require.NoError(t, err)
+
+	stop.Store(true)
+
+	rest, err := io.ReadAll(part)
+	require.NoError(t, err)
+	require.NoError(t, part.Close())
+
+	first = append(first, rest...)
+	require.Equal(t, 1, decodeASBRecords(t, first))
+
+	state := f.state()
+	require.Equal(t, progress.File{Name: "0_test_7.asb", Offset: int64(len(first)), Records: 1}, state)
+
+	// The next part contains the header and the rest of the records.
+	stop.Store(false)
+
+	second, err := io.ReadAll(f.part(&stop))
+	require.NoError(t, err)
+	require.Equal(t, header, second[:len(header)])
+	require.Equal(t, data, append(first, second[len(header):]...))
+	require.Equal(t, progress.File{Name: "0_test_7.asb", Offset: int64(len(data)), Records: 5, Done: true}, f.state())
+
+	// After a restart, the file is continued from the saved offset.
+	f = newTestResumableFile(t, data, backup.EncoderTypeASB, nil, state)
+
+	continued, err := io.ReadAll(f.part(&stop))
+	require.NoError(t, err)
+	require.Equal(t, second, continued)
+	require.Equal(t, uint64(5), f.state().Records)
+}
+
+func TestResumableFile_CompressedASBX(t *testing.T) {
+	t.Parallel()
+
+	lut := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	data := encodeASBX(t, newLUTToken(t, 1, lut), newLUTToken(t, 2, lut), newLUTToken(t, 3, lut))
+	// Records have the same size.
+	recordSize := (len(data) - asbxHeaderSize) / 3
+
+	zstdEncoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+
+	compressed := zstdEncoder.EncodeAll(data, nil)
+	compression := backup.NewCompressionPolicy(backup.CompressZSTD, 3)
+
+	var stop atomic.Bool
+
+	// The first record is restored.
+	restored := progress.File{Name: "0_test_7.asb", Offset: int64(asbxHeaderSize + recordSize), Records: 1}
+	f := newTestResumableFile(t, compressed, backup.EncoderTypeASBX, compression, restored)
+
+	// The decompressed rest of the file is sent.
+	continued, err := io.ReadAll(f.part(&stop))
+	require.NoError(t, err)
+	require.Len(t, decodeASBX(t, continued), 2)
+	require.Equal(t, data[restored.Offset:], continued[asbxHeaderSize:])
+	require.Equal(t, progress.File{Name: "0_test_7.asb", Offset: int64(len(data)), Records: 3, Done: true}, f.state())
+}
+
+func TestNewResumableFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	data := encodeASB(t, 1)
+
+	_, err := newResumableFile(bModels.File{Name: "0_test_7.asb", Reader: io.NopCloser(bytes.NewReader(nil))},
+		backup.EncoderTypeASB, nil, progress.File{}, logger)
+	require.ErrorContains(t, err, "failed to read header of 0_test_7.asb")
+
+	// The file is shorter than the saved offset.
+	_, err = newResumableFile(bModels.File{Name: "0_test_7.asb", Reader: io.NopCloser(bytes.NewReader(data))},
+		backup.EncoderTypeASB, nil, progress.File{Offset: int64(len(data) + 1)}, logger)
+	require.ErrorContains(t, err, "failed to skip")
+}
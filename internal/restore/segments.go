@@ -0,0 +1,225 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/checkpoint"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/aerospike-backup-cli/internal/throttle"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/storage/common"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// segment contains readers of a continuous xdr backup segment.
+type segment struct {
+	name string
+	// mode overrides the restore mode, it is set for the scan backup.
+	mode      string
+	reader    backup.StreamingReader
+	xdrReader backup.StreamingReader
+}
+
+// newSegments returns readers for segments of a continuous xdr backup in the restore directory.
+// Returns nil if the directory doesn't contain a checkpoint.
+// Only segments saved to the checkpoint are restored, a segment interrupted by a crash is skipped.
+// If the backup has a scan backup, it is restored first, unless only .asbx files are restored.
+// With a point in time, records changed after it are skipped in segments finished later.
+func newSegments(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	sa *backup.SecretAgentConfig,
+	throttleController *throttle.Controller,
+	logger *slog.Logger,
+) ([]segment, error) {
+	if params.Restore.Directory == "" {
+		return nil, nil
+	}
+
+	checkpointReader, err := storage.NewCheckpointReader(ctx, params, sa, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint reader: %w", err)
+	}
+
+	cp, err := checkpoint.Read(ctx, checkpointReader, params.Restore.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	if cp == nil {
+		if params.Restore.PointInTime != "" {
+			return nil, fmt.Errorf("point in time requires a continuous xdr backup, no checkpoint in %s",
+				params.Restore.Directory)
+		}
+
+		return nil, nil
+	}
+
+	until, err := params.Restore.GetPointInTime()
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := cp.Replay(until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select segments of %s: %w", params.Restore.Directory, err)
+	}
+
+	logArgs := []any{
+		slog.Time("checkpoint", cp.Time),
+		slog.Int("segments", len(replay)),
+		slog.Bool("scan", cp.Scan != nil),
+	}
+
+	if len(replay) > 0 {
+		last := replay[len(replay)-1]
+		// Changes made before the restored point are restored, later ones can be restored only partially.
+		logArgs = append(logArgs,
+			slog.Time("restored_point", checkpoint.RestoredPoint(replay, until)),
+			slog.Time("last_segment_finished", last.Finished),
+		)
+	}
+
+	logger.Info("restoring continuous xdr backup", logArgs...)
+
+	result := make([]segment, 0, len(replay)+1)
+
+	if cp.Scan != nil && params.Restore.Mode != models.RestoreModeASBX {
+		s, err := newSegment(ctx, params, sa, throttleController, cp.Scan.Directory, models.RestoreModeASB,
+			time.Time{}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reader for scan backup %s: %w", cp.Scan.Directory, err)
+		}
+
+		result = append(result, *s)
+	}
+
+	for _, s := range replay {
+		var segmentUntil time.Time
+
+		if s.ContainsAfter(until) {
+			if params.EncryptionPolicy() != nil {
+				return nil, fmt.Errorf("point in time %s is inside segment %s, that can't be filtered in "+
+					"an encrypted backup, use a point in time after %s",
+					until.Format(time.RFC3339), s.Directory, s.Finished.Format(time.RFC3339))
+			}
+
+			segmentUntil = until
+		}
+
+		seg, err := newSegment(ctx, params, sa, throttleController, s.Directory, "", segmentUntil, logger)
+
+		switch {
+		case errors.Is(err, common.ErrEmptyStorage):
+			// Nothing was changed during the segment.
+			logger.Debug("skipping empty segment", slog.String("segment", s.Directory))
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("failed to create reader for segment %s: %w", s.Directory, err)
+		}
+
+		result = append(result, *seg)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: no files in segments of %s", common.ErrEmptyStorage, params.Restore.Directory)
+	}
+
+	return result, nil
+}
+
+// newSegment returns readers for the directory, relative to the restore directory.
+// If mode is empty, the configured restore mode is used.
+// If until is not zero, .asbx records changed after it are skipped.
+func newSegment(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	sa *backup.SecretAgentConfig,
+	throttleController *throttle.Controller,
+	directory, mode string,
+	until time.Time,
+	logger *slog.Logger,
+) (*segment, error) {
+	restore := *params.Restore
+	restore.Directory = path.Join(params.Restore.Directory, directory)
+
+	if mode != "" {
+		restore.Mode = mode
+	}
+
+	segmentParams := *params
+	segmentParams.Restore = &restore
+
+	reader, xdrReader, err := storage.NewRestoreReader(ctx, &segmentParams, sa, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if !until.IsZero() {
+		xdrReader = newPointInTimeReader(xdrReader, until, params.CompressionPolicy(), logger)
+	}
+
+	return &segment{
+		name:      directory,
+		mode:      mode,
+		reader:    throttle.NewReader(reader, throttleController),
+		xdrReader: throttle.NewReader(xdrReader, throttleController),
+	}, nil
+}
+
+// runSegments restores segments one by one, so later changes are applied last.
+func (r *Service) runSegments(ctx context.Context, logMessage string) (*bModels.RestoreStats, error) {
+	var result *bModels.RestoreStats
+
+	for _, s := range r.segments {
+		r.logger.Info("starting segment "+logMessage, slog.String("segment", s.name))
+
+		var (
+			stats *bModels.RestoreStats
+			err   error
+		)
+
+		mode := r.mode
+		if s.mode != "" {
+			mode = s.mode
+		}
+
+		switch mode {
+		case models.RestoreModeASB:
+			stats, err = r.run(ctx, backup.EncoderTypeASB, s.reader, logMessage)
+		case models.RestoreModeASBX:
+			stats, err = r.run(ctx, backup.EncoderTypeASBX, s.xdrReader, logMessage)
+		default:
+			stats, err = r.runAuto(ctx, s.reader, s.xdrReader, logMessage)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore segment %s: %w", s.name, err)
+		}
+
+		result = bModels.SumRestoreStats(result, stats)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/checkpoint"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSegments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+
+	newParams := func(directory string) *config.RestoreServiceConfig {
+		return &config.RestoreServiceConfig{
+			Restore: &models.Restore{
+				Common: models.Common{Directory: directory},
+				Mode:   models.RestoreModeAuto,
+			},
+		}
+	}
+
+	// Ordinary backup directory.
+	segments, err := newSegments(ctx, newParams(dir), nil, nil, logger)
+	require.NoError(t, err)
+	require.Nil(t, segments)
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cp := checkpoint.New(testNamespace)
+
+	for i := range 3 {
+		from := start.Add(time.Duration(i) * time.Minute)
+		cp.Add(checkpoint.Segment{Directory: checkpoint.SegmentName(from), From: from, To: from.Add(time.Minute)})
+	}
+
+	// The second segment has no changes.
+	for _, s := range []checkpoint.Segment{cp.Segments[0], cp.Segments[2]} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, s.Directory), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, s.Directory, "0_test_1.asbx"), []byte("data"), 0o600))
+	}
+
+	// The segment interrupted by a crash is not in the checkpoint.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, checkpoint.SegmentName(start.Add(time.Hour))), 0o755))
+
+	writer, err := local.NewWriter(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+	require.NoError(t, checkpoint.Write(ctx, writer, cp))
+
+	segments, err = newSegments(ctx, newParams(dir), nil, nil, logger)
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	require.Equal(t, cp.Segments[0].Directory, segments[0].name)
+	require.Equal(t, cp.Segments[2].Directory, segments[1].name)
+	require.Nil(t, segments[0].reader)
+	require.NotNil(t, segments[0].xdrReader)
+}
+
+func TestNewSegments_Scan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+
+	newParams := func(mode, pointInTime string) *config.RestoreServiceConfig {
+		return &config.RestoreServiceConfig{
+			Restore: &models.Restore{
+				Common:      models.Common{Directory: dir},
+				Mode:        mode,
+				PointInTime: pointInTime,
+			},
+		}
+	}
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cp := checkpoint.New(testNamespace)
+
+	for i := range 3 {
+		to := start.Add(time.Duration(i) * 10 * time.Minute)
+		cp.Add(checkpoint.Segment{Directory: checkpoint.SegmentName(to), To: to, Finished: to.Add(time.Minute)})
+
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, cp.Segments[i].Directory), 0o755))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, cp.Segments[i].Directory, "0_test_1.asbx"), []byte("data"), 0o600))
+	}
+
+	cp.Scan = &checkpoint.Scan{Directory: checkpoint.ScanDirectory, Handover: start, Finished: start.Add(5 * time.Minute)}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, checkpoint.ScanDirectory), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, checkpoint.ScanDirectory, "test_1.asb"), []byte("data"), 0o600))
+
+	writer, err := local.NewWriter(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+	require.NoError(t, checkpoint.Write(ctx, writer, cp))
+
+	// The scan backup is restored first, the segment before handover is skipped.
+	segments, err := newSegments(ctx, newParams(models.RestoreModeAuto, ""), nil, nil, logger)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+	require.Equal(t, checkpoint.ScanDirectory, segments[0].name)
+	require.Equal(t, models.RestoreModeASB, segments[0].mode)
+	require.NotNil(t, segments[0].reader)
+	require.Equal(t, cp.Segments[1].Directory, segments[1].name)
+	require.Empty(t, segments[1].mode)
+
+	// Point in time, changes after it are skipped in the segment finished later.
+	segments, err = newSegments(ctx, newParams(models.RestoreModeAuto, "2024-05-01T10:15:00Z"), nil, nil, logger)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+	require.Equal(t, cp.Segments[1].Directory, segments[1].name)
+	require.IsType(t, &local.Reader{}, segments[1].xdrReader)
+	require.Equal(t, cp.Segments[2].Directory, segments[2].name)
+	require.IsType(t, &pointInTimeReader{}, segments[2].xdrReader)
+
+	_, err = newSegments(ctx, newParams(models.RestoreModeAuto, "2024-05-01T10:04:00Z"), nil, nil, logger)
+	require.ErrorContains(t, err, "is before the scan backup finished")
+
+	// Encrypted segments can't be filtered.
+	params := newParams(models.RestoreModeAuto, "2024-05-01T10:15:00Z")
+	params.Encryption = &models.Encryption{Mode: "AES128", KeyFile: "key.pem"}
+	_, err = newSegments(ctx, params, nil, nil, logger)
+	require.ErrorContains(t, err, "can't be filtered in an encrypted backup, use a point in time after")
+
+	// Only segments are replayed in asbx mode.
+	segments, err = newSegments(ctx, newParams(models.RestoreModeASBX, ""), nil, nil, logger)
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	require.Equal(t, cp.Segments[1].Directory, segments[0].name)
+}
+
+func TestNewSegments_PointInTimeWithoutCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	params := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common:      models.Common{Directory: t.TempDir()},
+			Mode:        models.RestoreModeAuto,
+			PointInTime: "2024-05-01T10:00:00Z",
+		},
+	}
+
+	_, err := newSegments(context.Background(), params, nil, nil, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.ErrorContains(t, err, "point in time requires a continuous xdr backup")
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/shard"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/backup-go"
+)
+
+// checkShards verifies that all shards are finished, if the restore directory contains a distributed backup.
+func checkShards(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) error {
+	if params.Restore.Directory == "" {
+		return nil
+	}
+
+	reader, err := storage.NewCheckpointReader(ctx, params, sa, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create shard marker reader: %w", err)
+	}
+
+	markers, err := shard.Read(ctx, reader, params.Restore.Directory)
+	if err != nil {
+		return err
+	}
+
+	if err = shard.Check(markers); err != nil {
+		return fmt.Errorf("distributed backup in %s is incomplete: %w", params.Restore.Directory, err)
+	}
+
+	if len(markers) > 0 {
+		logger.Info("all shards of distributed backup are finished", slog.Int("shards", markers[0].Shards))
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/shard"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckShards(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+
+	params := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common: models.Common{Directory: dir},
+		},
+	}
+
+	// Ordinary backup directory.
+	require.NoError(t, checkShards(ctx, params, nil, logger))
+
+	writer, err := local.NewWriter(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+	require.NoError(t, shard.Write(ctx, writer, &shard.Marker{Shard: 1, Shards: 2, Namespace: testNamespace}))
+
+	err = checkShards(ctx, params, nil, logger)
+	require.ErrorContains(t, err, "is incomplete: 1 of 2 shards are not finished: 2")
+
+	require.NoError(t, shard.Write(ctx, writer, &shard.Marker{Shard: 2, Shards: 2, Namespace: testNamespace}))
+	require.NoError(t, checkShards(ctx, params, nil, logger))
+}
@@ -0,0 +1,340 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/progress"
+	"github.com/aerospike/aerospike-backup-cli/internal/storage"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// restoreState saves restored files and offsets within them, so an interrupted restore can be continued.
+type restoreState struct {
+	state    *progress.State
+	name     string
+	interval time.Duration
+	writer   backup.Writer
+	// compression is set if files are compressed, they are decompressed to be continued from an offset.
+	compression *backup.CompressionPolicy
+	// restored files are skipped, partially restored files are continued from the offset.
+	restored map[string]progress.File
+}
+
+// newRestoreState reads the state of an interrupted restore on continue, or returns a new state.
+func newRestoreState(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) (*restoreState, error) {
+	// An encrypted file can't be decrypted from an offset.
+	if params.EncryptionPolicy() != nil {
+		return nil, fmt.Errorf("state file is not supported for an encrypted backup")
+	}
+
+	directory, name := params.Restore.Directory, params.Restore.StateFile()
+	state := progress.New(directory)
+
+	if params.Restore.Continue != "" {
+		reader, err := storage.NewCheckpointReader(ctx, params, sa, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create state reader: %w", err)
+		}
+
+		state, err = progress.Read(ctx, reader, directory, name)
+		switch {
+		case err != nil:
+			return nil, err
+		case state == nil:
+			return nil, fmt.Errorf("state file %s not found in %s", name, directory)
+		case state.Directory != directory:
+			return nil, fmt.Errorf("state file %s is saved by restore of %s", name, state.Directory)
+		}
+
+		logger.Info("continuing restore",
+			slog.String("state_file", name),
+			slog.Int("restored_files", len(state.Files)),
+			slog.Uint64("restored_records", state.Records),
+		)
+	}
+
+	writer, err := storage.NewRestoreStateWriter(ctx, params, sa, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state writer: %w", err)
+	}
+
+	return &restoreState{
+		state:       state,
+		name:        name,
+		interval:    time.Duration(params.Restore.StateInterval) * time.Millisecond,
+		writer:      writer,
+		compression: params.CompressionPolicy(),
+		restored:    state.Restored(),
+	}, nil
+}
+
+// save adds files of a finished batch to the state and writes it.
+func (s *restoreState) save(ctx context.Context, files []progress.File, records uint64) error {
+	s.state.Add(files, records)
+
+	for _, f := range files {
+		s.restored[f.Name] = f
+	}
+
+	if err := progress.Write(ctx, s.writer, s.name, s.state); err != nil {
+		return fmt.Errorf("failed to save restore state: %w", err)
+	}
+
+	return nil
+}
+
+// runState restores files according to the restore mode, skipping files that are already restored.
+func (r *Service) runState(ctx context.Context, logMessage string) (*bModels.RestoreStats, error) {
+	var stats, xdrStats *bModels.RestoreStats
+
+	if r.reader != nil && r.mode != models.RestoreModeASBX {
+		var err error
+
+		stats, err = r.runBatches(ctx, backup.EncoderTypeASB, r.reader, logMessage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// .asbx files are replayed after .asb files, as they contain later changes.
+	if r.xdrReader != nil && r.mode != models.RestoreModeASB {
+		var err error
+
+		xdrStats, err = r.runBatches(ctx, backup.EncoderTypeASBX, r.xdrReader, logMessage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bModels.SumRestoreStats(xdrStats, stats), nil
+}
+
+// runBatches restores files of the reader in batches, the state is saved after each batch.
+// A batch is finished when files in progress are restored to the end of a record after the state interval.
+func (r *Service) runBatches(
+	ctx context.Context, encoderType backup.EncoderType, reader backup.StreamingReader, logMessage string,
+) (*bModels.RestoreStats, error) {
+	// The stream outlives batches, so it is stopped only when the restore is finished.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	br := newBatchReader(reader, encoderType, r.state, r.logger)
+	br.start(ctx)
+
+	var result *bModels.RestoreStats
+
+	for !br.finished {
+		stats, err := r.run(ctx, encoderType, br, logMessage)
+		if err != nil {
+			return nil, err
+		}
+
+		result = bModels.SumRestoreStats(result, stats)
+
+		files := br.commit()
+		if len(files) == 0 {
+			continue
+		}
+
+		if err = r.state.save(ctx, files, stats.GetReadRecords()); err != nil {
+			return nil, err
+		}
+
+		r.logger.Info("restore state is saved",
+			slog.String("state_file", r.state.name),
+			slog.Int("batch_files", len(files)),
+			slog.Int("started_files", len(r.state.state.Files)),
+			slog.Uint64("restored_records", r.state.state.Records),
+		)
+	}
+
+	return result, nil
+}
+
+// batchReader streams files of the wrapped reader in batches, each batch is restored by a separate run.
+// Files that are already restored are skipped, partially restored files are continued from the offset.
+type batchReader struct {
+	backup.StreamingReader
+
+	encoderType backup.EncoderType
+	compression *backup.CompressionPolicy
+	files       chan bModels.File
+	errors      chan error
+	restored    map[string]progress.File
+	interval    time.Duration
+	logger      *slog.Logger
+
+	// stop is set when the interval is reached, files in progress are stopped after a record.
+	stop atomic.Bool
+	// batch contains files streamed in the current run.
+	batch []*resumableFile
+	// unfinished files are continued in the next run.
+	unfinished []*resumableFile
+	// finished is set when all files are streamed.
+	finished bool
+}
+
+func newBatchReader(
+	reader backup.StreamingReader, encoderType backup.EncoderType, state *restoreState, logger *slog.Logger,
+) *batchReader {
+	return &batchReader{
+		StreamingReader: reader,
+		encoderType:     encoderType,
+		compression:     state.compression,
+		files:           make(chan bModels.File),
+		errors:          make(chan error, 1),
+		restored:        state.restored,
+		interval:        state.interval,
+		logger:          logger,
+	}
+}
+
+// start streams files of the wrapped reader until ctx is canceled.
+func (b *batchReader) start(ctx context.Context) {
+	go b.StreamingReader.StreamFiles(ctx, b.files, b.errors, nil)
+}
+
+// StreamFiles sends files to readersCh until the interval is reached or all files are streamed.
+// Files unfinished in the previous run are sent first.
+func (b *batchReader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, _ []string,
+) {
+	defer close(readersCh)
+
+	b.stop.Store(false)
+
+	timer := time.NewTimer(b.interval)
+	defer timer.Stop()
+
+	// Files in progress are stopped after a record, so the run is finished soon after the interval.
+	defer func() {
+		if !b.finished {
+			b.stop.Store(true)
+		}
+	}()
+
+	queue := b.unfinished
+	b.unfinished = nil
+
+	// Files that are not sent are continued in the next run.
+	defer func() {
+		b.unfinished = append(b.unfinished, queue...)
+	}()
+
+	for {
+		if len(queue) == 0 {
+			f, ok := b.next(ctx, timer.C, errorsCh)
+			if !ok {
+				return
+			}
+
+			queue = append(queue, f)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			return
+		case readersCh <- bModels.File{Name: queue[0].name, Reader: queue[0].part(&b.stop)}:
+			b.batch = append(b.batch, queue[0])
+			queue = queue[1:]
+		}
+	}
+}
+
+// next returns the next file of the wrapped reader, that is not restored yet.
+// Returns false if the batch must be finished.
+func (b *batchReader) next(
+	ctx context.Context, timeout <-chan time.Time, errorsCh chan<- error,
+) (*resumableFile, bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-timeout:
+			return nil, false
+		case err := <-b.errors:
+			b.sendError(ctx, errorsCh, err)
+			return nil, false
+		case file, ok := <-b.files:
+			if !ok {
+				b.finished = true
+				return nil, false
+			}
+
+			restored := b.restored[file.Name]
+			if restored.Done {
+				b.logger.Debug("skipping restored file", slog.String("file", file.Name))
+				_ = file.Reader.Close()
+
+				continue
+			}
+
+			if restored.Offset > 0 {
+				b.logger.Debug("continuing restored file",
+					slog.String("file", file.Name),
+					slog.Int64("offset", restored.Offset),
+				)
+			}
+
+			f, err := newResumableFile(file, b.encoderType, b.compression, restored, b.logger)
+			if err != nil {
+				b.sendError(ctx, errorsCh, err)
+				return nil, false
+			}
+
+			return f, true
+		}
+	}
+}
+
+func (b *batchReader) sendError(ctx context.Context, errorsCh chan<- error, err error) {
+	select {
+	case errorsCh <- err:
+	case <-ctx.Done():
+	}
+}
+
+// commit returns files of the finished batch and starts a new batch.
+// Files that are not read to the end are continued in the next batch.
+func (b *batchReader) commit() []progress.File {
+	files := make([]progress.File, 0, len(b.batch))
+	for _, f := range b.batch {
+		files = append(files, f.state())
+
+		if !f.done {
+			b.unfinished = append(b.unfinished, f)
+		}
+	}
+
+	b.batch = nil
+
+	return files
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-backup-cli/internal/progress"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/require"
+)
+
+// streamBatch reads all files of a batch, as a restore run does.
+func streamBatch(ctx context.Context, t *testing.T, br *batchReader) []string {
+	t.Helper()
+
+	readersCh := make(chan bModels.File)
+	errorsCh := make(chan error, 1)
+
+	go br.StreamFiles(ctx, readersCh, errorsCh, nil)
+
+	var names []string
+
+	for file := range readersCh {
+		_, err := io.Copy(io.Discard, file.Reader)
+		require.NoError(t, err)
+		require.NoError(t, file.Reader.Close())
+
+		names = append(names, file.Name)
+	}
+
+	require.Empty(t, errorsCh)
+	slices.Sort(names)
+
+	return names
+}
+
+func newTestBatchReader(
+	ctx context.Context, t *testing.T, files map[string][]byte, restored map[string]progress.File, interval time.Duration,
+) *batchReader {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	reader, err := local.NewReader(ctx, options.WithDir(dir))
+	require.NoError(t, err)
+
+	state := &restoreState{restored: restored, interval: interval}
+	br := newBatchReader(reader, backup.EncoderTypeASB, state, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	br.start(ctx)
+
+	return br
+}
+
+func TestBatchReader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	data := encodeASB(t, 3)
+	files := map[string][]byte{"0_test_1.asb": data, "1_test_1.asb": data, "2_test_1.asb": data}
+	restored := map[string]progress.File{"1_test_1.asb": {Name: "1_test_1.asb", Done: true}}
+
+	br := newTestBatchReader(ctx, t, files, restored, time.Minute)
+
+	require.Equal(t, []string{"0_test_1.asb", "2_test_1.asb"}, streamBatch(ctx, t, br))
+	require.True(t, br.finished)
+
+	committed := br.commit()
+	slices.SortFunc(committed, func(a, b progress.File) int { return strings.Compare(a.Name, b.Name) })
+	require.Equal(t, []progress.File{
+		{Name: "0_test_1.asb", Offset: int64(len(data)), Records: 3, Done: true},
+		{Name: "2_test_1.asb", Offset: int64(len(data)), Records: 3, Done: true},
+	}, committed)
+
+	require.Empty(t, br.commit())
+}
+
+func TestBatchReader_Interval(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	data := encodeASB(t, 3)
+	files := map[string][]byte{"0_test_1.asb": data, "1_test_1.asb": data}
+
+	br := newTestBatchReader(ctx, t, files, nil, 50*time.Millisecond)
+
+	readersCh := make(chan bModels.File)
+	go br.StreamFiles(ctx, readersCh, make(chan error, 1), nil)
+
+	// The first file is in progress, when the interval is reached.
+	file := <-readersCh
+	header := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig(testNamespace, false, false)).GetHeader(0, true)
+	_, err := io.ReadFull(file.Reader, make([]byte, len(header)+1))
+	require.NoError(t, err)
+
+	// The second file is not sent, as no worker reads it.
+	require.Eventually(t, br.stop.Load, time.Second, 5*time.Millisecond)
+
+	_, ok := <-readersCh
+	require.False(t, ok)
+
+	// The rest of the file is read after a record.
+	_, err = io.Copy(io.Discard, file.Reader)
+	require.NoError(t, err)
+	require.NoError(t, file.Reader.Close())
+	require.False(t, br.finished)
+
+	committed := br.commit()
+	require.Len(t, committed, 1)
+	require.Equal(t, file.Name, committed[0].Name)
+	require.Equal(t, uint64(1), committed[0].Records)
+	require.False(t, committed[0].Done)
+
+	// Both files are finished in the next batch.
+	require.Equal(t, []string{"0_test_1.asb", "1_test_1.asb"}, streamBatch(ctx, t, br))
+	require.True(t, br.finished)
+
+	committed = br.commit()
+	require.Len(t, committed, 2)
+
+	for _, f := range committed {
+		require.Equal(t, progress.File{Name: f.Name, Offset: int64(len(data)), Records: 3, Done: true}, f)
+	}
+}
+
+func TestNewRestoreState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+
+	params := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common:        models.Common{Directory: dir},
+			Continue:      "restore.state",
+			StateInterval: 1000,
+		},
+	}
+
+	_, err := newRestoreState(ctx, params, nil, logger)
+	require.ErrorContains(t, err, "state file restore.state not found")
+
+	params.Restore.Continue = ""
+	params.Restore.StateFileDst = "restore.state"
+
+	s, err := newRestoreState(ctx, params, nil, logger)
+	require.NoError(t, err)
+	require.NoError(t, s.save(ctx, []progress.File{{Name: "0_test_1.asb", Offset: 10, Records: 5}}, 5))
+
+	params.Restore.StateFileDst = ""
+	params.Restore.Continue = "restore.state"
+
+	s, err = newRestoreState(ctx, params, nil, logger)
+	require.NoError(t, err)
+	require.Equal(t, progress.File{Name: "0_test_1.asb", Offset: 10, Records: 5}, s.restored["0_test_1.asb"])
+	require.Equal(t, uint64(5), s.state.Records)
+	require.Equal(t, time.Second, s.interval)
+}
+
+func TestNewRestoreState_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	params := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common:        models.Common{Directory: t.TempDir()},
+			StateFileDst:  "restore.state",
+			StateInterval: 1000,
+		},
+		Encryption: &models.Encryption{Mode: "AES128", KeyFile: "key.pem"},
+	}
+
+	_, err := newRestoreState(context.Background(), params, nil, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.ErrorContains(t, err, "state file is not supported for an encrypted backup")
+}
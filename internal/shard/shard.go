@@ -0,0 +1,184 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/storage/objectpath"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// markerPattern matches names of completion markers, e.g. shard_1_of_4.json.
+var markerPattern = regexp.MustCompile(`^shard_(\d+)_of_(\d+)\.json$`)
+
+// Marker is written to the backup directory when a shard of a distributed backup is finished.
+type Marker struct {
+	Shard     int    `json:"shard"`
+	Shards    int    `json:"shards"`
+	Namespace string `json:"namespace"`
+	// Partitions of the shard.
+	PartitionBegin int       `json:"partition_begin"`
+	PartitionCount int       `json:"partition_count"`
+	Records        uint64    `json:"records"`
+	Files          uint64    `json:"files"`
+	Finished       time.Time `json:"finished"`
+}
+
+// Partitions returns the range of partitions of the shard. Shards are numbered from 1.
+// Partitions are split evenly, so each process gets the same range for the same shard.
+func Partitions(shard, shards int) (begin, count int) {
+	begin = (shard - 1) * backup.MaxPartitions / shards
+	end := shard * backup.MaxPartitions / shards
+
+	return begin, end - begin
+}
+
+// FilePrefix returns the prefix of backup files of the shard, so shards don't overwrite files of each other.
+func FilePrefix(shard, shards int) string {
+	return fmt.Sprintf("shard%dof%d_", shard, shards)
+}
+
+// MarkerName returns the name of the completion marker of the shard.
+func MarkerName(shard, shards int) string {
+	return fmt.Sprintf("shard_%d_of_%d.json", shard, shards)
+}
+
+// Write writes the completion marker to the writer directory.
+func Write(ctx context.Context, writer backup.Writer, m *Marker) error {
+	name := MarkerName(m.Shard, m.Shards)
+
+	file, err := writer.NewWriter(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	if err = json.NewEncoder(file).Encode(m); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Read reads all completion markers from the directory, sorted by shard.
+// Returns nil if the directory doesn't contain markers.
+func Read(ctx context.Context, reader backup.StreamingReader, dir string) ([]Marker, error) {
+	objects, err := reader.ListObjects(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var markers []Marker
+
+	for _, object := range objects {
+		if !IsMarker(object) {
+			continue
+		}
+
+		m, err := readFile(ctx, reader, object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read shard marker %s: %w", object, err)
+		}
+
+		markers = append(markers, *m)
+	}
+
+	slices.SortFunc(markers, func(a, b Marker) int {
+		return a.Shard - b.Shard
+	})
+
+	return markers, nil
+}
+
+// IsMarker checks if the object is a completion marker.
+func IsMarker(object string) bool {
+	return markerPattern.MatchString(objectpath.BaseName(object))
+}
+
+func readFile(ctx context.Context, reader backup.StreamingReader, name string) (*Marker, error) {
+	readCh := make(chan bModels.File, 1)
+	errCh := make(chan error, 1)
+
+	go reader.StreamFile(ctx, name, readCh, errCh)
+
+	var file bModels.File
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-errCh:
+		return nil, err
+	case file = <-readCh:
+	}
+
+	defer file.Reader.Close()
+
+	var m Marker
+	if err := json.NewDecoder(file.Reader).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode: %w", err)
+	}
+
+	return &m, nil
+}
+
+// Check verifies that markers of all shards are present and belong to the same backup.
+func Check(markers []Marker) error {
+	if len(markers) == 0 {
+		return nil
+	}
+
+	shards := markers[0].Shards
+	present := make(map[int]bool, len(markers))
+
+	for _, m := range markers {
+		if m.Shards != shards {
+			return fmt.Errorf("shards of different backups: %d/%d and %d/%d",
+				markers[0].Shard, shards, m.Shard, m.Shards)
+		}
+
+		if m.Namespace != markers[0].Namespace {
+			return fmt.Errorf("shards of different namespaces: %s and %s", markers[0].Namespace, m.Namespace)
+		}
+
+		present[m.Shard] = true
+	}
+
+	missing := make([]string, 0)
+
+	for i := 1; i <= shards; i++ {
+		if !present[i] {
+			missing = append(missing, strconv.Itoa(i))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%d of %d shards are not finished: %s", len(missing), shards, strings.Join(missing, ","))
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/require"
+)
+
+const testNamespace = "test"
+
+func TestPartitions(t *testing.T) {
+	t.Parallel()
+
+	for _, shards := range []int{1, 3, 7, backup.MaxPartitions} {
+		next := 0
+
+		for shard := 1; shard <= shards; shard++ {
+			begin, count := Partitions(shard, shards)
+			// Shards are adjacent and not empty.
+			require.Equal(t, next, begin)
+			require.Positive(t, count)
+
+			next = begin + count
+		}
+
+		require.Equal(t, backup.MaxPartitions, next)
+	}
+
+	begin, count := Partitions(2, 3)
+	require.Equal(t, 1365, begin)
+	require.Equal(t, 1365, count)
+}
+
+func TestWriteRead(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	writer, err := local.NewWriter(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	reader, err := local.NewReader(ctx, options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	markers, err := Read(ctx, reader, dir)
+	require.NoError(t, err)
+	require.Empty(t, markers)
+
+	finished := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, shard := range []int{3, 1} {
+		begin, count := Partitions(shard, 3)
+		require.NoError(t, Write(ctx, writer, &Marker{
+			Shard:          shard,
+			Shards:         3,
+			Namespace:      testNamespace,
+			PartitionBegin: begin,
+			PartitionCount: count,
+			Records:        10,
+			Finished:       finished,
+		}))
+	}
+
+	markers, err = Read(ctx, reader, dir)
+	require.NoError(t, err)
+	require.Len(t, markers, 2)
+	require.Equal(t, 1, markers[0].Shard)
+	require.Equal(t, 3, markers[1].Shard)
+	require.Equal(t, 2730, markers[1].PartitionBegin)
+	require.True(t, finished.Equal(markers[1].Finished))
+
+	require.ErrorContains(t, Check(markers), "1 of 3 shards are not finished: 2")
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		markers []Marker
+		wantErr string
+	}{
+		{
+			name: "not sharded",
+		},
+		{
+			name: "all shards",
+			markers: []Marker{
+				{Shard: 1, Shards: 2, Namespace: testNamespace},
+				{Shard: 2, Shards: 2, Namespace: testNamespace},
+			},
+		},
+		{
+			name: "missing shards",
+			markers: []Marker{
+				{Shard: 2, Shards: 4, Namespace: testNamespace},
+			},
+			wantErr: "3 of 4 shards are not finished: 1,3,4",
+		},
+		{
+			name: "different backups",
+			markers: []Marker{
+				{Shard: 1, Shards: 2, Namespace: testNamespace},
+				{Shard: 2, Shards: 3, Namespace: testNamespace},
+			},
+			wantErr: "shards of different backups: 1/2 and 2/3",
+		},
+		{
+			name: "different namespaces",
+			markers: []Marker{
+				{Shard: 1, Shards: 2, Namespace: testNamespace},
+				{Shard: 2, Shards: 2, Namespace: "other"},
+			},
+			wantErr: "shards of different namespaces: test and other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Check(tt.markers)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestIsMarker(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsMarker("backup/shard_1_of_4.json"))
+	require.True(t, IsMarker("shard_12_of_4096.json"))
+	require.False(t, IsMarker("backup/shard1of4_test_1.asb"))
+	require.False(t, IsMarker("xdr_checkpoint.json"))
+}
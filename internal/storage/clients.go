@@ -16,11 +16,21 @@ package storage
 
 import (
 	"context"
+	"crypto/md5" //nolint:gosec // S3 requires MD5 digest of SSE-C key.
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	gcpStorage "cloud.google.com/go/storage"
@@ -37,10 +47,19 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
 	"github.com/googleapis/gax-go/v2"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
 	"google.golang.org/api/option"
 )
 
@@ -111,7 +130,13 @@ func NewAerospikeClient(
 	return asClient, nil
 }
 
-func newS3Client(ctx context.Context, a *models.AwsS3) (*s3.Client, error) {
+// newS3Client returns a client for the storage params, optFns are applied after the params.
+func newS3Client(ctx context.Context, a *models.AwsS3, optFns ...func(*s3.Options)) (*s3.Client, error) {
+	transport, err := newTransport(&a.StorageCommon)
+	if err != nil {
+		return nil, err
+	}
+
 	cfgOpts := make([]func(*config.LoadOptions) error, 0)
 
 	// use an adaptive mode for more aggressive retries
@@ -127,7 +152,7 @@ func newS3Client(ctx context.Context, a *models.AwsS3) (*s3.Client, error) {
 			})
 		}),
 		config.WithHTTPClient(
-			newHTTPClient(newTransport(a.MaxConnsPerHost), a.RequestTimeout)),
+			newHTTPClient(transport, a.RequestTimeout)),
 	)
 
 	if a.Profile != "" {
@@ -151,31 +176,167 @@ func newS3Client(ctx context.Context, a *models.AwsS3) (*s3.Client, error) {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	s3Client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+	if a.RoleArn != "" {
+		// The cache refreshes credentials before they expire, so long backups are not interrupted.
+		cfg.Credentials = aws.NewCredentialsCache(newS3RoleProvider(cfg, a))
+	}
+
+	sse, err := newS3SSE(a)
+	if err != nil {
+		return nil, err
+	}
+
+	optFns = append([]func(*s3.Options){func(o *s3.Options) {
 		if a.Endpoint != "" {
 			o.BaseEndpoint = &a.Endpoint
 		}
 
-		o.UsePathStyle = true
+		if sse != nil {
+			o.APIOptions = append(o.APIOptions, sse.addMiddleware)
+		}
+
+		o.UsePathStyle = a.AddressingStyle != models.S3AddressingStyleVirtualHosted
 		o.DisableLogOutputChecksumValidationSkipped = true
-	})
+	}}, optFns...)
+
+	s3Client := s3.NewFromConfig(cfg, optFns...)
 
 	return s3Client, nil
 }
 
-func newGcpClient(ctx context.Context, g *models.GcpStorage) (*gcpStorage.Client, error) {
-	opts := make([]option.ClientOption, 0)
+// newS3RoleProvider returns a provider of temporary credentials of the assumed role.
+// STS is called with the base credentials from cfg, or with the web identity token if it is set.
+func newS3RoleProvider(cfg aws.Config, a *models.AwsS3) aws.CredentialsProvider {
+	stsClient := sts.NewFromConfig(cfg)
 
-	var transport http.RoundTripper = newTransport(g.MaxConnsPerHost)
-	// GCP can't apply option.WithCredentialsFile() with custom http client option.WithHTTPClient().
-	// So we implement our own logic to load auth key and set http headers.
-	if g.KeyFile != "" {
-		creds, err := getGcpAuth(ctx, g.KeyFile)
+	if a.WebIdentityTokenFile != "" {
+		return stscreds.NewWebIdentityRoleProvider(stsClient, a.RoleArn,
+			stscreds.IdentityTokenFile(a.WebIdentityTokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = a.SessionName
+			})
+	}
+
+	return stscreds.NewAssumeRoleProvider(stsClient, a.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		if a.SessionName != "" {
+			o.RoleSessionName = a.SessionName
+		}
+
+		if a.ExternalID != "" {
+			o.ExternalID = aws.String(a.ExternalID)
+		}
+	})
+}
+
+// s3SSE contains server-side encryption params, that are set on each request of the S3 client,
+// as backup-go doesn't expose them in upload and download inputs.
+type s3SSE struct {
+	encryption types.ServerSideEncryption
+	kmsKeyID   *string
+	// SSE-C params, they are required on uploads and on reads of encrypted objects.
+	customerAlgorithm *string
+	customerKey       *string
+	customerKeyMD5    *string
+}
+
+// newS3SSE returns server-side encryption params, or nil if they are not set.
+func newS3SSE(a *models.AwsS3) (*s3SSE, error) {
+	sse := &s3SSE{}
+
+	switch a.SSE {
+	case models.S3SSEAES256:
+		sse.encryption = types.ServerSideEncryptionAes256
+	case models.S3SSEKMS:
+		sse.encryption = types.ServerSideEncryptionAwsKms
+
+		if a.SSEKmsKeyID != "" {
+			sse.kmsKeyID = aws.String(a.SSEKmsKeyID)
+		}
+	}
+
+	if a.SSECustomerKey != "" {
+		key, err := base64.StdEncoding.DecodeString(a.SSECustomerKey)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to decode sse customer key: %w", err)
 		}
+
+		sum := md5.Sum(key) //nolint:gosec // S3 requires MD5 digest of SSE-C key.
+
+		sse.customerAlgorithm = aws.String(string(types.ServerSideEncryptionAes256))
+		sse.customerKey = aws.String(a.SSECustomerKey)
+		sse.customerKeyMD5 = aws.String(base64.StdEncoding.EncodeToString(sum[:]))
+	}
+
+	if sse.encryption == "" && sse.customerKey == nil {
+		return nil, nil
+	}
+
+	return sse, nil
+}
+
+func (e *s3SSE) addMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ServerSideEncryption", e.handle),
+		middleware.After)
+}
+
+// handle sets encryption params on a copy of the request input, so the caller's input is not modified.
+func (e *s3SSE) handle(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	switch v := in.Parameters.(type) {
+	case *s3.PutObjectInput:
+		p := *v
+		p.ServerSideEncryption, p.SSEKMSKeyId = e.encryption, e.kmsKeyID
+		p.SSECustomerAlgorithm, p.SSECustomerKey, p.SSECustomerKeyMD5 = e.customer()
+		in.Parameters = &p
+	case *s3.CreateMultipartUploadInput:
+		p := *v
+		p.ServerSideEncryption, p.SSEKMSKeyId = e.encryption, e.kmsKeyID
+		p.SSECustomerAlgorithm, p.SSECustomerKey, p.SSECustomerKeyMD5 = e.customer()
+		in.Parameters = &p
+	case *s3.UploadPartInput:
+		p := *v
+		p.SSECustomerAlgorithm, p.SSECustomerKey, p.SSECustomerKeyMD5 = e.customer()
+		in.Parameters = &p
+	case *s3.CompleteMultipartUploadInput:
+		p := *v
+		p.SSECustomerAlgorithm, p.SSECustomerKey, p.SSECustomerKeyMD5 = e.customer()
+		in.Parameters = &p
+	case *s3.GetObjectInput:
+		p := *v
+		p.SSECustomerAlgorithm, p.SSECustomerKey, p.SSECustomerKeyMD5 = e.customer()
+		in.Parameters = &p
+	case *s3.HeadObjectInput:
+		p := *v
+		p.SSECustomerAlgorithm, p.SSECustomerKey, p.SSECustomerKeyMD5 = e.customer()
+		in.Parameters = &p
+	}
+
+	return next.HandleInitialize(ctx, in)
+}
+
+func (e *s3SSE) customer() (algorithm, key, keyMD5 *string) {
+	return e.customerAlgorithm, e.customerKey, e.customerKeyMD5
+}
+
+func newGcpClient(ctx context.Context, g *models.GcpStorage) (*gcpStorage.Client, error) {
+	opts := make([]option.ClientOption, 0)
+
+	baseTransport, err := newTransport(&g.StorageCommon)
+	if err != nil {
+		return nil, err
+	}
+
+	var transport http.RoundTripper = baseTransport
+	// GCP can't apply credentials options together with custom http client option.WithHTTPClient().
+	// So we implement our own logic to load credentials and set http headers.
+	tokenSource, err := newGcpTokenSource(ctx, g, baseTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenSource != nil {
 		// Use client with custom auth.
-		transport = newAuthTransport(transport, creds.TokenSource)
+		transport = newAuthTransport(transport, tokenSource)
 	}
 
 	opts = append(opts, option.WithHTTPClient(newHTTPClient(transport, g.RequestTimeout)))
@@ -203,6 +364,49 @@ func newGcpClient(ctx context.Context, g *models.GcpStorage) (*gcpStorage.Client
 	return gcpClient, nil
 }
 
+// newGcpTokenSource returns a token source for the key file or application default credentials,
+// impersonating the service account if it is set. Returns nil if the client must not be authenticated,
+// that is for the endpoint override without credentials. Token requests use the base transport.
+func newGcpTokenSource(ctx context.Context, g *models.GcpStorage, base http.RoundTripper,
+) (oauth2.TokenSource, error) {
+	// oauth2 takes the http client for token requests from the context.
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, newHTTPClient(base, g.RequestTimeout))
+
+	var (
+		creds *google.Credentials
+		err   error
+	)
+
+	switch {
+	case g.KeyFile != "":
+		creds, err = getGcpAuth(ctx, g.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+	case g.Endpoint == "" || g.ImpersonateServiceAccount != "":
+		creds, err = google.FindDefaultCredentials(ctx, gcpStorage.ScopeReadWrite)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find GCP application default credentials: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	if g.ImpersonateServiceAccount == "" {
+		return creds.TokenSource, nil
+	}
+
+	tokenSource, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: g.ImpersonateServiceAccount,
+		Scopes:          []string{gcpStorage.ScopeReadWrite},
+	}, option.WithHTTPClient(newHTTPClient(newAuthTransport(base, creds.TokenSource), g.RequestTimeout)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate GCP service account %s: %w", g.ImpersonateServiceAccount, err)
+	}
+
+	return tokenSource, nil
+}
+
 // getGcpAuth read and load auth key from a file for GCP.
 func getGcpAuth(ctx context.Context, keyFile string) (*google.Credentials, error) {
 	jsonKey, err := os.ReadFile(keyFile)
@@ -220,15 +424,18 @@ func getGcpAuth(ctx context.Context, keyFile string) (*google.Credentials, error
 	return creds, nil
 }
 
-func newAzureClient(a *models.AzureBlob) (*azblob.Client, error) {
-	var (
-		azClient *azblob.Client
-		err      error
-	)
+// newAzureClient returns a client for the storage params, perCallPolicies are added to its pipeline.
+func newAzureClient(a *models.AzureBlob, perCallPolicies ...policy.Policy) (*azblob.Client, error) {
+	var azClient *azblob.Client
+
+	transport, err := newTransport(&a.StorageCommon)
+	if err != nil {
+		return nil, err
+	}
 
 	azOpts := &azblob.ClientOptions{
 		ClientOptions: azcore.ClientOptions{
-			Transport: newHTTPClient(newTransport(a.MaxConnsPerHost), a.RequestTimeout),
+			Transport: newHTTPClient(transport, a.RequestTimeout),
 			Retry: policy.RetryOptions{
 				MaxRetries:    int32(a.RetryMaxAttempts),
 				RetryDelay:    time.Duration(a.RetryDelay) * time.Millisecond,
@@ -242,11 +449,12 @@ func newAzureClient(a *models.AzureBlob) (*azblob.Client, error) {
 					http.StatusGatewayTimeout,
 				},
 			},
+			PerCallPolicies: perCallPolicies,
 		},
 	}
 
-	switch {
-	case a.AccountName != "" && a.AccountKey != "":
+	switch azureAuthMode(a) {
+	case models.AzureAuthModeSharedKey:
 		cred, err := azblob.NewSharedKeyCredential(a.AccountName, a.AccountKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Azure shared key credentials: %w", err)
@@ -256,20 +464,34 @@ func newAzureClient(a *models.AzureBlob) (*azblob.Client, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Azure Blob client with shared key: %w", err)
 		}
-	case a.TenantID != "" && a.ClientID != "" && a.ClientSecret != "":
-		cred, err := azidentity.NewClientSecretCredential(a.TenantID, a.ClientID, a.ClientSecret, nil)
+	case models.AzureAuthModeSAS:
+		endpoint, err := azureSASEndpoint(a.Endpoint, a.SASToken)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Azure AAD credentials: %w", err)
+			return nil, err
 		}
 
-		azClient, err = azblob.NewClient(a.Endpoint, cred, azOpts)
+		azClient, err = azblob.NewClientWithNoCredential(endpoint, azOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Azure Blob client with AAD: %w", err)
+			return nil, fmt.Errorf("failed to create Azure Blob client with SAS: %w", err)
 		}
-	default:
+	case "":
+		// Public containers or SAS token already included in the endpoint.
 		azClient, err = azblob.NewClientWithNoCredential(a.Endpoint, azOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Azure Blob client with SAS: %w", err)
+			return nil, fmt.Errorf("failed to create Azure Blob client with no credential: %w", err)
+		}
+	default:
+		credOpts := azOpts.ClientOptions
+		credOpts.PerCallPolicies = nil
+
+		cred, err := newAzureTokenCredential(a, credOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		azClient, err = azblob.NewClient(a.Endpoint, cred, azOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Azure Blob client with %s auth: %w", azureAuthMode(a), err)
 		}
 	}
 
@@ -289,20 +511,77 @@ func toHosts(htpSlice client.HostTLSPortSlice) []*aerospike.Host {
 	return hosts
 }
 
-// newTransport returns a new http.Transport.
-func newTransport(maxConnsPerHost int) *http.Transport {
+// newTransport returns a new http.Transport with the connection, TLS and proxy settings of the storage.
+func newTransport(s *models.StorageCommon) (*http.Transport, error) {
+	tlsConfig, err := newStorageTLSConfig(s)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+
+	if s.ProxyURL != "" {
+		proxyURL, err := url.Parse(s.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy url: %w", err)
+		}
+
+		proxy = http.ProxyURL(proxyURL)
+	}
+
 	return &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
+		Proxy: proxy,
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
 			KeepAlive: 30 * time.Second,
 		}).DialContext,
-		MaxConnsPerHost:     maxConnsPerHost,
+		MaxConnsPerHost:     s.MaxConnsPerHost,
 		IdleConnTimeout:     120 * time.Second,
 		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     tlsConfig,
 		ReadBufferSize:      64 * 1024,
 		ForceAttemptHTTP2:   true,
+	}, nil
+}
+
+// newStorageTLSConfig returns TLS config with the CA bundle and client certificate of the storage,
+// or nil if they are not set, so the default config is used.
+func newStorageTLSConfig(s *models.StorageCommon) (*tls.Config, error) {
+	if s.TLSCAFile == "" && s.TLSCertFile == "" {
+		return nil, nil
 	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if s.TLSCAFile != "" {
+		// Private CAs are trusted in addition to system CAs, so public endpoints keep working.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		caPEM, err := os.ReadFile(s.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca file %s: %w", s.TLSCAFile, err)
+		}
+
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in tls ca file %s", s.TLSCAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if s.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.TLSCertFile, s.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate %s: %w", s.TLSCertFile, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
 }
 
 // newAuthTransport returns transport with auth.
@@ -321,3 +600,302 @@ func newHTTPClient(transport http.RoundTripper, requestTimeoutSeconds int) *http
 		Timeout:   time.Duration(requestTimeoutSeconds) * time.Millisecond,
 	}
 }
+
+// azureAuthMode returns the configured auth mode, or detects it from the set credentials.
+// Empty result means that no credential is used.
+func azureAuthMode(a *models.AzureBlob) string {
+	switch {
+	case a.AuthMode != "":
+		return a.AuthMode
+	case a.AccountName != "" && a.AccountKey != "":
+		return models.AzureAuthModeSharedKey
+	case a.TenantID != "" && a.ClientID != "" && a.ClientSecret != "":
+		return models.AzureAuthModeClientSecret
+	case a.SASToken != "":
+		return models.AzureAuthModeSAS
+	default:
+		return ""
+	}
+}
+
+// newAzureTokenCredential returns an Azure AD credential for the auth mode.
+// Token requests use the same transport and retry options as the storage requests.
+func newAzureTokenCredential(a *models.AzureBlob, opts azcore.ClientOptions) (azcore.TokenCredential, error) {
+	var (
+		cred azcore.TokenCredential
+		err  error
+	)
+
+	mode := azureAuthMode(a)
+
+	switch mode {
+	case models.AzureAuthModeClientSecret:
+		cred, err = azidentity.NewClientSecretCredential(a.TenantID, a.ClientID, a.ClientSecret,
+			&azidentity.ClientSecretCredentialOptions{ClientOptions: opts})
+	case models.AzureAuthModeManagedIdentity:
+		miOpts := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: opts}
+		if a.ClientID != "" {
+			miOpts.ID = azidentity.ClientID(a.ClientID)
+		}
+
+		cred, err = azidentity.NewManagedIdentityCredential(miOpts)
+	case models.AzureAuthModeWorkloadIdentity:
+		cred, err = azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: opts,
+			ClientID:      a.ClientID,
+			TenantID:      a.TenantID,
+			TokenFilePath: a.FederatedTokenFile,
+		})
+	case models.AzureAuthModeDefault:
+		cred, err = azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions: opts,
+			TenantID:      a.TenantID,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported Azure auth mode %s", mode)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure %s credentials: %w", mode, err)
+	}
+
+	return cred, nil
+}
+
+// azureSASEndpoint appends the SAS token to the endpoint query.
+func azureSASEndpoint(endpoint, token string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse Azure endpoint: %w", err)
+	}
+
+	sas, err := url.ParseQuery(strings.TrimPrefix(token, "?"))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse Azure SAS token: %w", err)
+	}
+
+	query := u.Query()
+	for k, v := range sas {
+		query[k] = v
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// sftpClient is a pool of SFTP clients over separate SSH connections.
+// Files are distributed between connections, so they are transferred in parallel.
+type sftpClient struct {
+	clients []*sftp.Client
+	next    atomic.Uint64
+	// closed is set when any connection of the pool is closed.
+	closed atomic.Bool
+}
+
+// sftpClients caches pools by storage params. Writers and readers of the process share one pool,
+// so checkpoints, state files and rotation segments don't open new SSH connections.
+var sftpClients = struct {
+	sync.Mutex
+	pools map[models.Sftp]*sftpClient
+}{pools: make(map[models.Sftp]*sftpClient)}
+
+// getSftpClient returns the cached pool for the params. A new pool is created if there is none,
+// or if a connection of the cached pool was closed.
+func getSftpClient(ctx context.Context, s *models.Sftp) (*sftpClient, error) {
+	sftpClients.Lock()
+	defer sftpClients.Unlock()
+
+	pool, ok := sftpClients.pools[*s]
+	if ok && !pool.closed.Load() {
+		return pool, nil
+	}
+
+	if ok {
+		// Close the rest of the connections of the broken pool.
+		_ = pool.Close()
+	}
+
+	pool, err := newSftpClient(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	sftpClients.pools[*s] = pool
+
+	return pool, nil
+}
+
+// get returns the next client of the pool.
+func (c *sftpClient) get() *sftp.Client {
+	return c.clients[c.next.Add(1)%uint64(len(c.clients))]
+}
+
+// Close closes all connections of the pool.
+func (c *sftpClient) Close() error {
+	errs := make([]error, 0, len(c.clients))
+	for _, client := range c.clients {
+		errs = append(errs, client.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+func newSftpClient(ctx context.Context, s *models.Sftp) (*sftpClient, error) {
+	sshConfig, closeAgent, err := newSSHClientConfig(s)
+	if err != nil {
+		return nil, err
+	}
+	// The agent is used only during handshakes.
+	defer closeAgent()
+
+	addr := s.Host
+	if _, _, err = net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	pool := &sftpClient{clients: make([]*sftp.Client, 0, s.Connections)}
+
+	for range s.Connections {
+		conn, err := dialSSH(ctx, addr, sshConfig)
+		if err != nil {
+			_ = pool.Close()
+			return nil, fmt.Errorf("failed to connect to SSH server %s: %w", addr, err)
+		}
+
+		client, err := sftp.NewClient(conn, sftp.UseConcurrentWrites(true))
+		if err != nil {
+			_ = conn.Close()
+			_ = pool.Close()
+
+			return nil, fmt.Errorf("failed to start SFTP session on %s: %w", addr, err)
+		}
+
+		pool.clients = append(pool.clients, client)
+
+		go func() {
+			_ = client.Wait()
+			pool.closed.Store(true)
+		}()
+	}
+
+	return pool, nil
+}
+
+// dialSSH establishes an SSH connection, config timeout limits both the dial and the handshake.
+func dialSSH(ctx context.Context, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Timeout > 0 {
+		_ = netConn.SetDeadline(time.Now().Add(config.Timeout))
+	}
+
+	conn, chans, reqs, err := ssh.NewClientConn(netConn, addr, config)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+
+	_ = netConn.SetDeadline(time.Time{})
+
+	return ssh.NewClient(conn, chans, reqs), nil
+}
+
+// newSSHClientConfig returns the client config and a function, that closes the connection to the SSH agent.
+func newSSHClientConfig(s *models.Sftp) (*ssh.ClientConfig, func(), error) {
+	knownHostsFile := s.KnownHostsFile
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get home directory for known_hosts file: %w", err)
+		}
+
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load known hosts file %s: %w", knownHostsFile, err)
+	}
+
+	auth, closeAgent, err := newSSHAuthMethods(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &ssh.ClientConfig{
+		User:            s.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         time.Duration(s.ConnectTimeout) * time.Millisecond,
+	}, closeAgent, nil
+}
+
+// newSSHAuthMethods returns public key authorization with the agent and key file signers, and password authorization.
+// The signers are combined in one method, as the SSH client tries each method type only once.
+// Agent signers sign over the agent connection, so it must be closed with the returned function after handshakes.
+func newSSHAuthMethods(s *models.Sftp) ([]ssh.AuthMethod, func(), error) {
+	signers := make([]ssh.Signer, 0)
+	closeAgent := func() {}
+
+	if s.UseAgent {
+		sock := os.Getenv("SSH_AUTH_SOCK")
+		if sock == "" {
+			return nil, nil, fmt.Errorf("SSH_AUTH_SOCK is not set, SSH agent is not available")
+		}
+
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to SSH agent: %w", err)
+		}
+
+		agentSigners, err := agent.NewClient(conn).Signers()
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, fmt.Errorf("failed to get keys from SSH agent: %w", err)
+		}
+
+		closeAgent = func() { _ = conn.Close() }
+
+		signers = append(signers, agentSigners...)
+	}
+
+	if s.KeyFile != "" {
+		key, err := os.ReadFile(s.KeyFile)
+		if err != nil {
+			closeAgent()
+			return nil, nil, fmt.Errorf("failed to read key file %s: %w", s.KeyFile, err)
+		}
+
+		var signer ssh.Signer
+		if s.KeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(s.KeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(key)
+		}
+
+		if err != nil {
+			closeAgent()
+			return nil, nil, fmt.Errorf("failed to parse key file %s: %w", s.KeyFile, err)
+		}
+
+		signers = append(signers, signer)
+	}
+
+	methods := make([]ssh.AuthMethod, 0, 2)
+
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	if s.Password != "" {
+		methods = append(methods, ssh.Password(s.Password))
+	}
+
+	return methods, closeAgent, nil
+}
@@ -16,14 +16,36 @@ package storage
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
 	appConfig "github.com/aerospike/aerospike-backup-cli/internal/config"
 	"github.com/aerospike/aerospike-backup-cli/internal/models"
 	"github.com/aerospike/aerospike-client-go/v8"
 	"github.com/aerospike/tools-common-go/client"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -110,6 +132,72 @@ func TestClients_newS3Client(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClients_newS3RoleProvider(t *testing.T) {
+	t.Parallel()
+
+	cfg := aws.Config{Region: testS3Region}
+
+	provider := newS3RoleProvider(cfg, &models.AwsS3{
+		RoleArn:     "arn:aws:iam::123456789012:role/backup",
+		ExternalID:  "external",
+		SessionName: "backup",
+	})
+	require.IsType(t, &stscreds.AssumeRoleProvider{}, provider)
+
+	provider = newS3RoleProvider(cfg, &models.AwsS3{
+		RoleArn:              "arn:aws:iam::123456789012:role/backup",
+		WebIdentityTokenFile: "/var/run/secrets/token",
+	})
+	require.IsType(t, &stscreds.WebIdentityRoleProvider{}, provider)
+}
+
+func TestClients_newS3SSE(t *testing.T) {
+	t.Parallel()
+
+	sse, err := newS3SSE(&models.AwsS3{})
+	require.NoError(t, err)
+	require.Nil(t, sse)
+
+	// Captures the input, that is passed to the next handler.
+	var params any
+
+	next := middleware.InitializeHandlerFunc(func(_ context.Context, in middleware.InitializeInput,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		params = in.Parameters
+		return middleware.InitializeOutput{}, middleware.Metadata{}, nil
+	})
+
+	sse, err = newS3SSE(&models.AwsS3{SSE: models.S3SSEKMS, SSEKmsKeyID: "alias/backup"})
+	require.NoError(t, err)
+
+	input := &s3.CreateMultipartUploadInput{Key: aws.String("file.asb")}
+	_, _, err = sse.handle(context.Background(), middleware.InitializeInput{Parameters: input}, next)
+	require.NoError(t, err)
+	require.IsType(t, &s3.CreateMultipartUploadInput{}, params)
+
+	upload := params.(*s3.CreateMultipartUploadInput)
+	require.Equal(t, types.ServerSideEncryptionAwsKms, upload.ServerSideEncryption)
+	require.Equal(t, "alias/backup", aws.ToString(upload.SSEKMSKeyId))
+	require.Nil(t, upload.SSECustomerKey)
+	// The input of the caller is not modified.
+	require.Empty(t, input.ServerSideEncryption)
+
+	sse, err = newS3SSE(&models.AwsS3{
+		SSE:            models.S3SSECustomer,
+		SSECustomerKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+	})
+	require.NoError(t, err)
+
+	_, _, err = sse.handle(context.Background(), middleware.InitializeInput{Parameters: &s3.GetObjectInput{}}, next)
+	require.NoError(t, err)
+	require.IsType(t, &s3.GetObjectInput{}, params)
+
+	get := params.(*s3.GetObjectInput)
+	require.Equal(t, "AES256", aws.ToString(get.SSECustomerAlgorithm))
+	require.Equal(t, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=", aws.ToString(get.SSECustomerKey))
+	require.Equal(t, "hRasmdxgYDKV3nvbahU1MA==", aws.ToString(get.SSECustomerKeyMD5))
+}
+
 func TestClients_newGcpClient(t *testing.T) {
 	t.Parallel()
 
@@ -122,6 +210,122 @@ func TestClients_newGcpClient(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClients_newGcpClientExternalAccount(t *testing.T) {
+	t.Parallel()
+
+	var authHeaders []string
+
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			writeTestGcpToken(w)
+			return
+		}
+
+		mu.Lock()
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"` + testBucket + `"}`))
+	}))
+	defer server.Close()
+
+	cfg := &models.GcpStorage{
+		KeyFile:  writeTestGcpExternalAccount(t, server.URL+"/token"),
+		Endpoint: server.URL + "/storage/v1/",
+	}
+
+	ctx := context.Background()
+	gcpClient, err := newGcpClient(ctx, cfg)
+	require.NoError(t, err)
+
+	_, err = gcpClient.Bucket(testBucket).Attrs(ctx)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.NotEmpty(t, authHeaders)
+	assert.Equal(t, "Bearer "+testGcpAccessToken, authHeaders[0])
+}
+
+//nolint:paralleltest // Test sets environment variable.
+func TestClients_newGcpTokenSourceADC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeTestGcpToken(w)
+	}))
+	defer server.Close()
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", writeTestGcpExternalAccount(t, server.URL+"/token"))
+
+	tokenSource, err := newGcpTokenSource(context.Background(), &models.GcpStorage{}, http.DefaultTransport)
+	require.NoError(t, err)
+	require.NotNil(t, tokenSource)
+
+	token, err := tokenSource.Token()
+	require.NoError(t, err)
+	assert.Equal(t, testGcpAccessToken, token.AccessToken)
+}
+
+func TestClients_newGcpTokenSourceImpersonate(t *testing.T) {
+	t.Parallel()
+
+	var connectHosts []string
+
+	var mu sync.Mutex
+
+	// All requests go through the proxy, so both the token exchange and impersonation are recorded.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			mu.Lock()
+			connectHosts = append(connectHosts, r.Host)
+			mu.Unlock()
+
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		writeTestGcpToken(w)
+	}))
+	defer proxy.Close()
+
+	cfg := &models.GcpStorage{
+		KeyFile:                   writeTestGcpExternalAccount(t, "http://sts.test/token"),
+		ImpersonateServiceAccount: "backup@project.iam.gserviceaccount.com",
+		Endpoint:                  testGcpEndpoint,
+		StorageCommon: models.StorageCommon{
+			ProxyURL: proxy.URL,
+		},
+	}
+
+	transport, err := newTransport(&cfg.StorageCommon)
+	require.NoError(t, err)
+
+	tokenSource, err := newGcpTokenSource(context.Background(), cfg, transport)
+	require.NoError(t, err)
+
+	_, err = tokenSource.Token()
+	require.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{"iamcredentials.googleapis.com:443"}, connectHosts)
+}
+
+func TestClients_newGcpTokenSourceEndpointOverride(t *testing.T) {
+	t.Parallel()
+
+	tokenSource, err := newGcpTokenSource(context.Background(), &models.GcpStorage{
+		Endpoint: testGcpEndpoint,
+	}, http.DefaultTransport)
+	require.NoError(t, err)
+	assert.Nil(t, tokenSource)
+}
+
 func TestClients_newAzureClient(t *testing.T) {
 	t.Parallel()
 
@@ -136,6 +340,274 @@ func TestClients_newAzureClient(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClients_newAzureClientSAS(t *testing.T) {
+	t.Parallel()
+
+	err := createAzureContainer()
+	require.NoError(t, err)
+
+	cred, err := azblob.NewSharedKeyCredential(testAzureAccountName, testAzureAccountKey)
+	require.NoError(t, err)
+
+	sasParams, err := sas.AccountSignatureValues{
+		Protocol:      sas.ProtocolHTTPSandHTTP,
+		ExpiryTime:    time.Now().Add(time.Hour).UTC(),
+		Permissions:   to.Ptr(sas.AccountPermissions{Read: true, Write: true, Create: true, Delete: true}).String(),
+		ResourceTypes: to.Ptr(sas.AccountResourceTypes{Container: true, Object: true}).String(),
+	}.SignWithSharedKey(cred)
+	require.NoError(t, err)
+
+	cfg := &models.AzureBlob{
+		SASToken:      sasParams.Encode(),
+		AuthMode:      models.AzureAuthModeSAS,
+		Endpoint:      testAzureEndpoint,
+		ContainerName: testBucket,
+	}
+
+	azClient, err := newAzureClient(cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	blobName := "sas-" + t.Name()
+
+	_, err = azClient.UploadBuffer(ctx, testBucket, blobName, []byte("data"), nil)
+	require.NoError(t, err)
+
+	_, err = azClient.DeleteBlob(ctx, testBucket, blobName, nil)
+	require.NoError(t, err)
+}
+
+func TestClients_newAzureClientAuthModes(t *testing.T) {
+	t.Parallel()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token"), 0o600))
+
+	tests := []struct {
+		name string
+		cfg  *models.AzureBlob
+	}{
+		{
+			name: "shared key",
+			cfg: &models.AzureBlob{
+				AccountName: testAzureAccountName,
+				AccountKey:  testAzureAccountKey,
+				AuthMode:    models.AzureAuthModeSharedKey,
+			},
+		},
+		{
+			name: "client secret",
+			cfg: &models.AzureBlob{
+				TenantID:     "tenant",
+				ClientID:     "client",
+				ClientSecret: "secret",
+				AuthMode:     models.AzureAuthModeClientSecret,
+			},
+		},
+		{
+			name: "managed identity",
+			cfg: &models.AzureBlob{
+				AuthMode: models.AzureAuthModeManagedIdentity,
+			},
+		},
+		{
+			name: "user-assigned managed identity",
+			cfg: &models.AzureBlob{
+				ClientID: "client",
+				AuthMode: models.AzureAuthModeManagedIdentity,
+			},
+		},
+		{
+			name: "workload identity",
+			cfg: &models.AzureBlob{
+				TenantID:           "tenant",
+				ClientID:           "client",
+				FederatedTokenFile: tokenFile,
+				AuthMode:           models.AzureAuthModeWorkloadIdentity,
+			},
+		},
+		{
+			name: "default",
+			cfg: &models.AzureBlob{
+				AuthMode: models.AzureAuthModeDefault,
+			},
+		},
+		{
+			name: "sas",
+			cfg: &models.AzureBlob{
+				SASToken: "sv=2024-01-01&sig=abc",
+				AuthMode: models.AzureAuthModeSAS,
+			},
+		},
+		{
+			name: "no credential",
+			cfg:  &models.AzureBlob{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.cfg.Endpoint = testAzureEndpoint
+			tt.cfg.ContainerName = testBucket
+
+			azClient, err := newAzureClient(tt.cfg)
+			require.NoError(t, err)
+			require.NotNil(t, azClient)
+		})
+	}
+}
+
+func TestClients_azureAuthMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *models.AzureBlob
+		want string
+	}{
+		{
+			name: "explicit mode",
+			cfg: &models.AzureBlob{
+				AccountName: testAzureAccountName,
+				AccountKey:  testAzureAccountKey,
+				AuthMode:    models.AzureAuthModeManagedIdentity,
+			},
+			want: models.AzureAuthModeManagedIdentity,
+		},
+		{
+			name: "shared key",
+			cfg: &models.AzureBlob{
+				AccountName: testAzureAccountName,
+				AccountKey:  testAzureAccountKey,
+			},
+			want: models.AzureAuthModeSharedKey,
+		},
+		{
+			name: "client secret",
+			cfg: &models.AzureBlob{
+				TenantID:     "tenant",
+				ClientID:     "client",
+				ClientSecret: "secret",
+			},
+			want: models.AzureAuthModeClientSecret,
+		},
+		{
+			name: "sas",
+			cfg: &models.AzureBlob{
+				SASToken: "sv=2024-01-01&sig=abc",
+			},
+			want: models.AzureAuthModeSAS,
+		},
+		{
+			name: "no credential",
+			cfg:  &models.AzureBlob{ClientID: "client"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, azureAuthMode(tt.cfg))
+		})
+	}
+}
+
+func TestClients_azureSASEndpoint(t *testing.T) {
+	t.Parallel()
+
+	endpoint, err := azureSASEndpoint(testAzureEndpoint, "?sv=2024-01-01&sig=a%2Bb")
+	require.NoError(t, err)
+	assert.Equal(t, testAzureEndpoint+"?sig=a%2Bb&sv=2024-01-01", endpoint)
+
+	endpoint, err = azureSASEndpoint("https://account.blob.core.windows.net/?comp=list", "sig=abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://account.blob.core.windows.net/?comp=list&sig=abc", endpoint)
+
+	_, err = azureSASEndpoint(testAzureEndpoint, "sig=%zz")
+	require.Error(t, err)
+}
+
+func TestClients_newTransport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	// The server certificate is trusted only with the CA file.
+	transport, err := newTransport(&models.StorageCommon{})
+	require.NoError(t, err)
+	require.Nil(t, transport.TLSClientConfig)
+
+	_, err = newHTTPClient(transport, 0).Get(server.URL)
+	require.Error(t, err)
+
+	certFile, keyFile := writeTestClientCert(t, dir)
+
+	transport, err = newTransport(&models.StorageCommon{
+		TLSCAFile:   caFile,
+		TLSCertFile: certFile,
+		TLSKeyFile:  keyFile,
+		ProxyURL:    "http://proxy:3128",
+	})
+	require.NoError(t, err)
+	require.Len(t, transport.TLSClientConfig.Certificates, 1)
+
+	proxy, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "s3.amazonaws.com"}})
+	require.NoError(t, err)
+	require.Equal(t, "http://proxy:3128", proxy.String())
+
+	transport.Proxy = nil
+	resp, err := newHTTPClient(transport, 0).Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	_, err = newTransport(&models.StorageCommon{TLSCAFile: filepath.Join(dir, "missing.pem")})
+	require.ErrorContains(t, err, "failed to read tls ca file")
+
+	_, err = newTransport(&models.StorageCommon{TLSCAFile: keyFile})
+	require.ErrorContains(t, err, "no certificates found in tls ca file")
+}
+
+// writeTestClientCert writes a self-signed client certificate and its key to dir.
+func writeTestClientCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "backup"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
 func TestToHosts(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -239,3 +711,34 @@ func TestParseRacks(t *testing.T) {
 		})
 	}
 }
+
+const testGcpAccessToken = "test-access-token"
+
+func writeTestGcpToken(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"access_token":"` + testGcpAccessToken + `",` +
+		`"issued_token_type":"urn:ietf:params:oauth:token-type:access_token",` +
+		`"token_type":"Bearer","expires_in":3600}`))
+}
+
+// writeTestGcpExternalAccount writes external account config with a file subject token source.
+func writeTestGcpExternalAccount(t *testing.T, tokenURL string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	subjectTokenFile := filepath.Join(dir, "subject-token")
+	require.NoError(t, os.WriteFile(subjectTokenFile, []byte("subject-token"), 0o600))
+
+	config := fmt.Sprintf(`{
+  "type": "external_account",
+  "audience": "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider",
+  "subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+  "token_url": %q,
+  "credential_source": {"file": %q}
+}`, tokenURL, subjectTokenFile)
+
+	configFile := filepath.Join(dir, "external-account.json")
+	require.NoError(t, os.WriteFile(configFile, []byte(config), 0o600))
+
+	return configFile
+}
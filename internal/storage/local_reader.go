@@ -0,0 +1,256 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// localReader wraps the local storage reader to tune disk reads on restore.
+// Each file is read with a buffer of the configured size, optionally in the background
+// and ahead of the consumer, so restores from fast disks are not bottlenecked by small reads.
+type localReader struct {
+	backup.StreamingReader
+
+	bufferSize      int
+	readAhead       int
+	dropCache       bool
+	openConcurrency int
+}
+
+func newLocalReaderWrapper(reader backup.StreamingReader, l *models.Local) backup.StreamingReader {
+	return &localReader{
+		StreamingReader: reader,
+		bufferSize:      l.BufferSize * 1024 * 1024,
+		readAhead:       l.ReadAhead,
+		dropCache:       l.DropCache,
+		openConcurrency: l.OpenConcurrency,
+	}
+}
+
+// StreamFiles streams files from the wrapped reader, replacing each file reader with a tuned one.
+// Up to openConcurrency files are opened and prefetched before the consumer requests them.
+func (r *localReader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	filesCh := make(chan bModels.File)
+
+	go r.StreamingReader.StreamFiles(ctx, filesCh, errorsCh, skipPrefixes)
+
+	r.forward(ctx, filesCh, readersCh)
+}
+
+// StreamFile streams a single file from the wrapped reader, replacing the file reader with a tuned one.
+func (r *localReader) StreamFile(
+	ctx context.Context, filename string, readersCh chan<- bModels.File, errorsCh chan<- error,
+) {
+	filesCh := make(chan bModels.File, 1)
+
+	r.StreamingReader.StreamFile(ctx, filename, filesCh, errorsCh)
+	close(filesCh)
+
+	for file := range filesCh {
+		readersCh <- r.wrapFile(file)
+	}
+}
+
+// forward wraps files from filesCh and sends them to readersCh, then closes readersCh.
+func (r *localReader) forward(ctx context.Context, filesCh <-chan bModels.File, readersCh chan<- bModels.File) {
+	defer close(readersCh)
+
+	queue := make(chan bModels.File, r.openConcurrency)
+
+	go func() {
+		defer close(queue)
+
+		for file := range filesCh {
+			queue <- r.wrapFile(file)
+		}
+	}()
+
+	for file := range queue {
+		select {
+		case <-ctx.Done():
+			_ = file.Reader.Close()
+		case readersCh <- file:
+		}
+	}
+}
+
+func (r *localReader) wrapFile(file bModels.File) bModels.File {
+	var source io.ReadCloser = file.Reader
+
+	if r.dropCache {
+		if f, ok := file.Reader.(*os.File); ok {
+			adviseSequential(f)
+
+			source = &cacheDroppingReader{file: f}
+		}
+	}
+
+	if r.readAhead > 0 {
+		file.Reader = newReadAheadReader(source, r.bufferSize, r.readAhead)
+		return file
+	}
+
+	file.Reader = &bufferedReadCloser{
+		Reader: bufio.NewReaderSize(source, r.bufferSize),
+		closer: source,
+	}
+
+	return file
+}
+
+// bufferedReadCloser reads through a buffer and closes the underlying file.
+type bufferedReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (b *bufferedReadCloser) Close() error {
+	return b.closer.Close()
+}
+
+// cacheDroppingReader drops each read range of the file from the page cache.
+type cacheDroppingReader struct {
+	file   *os.File
+	offset int64
+}
+
+func (c *cacheDroppingReader) Read(p []byte) (int, error) {
+	n, err := c.file.Read(p)
+	if n > 0 {
+		dropFromCache(c.file, c.offset, int64(n))
+		c.offset += int64(n)
+	}
+
+	return n, err
+}
+
+func (c *cacheDroppingReader) Close() error {
+	return c.file.Close()
+}
+
+// readAheadReader reads a file in chunks of bufferSize in a background goroutine,
+// keeping up to readAhead chunks ready for the consumer.
+type readAheadReader struct {
+	file io.ReadCloser
+
+	chunks chan []byte
+	free   chan []byte
+	// err is set before chunks is closed.
+	err     error
+	current []byte
+	chunk   []byte
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func newReadAheadReader(file io.ReadCloser, bufferSize, readAhead int) *readAheadReader {
+	r := &readAheadReader{
+		file:    file,
+		chunks:  make(chan []byte, readAhead),
+		free:    make(chan []byte, readAhead+1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go r.run(bufferSize)
+
+	return r
+}
+
+func (r *readAheadReader) run(bufferSize int) {
+	defer close(r.stopped)
+	defer close(r.chunks)
+
+	for {
+		var buf []byte
+
+		select {
+		case buf = <-r.free:
+		default:
+			buf = make([]byte, bufferSize)
+		}
+
+		n, err := io.ReadFull(r.file, buf)
+		if n > 0 {
+			select {
+			case r.chunks <- buf[:n]:
+			case <-r.done:
+				return
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				err = io.EOF
+			}
+
+			r.err = err
+
+			return
+		}
+	}
+}
+
+func (r *readAheadReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if r.chunk != nil {
+			// Return the consumed chunk for reuse.
+			select {
+			case r.free <- r.chunk[:cap(r.chunk)]:
+			default:
+			}
+
+			r.chunk = nil
+		}
+
+		chunk, ok := <-r.chunks
+		if !ok {
+			return 0, r.err
+		}
+
+		r.chunk = chunk
+		r.current = chunk
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+
+	return n, nil
+}
+
+func (r *readAheadReader) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+
+	// Wait for the background read to finish before closing the file.
+	<-r.stopped
+
+	return r.file.Close()
+}
@@ -0,0 +1,25 @@
+//go:build !linux
+
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import "os"
+
+// adviseSequential is a no-op on platforms without posix_fadvise.
+func adviseSequential(_ *os.File) {}
+
+// dropFromCache is a no-op on platforms without posix_fadvise.
+func dropFromCache(_ *os.File, _, _ int64) {}
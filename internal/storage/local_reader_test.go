@@ -0,0 +1,155 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalReader_StreamFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// Content is larger than a 1 MiB buffer, so it is read in several chunks.
+	content := bytes.Repeat([]byte("0123456789abcdef"), 200000)
+	files := []string{"0_test_1.asb", "0_test_2.asb", "0_test_3.asb"}
+
+	for _, name := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o600))
+	}
+
+	tests := []struct {
+		name  string
+		local *models.Local
+	}{
+		{
+			name:  "buffered",
+			local: &models.Local{BufferSize: 1},
+		},
+		{
+			name:  "read ahead",
+			local: &models.Local{BufferSize: 1, ReadAhead: 2, OpenConcurrency: 2},
+		},
+		{
+			name:  "drop cache",
+			local: &models.Local{BufferSize: 1, ReadAhead: 1, DropCache: true},
+		},
+		{
+			name:  "drop cache without read ahead",
+			local: &models.Local{BufferSize: 1, DropCache: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+			reader, err := newLocalReader(ctx, tt.local, []options.Opt{options.WithDir(dir), options.WithSkipDirCheck()}, logger)
+			require.NoError(t, err)
+
+			readersCh := make(chan bModels.File)
+			errorsCh := make(chan error, 1)
+
+			go reader.StreamFiles(ctx, readersCh, errorsCh, nil)
+
+			var count int
+
+			for file := range readersCh {
+				data, err := io.ReadAll(file.Reader)
+				require.NoError(t, err)
+				require.Equal(t, content, data, file.Name)
+				require.NoError(t, file.Reader.Close())
+
+				count++
+			}
+
+			require.Empty(t, errorsCh)
+			require.Equal(t, len(files), count)
+		})
+	}
+}
+
+func TestLocalReader_CloseBeforeEOF(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "0_test_1.asb")
+	require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte("a"), 4*1024*1024), 0o600))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	reader := newReadAheadReader(f, 1024*1024, 1)
+
+	buf := make([]byte, 10)
+	_, err = reader.Read(buf)
+	require.NoError(t, err)
+
+	// Background reading must stop without consuming the whole file.
+	require.NoError(t, reader.Close())
+}
+
+func TestLocalReader_DropCacheWithoutReadAhead(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "0_test_1.asb")
+	content := bytes.Repeat([]byte("a"), 3*1024*1024+10)
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	r := &localReader{bufferSize: 1024 * 1024, dropCache: true}
+	file := r.wrapFile(bModels.File{Name: "0_test_1.asb", Reader: f})
+
+	buffered, ok := file.Reader.(*bufferedReadCloser)
+	require.True(t, ok)
+
+	// Every read range must pass through the reader that drops it from the page cache.
+	dropping, ok := buffered.closer.(*cacheDroppingReader)
+	require.True(t, ok)
+
+	data, err := io.ReadAll(file.Reader)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+	require.Equal(t, int64(len(content)), dropping.offset)
+	require.NoError(t, file.Reader.Close())
+}
+
+func TestLocalReader_GetType(t *testing.T) {
+	t.Parallel()
+
+	inner, err := local.NewReader(context.Background(), options.WithDir(t.TempDir()), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	reader := newLocalReaderWrapper(inner, &models.Local{BufferSize: 1})
+	require.Equal(t, testLocalType, reader.GetType())
+}
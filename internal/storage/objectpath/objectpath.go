@@ -0,0 +1,28 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package objectpath contains helpers for object paths returned by storage readers.
+// It has no dependencies, so it can be used by any package that lists storage objects.
+package objectpath
+
+import (
+	"path"
+	"path/filepath"
+)
+
+// BaseName returns the last element of the object path.
+// Local storage returns os specific paths, cloud storages return keys separated by slashes.
+func BaseName(object string) string {
+	return path.Base(filepath.ToSlash(object))
+}
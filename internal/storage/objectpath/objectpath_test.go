@@ -0,0 +1,29 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "file.asb", BaseName("dir/sub/file.asb"), "Key base name should be returned")
+	assert.Equal(t, "file.asb", BaseName("/dir/file.asb"), "Absolute path base name should be returned")
+	assert.Equal(t, "file.asb", BaseName("file.asb"), "Name without directory should be returned as is")
+}
@@ -0,0 +1,352 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/storage/common"
+	"github.com/aerospike/backup-go/io/storage/options"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// preflightProbePrefix is the name prefix of the object written by the storage preflight check.
+const preflightProbePrefix = ".abs-preflight-"
+
+var preflightProbeContent = []byte("aerospike-backup-cli storage preflight probe\n")
+
+// PreflightError contains all issues found by the storage preflight check.
+type PreflightError struct {
+	Issues []error
+}
+
+func (e *PreflightError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i := range e.Issues {
+		msgs[i] = e.Issues[i].Error()
+	}
+
+	return fmt.Sprintf("storage preflight found %d issue(s): %s", len(e.Issues), strings.Join(msgs, "; "))
+}
+
+func (e *PreflightError) Unwrap() []error {
+	return e.Issues
+}
+
+// Estimator estimates the backup size, it is implemented by backup.Client.
+type Estimator interface {
+	Estimate(ctx context.Context, config *backup.ConfigBackup, estimateSamples int64) (uint64, error)
+}
+
+// projectSizeFunc returns the projected size of the backup in bytes.
+// If upperBound is true, records are also filtered in a way that can't be projected, so the backup can be smaller.
+type projectSizeFunc func(ctx context.Context) (size uint64, upperBound bool, err error)
+
+// ShouldPreflight returns true if the storage must be checked before the backup.
+// Xdr backup, estimate, stdout and artifacts removal are not checked.
+func ShouldPreflight(params *config.BackupServiceConfig) bool {
+	return params.Backup != nil &&
+		!params.Backup.SkipPreflight &&
+		!params.Backup.RemoveArtifacts &&
+		!params.Backup.Estimate &&
+		!params.IsStdout()
+}
+
+// Preflight verifies that the backup storage is usable before the backup writer is created.
+// The size of a local backup is projected with the estimator, so the connected backup client is reused.
+func Preflight(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	estimator Estimator,
+	logger *slog.Logger,
+) error {
+	return preflight(ctx, params, sa, newSizeProjector(params, estimator), logger)
+}
+
+// preflight verifies that the backup storage is usable before the backup is started.
+// It writes, reads, lists and deletes a probe object, for local storage it also compares
+// free disk space with the projected backup size. All found issues are returned as PreflightError.
+func preflight(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	projectSize projectSizeFunc,
+	logger *slog.Logger,
+) error {
+	directory := params.Backup.Directory
+	if params.Backup.OutputFile != "" {
+		directory = path.Dir(params.Backup.OutputFile)
+	}
+
+	isCloud := isCloudStorage(params)
+	probeName := preflightProbePrefix + strconv.FormatInt(time.Now().UnixNano(), 10)
+	probePath := path.Join(common.CleanPath(directory, isCloud), probeName)
+
+	logger.Info("running storage preflight",
+		slog.String("directory", directory),
+		slog.String("probe", probePath),
+	)
+
+	// The probe is written in file mode, so the writer removes only the probe.
+	writerOpts := []options.Opt{
+		options.WithFile(probePath),
+		options.WithSkipDirCheck(),
+		options.WithLogger(logger),
+	}
+
+	writer, err := newStorageWriter(ctx, params, sa, writerOpts, nil, logger)
+	if err != nil {
+		// Nothing else can be checked without credentials.
+		return &PreflightError{Issues: []error{fmt.Errorf("failed to initialize storage: %w", err)}}
+	}
+
+	var issues []error
+
+	written := true
+	if err = writeProbe(ctx, writer); err != nil {
+		issues = append(issues, fmt.Errorf("failed to write probe object %s: %w", probePath, err))
+		written = false
+	}
+
+	readerOpts := []options.Opt{
+		options.WithDir(directory),
+		options.WithSkipDirCheck(),
+		options.WithLogger(logger),
+	}
+
+	readerParams := &config.RestoreServiceConfig{
+		AwsS3:      params.AwsS3,
+		GcpStorage: params.GcpStorage,
+		AzureBlob:  params.AzureBlob,
+		Sftp:       params.Sftp,
+		Local:      params.Local,
+	}
+
+	reader, err := newStorageReader(ctx, readerParams, sa, readerOpts, logger)
+	if err != nil {
+		issues = append(issues, fmt.Errorf("failed to initialize storage reader: %w", err))
+	}
+
+	if reader != nil {
+		if written {
+			if err = readProbe(ctx, reader, probePath); err != nil {
+				issues = append(issues, fmt.Errorf("failed to read probe object %s: %w", probePath, err))
+			}
+		}
+
+		objects, err := reader.ListObjects(ctx, common.CleanPath(directory, isCloud))
+		switch {
+		case err != nil:
+			issues = append(issues, fmt.Errorf("failed to list backup directory %s: %w", directory, err))
+		case shouldBeEmpty(params) && hasObjects(objects, probeName):
+			issues = append(issues, fmt.Errorf("backup directory %s is not empty, use --remove-files to clean it",
+				directory))
+		}
+	}
+
+	if written {
+		if err = writer.Remove(ctx, probePath); err != nil {
+			issues = append(issues, fmt.Errorf("failed to delete probe object %s: %w", probePath, err))
+		}
+	}
+
+	if !isCloud && (params.Sftp == nil || params.Sftp.Host == "") {
+		if err = checkFreeSpace(ctx, directory, projectSize, logger); err != nil {
+			issues = append(issues, err)
+		}
+	}
+
+	if len(issues) > 0 {
+		return &PreflightError{Issues: issues}
+	}
+
+	return nil
+}
+
+func writeProbe(ctx context.Context, writer backup.Writer) error {
+	w, err := writer.NewWriter(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(preflightProbeContent); err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	// Cloud uploads are completed on close.
+	return w.Close()
+}
+
+func readProbe(ctx context.Context, reader backup.StreamingReader, probePath string) error {
+	// StreamFile sends the file or an error before it returns.
+	readersCh := make(chan bModels.File, 1)
+	errorsCh := make(chan error, 1)
+
+	reader.StreamFile(ctx, probePath, readersCh, errorsCh)
+
+	select {
+	case err := <-errorsCh:
+		return err
+	case file := <-readersCh:
+		defer file.Reader.Close()
+
+		data, err := io.ReadAll(file.Reader)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(data, preflightProbeContent) {
+			return errors.New("probe object content doesn't match")
+		}
+
+		return nil
+	default:
+		return errors.New("probe object is not found")
+	}
+}
+
+// shouldBeEmpty returns true if the writer requires an empty backup directory.
+func shouldBeEmpty(params *config.BackupServiceConfig) bool {
+	return params.Backup.OutputFile == "" &&
+		!params.Backup.ShouldClearTarget() &&
+		params.Backup.Continue == "" &&
+		!params.IsShard()
+}
+
+// hasObjects returns true if objects contain anything except the probe.
+func hasObjects(objects []string, probeName string) bool {
+	for _, o := range objects {
+		if path.Base(o) != probeName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isCloudStorage(params *config.BackupServiceConfig) bool {
+	return params.AwsS3 != nil && params.AwsS3.BucketName != "" ||
+		params.GcpStorage != nil && params.GcpStorage.BucketName != "" ||
+		params.AzureBlob != nil && params.AzureBlob.ContainerName != ""
+}
+
+// checkFreeSpace compares free disk space of the directory with the projected backup size.
+// The check is skipped if the size can't be projected, an upper bound that exceeds free space is only logged.
+func checkFreeSpace(ctx context.Context, directory string, projectSize projectSizeFunc, logger *slog.Logger) error {
+	if projectSize == nil {
+		return nil
+	}
+
+	free, err := freeDiskSpace(directory)
+	switch {
+	case errors.Is(err, errors.ErrUnsupported):
+		logger.Warn("free disk space check is not supported on this platform")
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get free disk space of %s: %w", directory, err)
+	}
+
+	projected, upperBound, err := projectSize(ctx)
+	if err != nil {
+		logger.Warn("skipping free disk space check, failed to project backup size", slog.Any("error", err))
+		return nil
+	}
+
+	logger.Info("checked free disk space",
+		slog.Uint64("free_bytes", free),
+		slog.Uint64("projected_bytes", projected),
+		slog.Bool("upper_bound", upperBound),
+	)
+
+	switch {
+	case projected <= free:
+		return nil
+	case upperBound:
+		logger.Warn("projected backup size exceeds free disk space, records filters are not projected, "+
+			"so the backup can be smaller",
+			slog.String("directory", directory),
+			slog.Uint64("free_bytes", free),
+			slog.Uint64("projected_bytes", projected),
+		)
+
+		return nil
+	default:
+		return fmt.Errorf("not enough free disk space in %s: %d bytes free, %d bytes projected",
+			directory, free, projected)
+	}
+}
+
+// newSizeProjector returns a function that projects the backup size with the backup-go estimate.
+// The estimate covers the namespace and sets with compression, it is scaled by the share of backed up partitions.
+func newSizeProjector(params *config.BackupServiceConfig, estimator Estimator) projectSizeFunc {
+	return func(ctx context.Context) (uint64, bool, error) {
+		// The config is already logged by the backup service.
+		backupConfig, _, err := config.NewBackupConfigs(params, slog.New(slog.DiscardHandler))
+		if err != nil {
+			return 0, false, err
+		}
+
+		// The estimate doesn't use the state.
+		backupConfig.StateFile = ""
+		backupConfig.Continue = false
+		backupConfig.PageSize = 0
+
+		estimate, err := estimator.Estimate(ctx, backupConfig, params.Backup.EstimateSamples)
+		if err != nil {
+			return 0, false, err
+		}
+
+		return uint64(float64(estimate) * partitionShare(backupConfig.PartitionFilters)), isUpperBound(backupConfig), nil
+	}
+}
+
+// partitionShare returns the share of partitions covered by the filters.
+// The estimate counts records of all partitions, so it is scaled by this share.
+func partitionShare(filters []*aerospike.PartitionFilter) float64 {
+	if len(filters) == 0 {
+		return 1
+	}
+
+	var count int
+	for _, f := range filters {
+		count += f.Count
+	}
+
+	return min(float64(count)/float64(backup.MaxPartitions), 1)
+}
+
+// isUpperBound returns true if records are filtered in a way that the estimate doesn't account for.
+func isUpperBound(c *backup.ConfigBackup) bool {
+	return c.ModBefore != nil ||
+		c.ModAfter != nil ||
+		c.NoTTLOnly ||
+		len(c.NodeList) > 0 ||
+		len(c.RackList) > 0 ||
+		c.ScanPolicy != nil && c.ScanPolicy.FilterExpression != nil
+}
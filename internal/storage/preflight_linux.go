@@ -0,0 +1,44 @@
+//go:build linux
+
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// freeDiskSpace returns the number of bytes available to the user on the file system of the path.
+// If the path doesn't exist yet, the nearest existing parent is checked.
+func freeDiskSpace(path string) (uint64, error) {
+	path = filepath.Clean(path)
+
+	for {
+		var stat unix.Statfs_t
+
+		err := unix.Statfs(path, &stat)
+		switch {
+		case err == nil:
+			return stat.Bavail * uint64(stat.Bsize), nil
+		case (errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ENOTDIR)) && filepath.Dir(path) != path:
+			path = filepath.Dir(path)
+		default:
+			return 0, err
+		}
+	}
+}
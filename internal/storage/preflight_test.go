@@ -0,0 +1,280 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProjectSize(size uint64, upperBound bool) projectSizeFunc {
+	return func(context.Context) (uint64, bool, error) {
+		return size, upperBound, nil
+	}
+}
+
+func newTestPreflightParams(directory string) *config.BackupServiceConfig {
+	return &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: directory,
+			},
+		},
+		Local: &models.Local{},
+	}
+}
+
+func TestPreflight_Local(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	params := newTestPreflightParams(dir)
+
+	err := preflight(context.Background(), params, nil, testProjectSize(1, false), slog.Default())
+	require.NoError(t, err)
+
+	// The probe must be removed.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestPreflight_LocalOutputFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	params := newTestPreflightParams("")
+	params.Backup.OutputFile = filepath.Join(dir, "backup.asb")
+
+	// Other files don't matter when backup is written to a file.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.asb"), []byte("data"), 0o600))
+
+	err := preflight(context.Background(), params, nil, testProjectSize(1, false), slog.Default())
+	require.NoError(t, err)
+}
+
+func TestPreflight_LocalNotEmpty(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0_test.asb"), []byte("data"), 0o600))
+
+	params := newTestPreflightParams(dir)
+
+	err := preflight(context.Background(), params, nil, testProjectSize(1, false), slog.Default())
+	require.ErrorContains(t, err, "is not empty")
+
+	// Directory is cleaned by the writer.
+	params.Backup.RemoveFiles = true
+	err = preflight(context.Background(), params, nil, testProjectSize(1, false), slog.Default())
+	require.NoError(t, err)
+}
+
+func TestPreflight_LocalNoSpace(t *testing.T) {
+	t.Parallel()
+
+	params := newTestPreflightParams(t.TempDir())
+
+	err := preflight(context.Background(), params, nil, testProjectSize(1<<62, false), slog.Default())
+	require.ErrorContains(t, err, "not enough free disk space")
+
+	// The backup can be smaller than an upper bound.
+	err = preflight(context.Background(), params, nil, testProjectSize(1<<62, true), slog.Default())
+	require.NoError(t, err)
+
+	// Backup is not stopped if the size can't be projected.
+	failedProjection := func(context.Context) (uint64, bool, error) {
+		return 0, false, errors.New("cluster is not available")
+	}
+
+	err = preflight(context.Background(), params, nil, failedProjection, slog.Default())
+	require.NoError(t, err)
+}
+
+func TestPreflight_LocalAllIssues(t *testing.T) {
+	t.Parallel()
+
+	// A regular file in place of the parent directory makes every storage call fail.
+	parent := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(parent, []byte("data"), 0o600))
+
+	params := newTestPreflightParams(filepath.Join(parent, "backup"))
+
+	err := preflight(context.Background(), params, nil, testProjectSize(1<<62, false), slog.Default())
+
+	var preflightErr *PreflightError
+	require.ErrorAs(t, err, &preflightErr)
+	assert.Len(t, preflightErr.Issues, 3)
+	assert.ErrorContains(t, err, "failed to write probe object")
+	assert.ErrorContains(t, err, "failed to list backup directory")
+	assert.ErrorContains(t, err, "not enough free disk space")
+}
+
+func TestPreflight_Sftp(t *testing.T) {
+	t.Parallel()
+
+	server := newTestSftpServer(t)
+
+	params := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: filepath.Join(t.TempDir(), "backup"),
+			},
+		},
+		Sftp: server.model(),
+	}
+
+	// Free disk space is checked only for local storage.
+	err := preflight(context.Background(), params, nil, testProjectSize(1<<62, false), slog.Default())
+	require.NoError(t, err)
+}
+
+type testEstimator struct {
+	size  uint64
+	calls int
+}
+
+func (e *testEstimator) Estimate(context.Context, *backup.ConfigBackup, int64) (uint64, error) {
+	e.calls++
+	return e.size, nil
+}
+
+func TestPreflight_Estimator(t *testing.T) {
+	t.Parallel()
+
+	params := newTestPreflightParams(filepath.Join(t.TempDir(), "backup"))
+	params.Backup.Namespace = "test"
+	params.Backup.EstimateSamples = 10
+	estimator := &testEstimator{size: 1 << 62}
+
+	err := Preflight(context.Background(), params, nil, estimator, slog.Default())
+
+	var preflightErr *PreflightError
+	require.ErrorAs(t, err, &preflightErr)
+	require.Len(t, preflightErr.Issues, 1)
+	assert.ErrorContains(t, preflightErr.Issues[0], "not enough free disk space")
+	assert.Equal(t, 1, estimator.calls)
+}
+
+func TestShouldPreflight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		params *config.BackupServiceConfig
+		want   bool
+	}{
+		{
+			name:   "Backup",
+			params: &config.BackupServiceConfig{Backup: &models.Backup{}},
+			want:   true,
+		},
+		{
+			name:   "Skip preflight",
+			params: &config.BackupServiceConfig{Backup: &models.Backup{SkipPreflight: true}},
+			want:   false,
+		},
+		{
+			name:   "Remove artifacts",
+			params: &config.BackupServiceConfig{Backup: &models.Backup{RemoveArtifacts: true}},
+			want:   false,
+		},
+		{
+			name:   "Estimate",
+			params: &config.BackupServiceConfig{Backup: &models.Backup{Estimate: true}},
+			want:   false,
+		},
+		{
+			name:   "Stdout",
+			params: &config.BackupServiceConfig{Backup: &models.Backup{OutputFile: config.StdPlaceholder}},
+			want:   false,
+		},
+		{
+			name:   "Xdr backup",
+			params: &config.BackupServiceConfig{BackupXDR: &models.BackupXDR{}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ShouldPreflight(tt.params))
+		})
+	}
+}
+
+func TestPartitionShare(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1, partitionShare(nil), 0, "No filters should cover all partitions")
+	assert.InDelta(t, 1, partitionShare([]*aerospike.PartitionFilter{aerospike.NewPartitionFilterAll()}), 0,
+		"All partitions filter should cover all partitions")
+	assert.InDelta(t, 0.25, partitionShare([]*aerospike.PartitionFilter{
+		aerospike.NewPartitionFilterByRange(0, 512),
+		aerospike.NewPartitionFilterByRange(2048, 512),
+	}), 0, "Ranges should be summed")
+	assert.InDelta(t, 1.0/backup.MaxPartitions, partitionShare([]*aerospike.PartitionFilter{
+		aerospike.NewPartitionFilterById(10),
+	}), 0, "Single partition should be projected")
+}
+
+func TestIsUpperBound(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Now()
+
+	tests := []struct {
+		name   string
+		modify func(c *backup.ConfigBackup)
+		want   bool
+	}{
+		{name: "No filters", modify: func(*backup.ConfigBackup) {}, want: false},
+		{name: "Partitions", modify: func(c *backup.ConfigBackup) {
+			c.PartitionFilters = []*aerospike.PartitionFilter{aerospike.NewPartitionFilterById(1)}
+		}, want: false},
+		{name: "Modified after", modify: func(c *backup.ConfigBackup) { c.ModAfter = &modified }, want: true},
+		{name: "Modified before", modify: func(c *backup.ConfigBackup) { c.ModBefore = &modified }, want: true},
+		{name: "No ttl only", modify: func(c *backup.ConfigBackup) { c.NoTTLOnly = true }, want: true},
+		{name: "Node list", modify: func(c *backup.ConfigBackup) { c.NodeList = []string{"node"} }, want: true},
+		{name: "Rack list", modify: func(c *backup.ConfigBackup) { c.RackList = []int{1} }, want: true},
+		{name: "Filter expression", modify: func(c *backup.ConfigBackup) {
+			c.ScanPolicy = aerospike.NewScanPolicy()
+			c.ScanPolicy.FilterExpression = aerospike.ExpBoolVal(true)
+		}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := backup.NewDefaultBackupConfig()
+			tt.modify(c)
+			assert.Equal(t, tt.want, isUpperBound(c))
+		})
+	}
+}
@@ -45,7 +45,7 @@ func NewRestoreReader(
 	logger *slog.Logger,
 ) (reader, xdrReader backup.StreamingReader, err error) {
 	switch params.Restore.Mode {
-	case models.RestoreModeASB, models.RestoreModeAuto:
+	case models.RestoreModeASB:
 		reader, err = newReader(ctx, params, sa, false, logger)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create asb reader: %w", err)
@@ -140,6 +140,33 @@ func newReader(
 		slog.String("directory_list", directoryList),
 	)
 
+	return newStorageReader(ctx, params, sa, opts, logger)
+}
+
+// NewCheckpointReader initializes a reader for the XDR checkpoint in the restore directory.
+// The reader doesn't fail if the directory doesn't exist.
+func NewCheckpointReader(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) (backup.StreamingReader, error) {
+	opts := []options.Opt{
+		options.WithDir(params.Restore.Directory),
+		options.WithSkipDirCheck(),
+		options.WithLogger(logger),
+	}
+
+	return newStorageReader(ctx, params, sa, opts, logger)
+}
+
+func newStorageReader(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	sa *backup.SecretAgentConfig,
+	opts []options.Opt,
+	logger *slog.Logger,
+) (backup.StreamingReader, error) {
 	switch {
 	case params.AwsS3 != nil && params.AwsS3.BucketName != "":
 		defer logger.Info("initialized AWS storage reader",
@@ -179,12 +206,24 @@ func newReader(
 		}
 
 		return newAzureReader(ctx, params.AzureBlob, opts, logger)
+	case params.Sftp != nil && params.Sftp.Host != "":
+		defer logger.Info("initialized SFTP storage reader",
+			slog.String("host", params.Sftp.Host),
+			slog.String("user", params.Sftp.User),
+			slog.Int("connections", params.Sftp.Connections),
+		)
+
+		if err := params.Sftp.LoadSecrets(sa); err != nil {
+			return nil, fmt.Errorf("failed to load SFTP secrets: %w", err)
+		}
+
+		return newSftpReader(ctx, params.Sftp, opts)
 	case params.IsStdin():
 		defer logger.Info("initialized standard input reader")
 		return newStdReader(ctx, params.Restore.StdBufferSize)
 	default:
 		defer logger.Info("initialized local storage reader")
-		return newLocalReader(ctx, opts)
+		return newLocalReader(ctx, params.Local, opts, logger)
 	}
 }
 
@@ -223,8 +262,29 @@ func newReaderOpts(
 	return opts
 }
 
-func newLocalReader(ctx context.Context, opts []options.Opt) (backup.StreamingReader, error) {
-	return local.NewReader(ctx, opts...)
+func newLocalReader(
+	ctx context.Context,
+	l *models.Local,
+	opts []options.Opt,
+	logger *slog.Logger,
+) (backup.StreamingReader, error) {
+	reader, err := local.NewReader(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil {
+		return reader, nil
+	}
+
+	logger.Info("local storage reader options",
+		slog.Int("buffer_size", l.BufferSize),
+		slog.Int("read_ahead", l.ReadAhead),
+		slog.Bool("drop_cache", l.DropCache),
+		slog.Int("open_concurrency", l.OpenConcurrency),
+	)
+
+	return newLocalReaderWrapper(reader, l), nil
 }
 
 func newStdReader(ctx context.Context, bufferSizeMiB int) (backup.StreamingReader, error) {
@@ -312,6 +372,19 @@ func newAzureReader(
 	return blob.NewReader(ctx, client, a.ContainerName, opts...)
 }
 
+func newSftpReader(
+	ctx context.Context,
+	s *models.Sftp,
+	opts []options.Opt,
+) (backup.StreamingReader, error) {
+	client, err := getSftpClient(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSftpStorageReader(ctx, client, opts...)
+}
+
 // prepareDirectoryList parses command line parameters and return slice of strings.
 func prepareDirectoryList(parentDir, dirList string) []string {
 	result := config.SplitByComma(dirList)
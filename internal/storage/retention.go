@@ -0,0 +1,397 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/storage/common"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	azureImmutabilityModeUnlocked = "Unlocked"
+	azureImmutabilityModeLocked   = "Locked"
+)
+
+// objectLock contains the retention, that is set on backup files when they are uploaded.
+type objectLock struct {
+	mode      string
+	days      int
+	legalHold bool
+	// validator selects backup files, other files, e.g. state files, are left removable.
+	// If nil, the retention is set on all files.
+	validator interface {
+		Run(fileName string) error
+	}
+	now func() time.Time
+}
+
+// newObjectLock returns the retention of backup files, or nil if it is not configured.
+func newObjectLock(params *config.BackupServiceConfig) *objectLock {
+	if params.Backup == nil || !params.Backup.HasRetention() {
+		return nil
+	}
+
+	lock := &objectLock{
+		mode:      params.Backup.RetentionMode,
+		days:      params.Backup.RetentionDays,
+		legalHold: params.Backup.LegalHold,
+		now:       time.Now,
+	}
+
+	// A single output file is always a backup file.
+	if params.Backup.OutputFile == "" {
+		lock.validator = asb.NewValidator()
+	}
+
+	return lock
+}
+
+func (l *objectLock) match(name string) bool {
+	return l.validator == nil || l.validator.Run(name) == nil
+}
+
+// retainUntil returns the end of the retention of a file, that is uploaded now.
+func (l *objectLock) retainUntil() time.Time {
+	return l.now().UTC().Add(time.Duration(l.days) * 24 * time.Hour)
+}
+
+// s3ClientOptions returns options of the S3 client, that set the retention on uploaded objects.
+func (l *objectLock) s3ClientOptions() []func(*s3.Options) {
+	if l == nil {
+		return nil
+	}
+
+	return []func(*s3.Options){
+		func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+				return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ObjectLock", l.handleS3),
+					middleware.After)
+			})
+		},
+	}
+}
+
+// handleS3 sets the retention on a copy of the upload input, so the caller's input is not modified.
+func (l *objectLock) handleS3(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	switch v := in.Parameters.(type) {
+	case *s3.CreateMultipartUploadInput:
+		if l.match(aws.ToString(v.Key)) {
+			p := *v
+			p.ObjectLockMode, p.ObjectLockRetainUntilDate, p.ObjectLockLegalHoldStatus = l.s3Lock()
+			in.Parameters = &p
+		}
+	case *s3.PutObjectInput:
+		if l.match(aws.ToString(v.Key)) {
+			p := *v
+			p.ObjectLockMode, p.ObjectLockRetainUntilDate, p.ObjectLockLegalHoldStatus = l.s3Lock()
+			in.Parameters = &p
+		}
+	}
+
+	return next.HandleInitialize(ctx, in)
+}
+
+func (l *objectLock) s3Lock() (mode types.ObjectLockMode, retainUntil *time.Time,
+	legalHold types.ObjectLockLegalHoldStatus) {
+	if l.days > 0 {
+		mode = types.ObjectLockModeGovernance
+		if l.mode == models.RetentionModeCompliance {
+			mode = types.ObjectLockModeCompliance
+		}
+
+		retainUntil = aws.Time(l.retainUntil())
+	}
+
+	if l.legalHold {
+		legalHold = types.ObjectLockLegalHoldStatusOn
+	}
+
+	return mode, retainUntil, legalHold
+}
+
+// checkS3Bucket checks that Object Lock is enabled for the bucket, otherwise uploads with retention fail.
+func (l *objectLock) checkS3Bucket(ctx context.Context, client *s3.Client, bucketName string) error {
+	if l == nil {
+		return nil
+	}
+
+	resp, err := client.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucketName),
+	})
+
+	var apiErr smithy.APIError
+
+	switch {
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "ObjectLockConfigurationNotFoundError":
+		// The bucket is created without object lock.
+	case err != nil:
+		return fmt.Errorf("failed to get object lock configuration of bucket %s: %w", bucketName, err)
+	case resp.ObjectLockConfiguration != nil &&
+		resp.ObjectLockConfiguration.ObjectLockEnabled == types.ObjectLockEnabledEnabled:
+		return nil
+	}
+
+	return fmt.Errorf("bucket %s is not object lock enabled, retention-days and legal-hold require "+
+		"a bucket with object lock enabled", bucketName)
+}
+
+// azurePolicies returns pipeline policies of the Azure client, that set the immutability policy on uploaded blobs.
+func (l *objectLock) azurePolicies() []policy.Policy {
+	if l == nil {
+		return nil
+	}
+
+	return []policy.Policy{&azureImmutabilityPolicy{lock: l}}
+}
+
+// checkAzureContainer checks that version-level immutability is enabled for the container,
+// otherwise uploads with immutability policy fail.
+func (l *objectLock) checkAzureContainer(ctx context.Context, client *azblob.Client, containerName string) error {
+	if l == nil {
+		return nil
+	}
+
+	resp, err := client.ServiceClient().NewContainerClient(containerName).GetProperties(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get properties of container %s: %w", containerName, err)
+	}
+
+	if resp.IsImmutableStorageWithVersioningEnabled == nil || !*resp.IsImmutableStorageWithVersioningEnabled {
+		return fmt.Errorf("container %s doesn't have version-level immutability enabled, retention-days and "+
+			"legal-hold require a container with version-level immutability enabled", containerName)
+	}
+
+	return nil
+}
+
+// azureImmutabilityPolicy sets the immutability policy and legal hold headers on blob uploads,
+// as backup-go doesn't expose them in upload options.
+type azureImmutabilityPolicy struct {
+	lock *objectLock
+}
+
+func (p *azureImmutabilityPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+
+	if raw.Method == http.MethodPut && isAzureBlobCommit(raw) && p.lock.match(path.Base(raw.URL.Path)) {
+		if p.lock.days > 0 {
+			mode := azureImmutabilityModeUnlocked
+			if p.lock.mode == models.RetentionModeCompliance {
+				mode = azureImmutabilityModeLocked
+			}
+
+			raw.Header["x-ms-immutability-policy-mode"] = []string{mode}
+			raw.Header["x-ms-immutability-policy-until-date"] = []string{
+				p.lock.retainUntil().Format(http.TimeFormat)}
+		}
+
+		if p.lock.legalHold {
+			raw.Header["x-ms-legal-hold"] = []string{strconv.FormatBool(true)}
+		}
+	}
+
+	return req.Next()
+}
+
+// isAzureBlobCommit returns true for requests that create a blob: Put Block List or Put Blob.
+func isAzureBlobCommit(req *http.Request) bool {
+	comp := req.URL.Query().Get("comp")
+
+	return comp == "blocklist" || (comp == "" && req.Header.Get("x-ms-blob-type") != "")
+}
+
+// removeS3Files removes files from the backup directory of a bucket with retention the same way the backup-go
+// writer does, but doesn't stop on the first failure, so all objects that can't be removed are reported.
+// A delete without the version id only adds a delete marker, that is allowed under retention or legal hold,
+// so locked objects would be hidden instead of reported. Therefore, the current version of each object is deleted
+// by its id. Older versions are kept, and if an object has them, a delete marker is added to hide it.
+func removeS3Files(ctx context.Context, client *s3.Client, bucketName string, opts []options.Opt) error {
+	o := applyOptions(opts)
+	if !o.IsRemovingFiles || !o.IsDir {
+		return nil
+	}
+
+	prefix := common.CleanPath(o.PathList[0], true)
+
+	var (
+		current         []types.ObjectVersion
+		hasOlder        = make(map[string]bool)
+		keyMarker       *string
+		versionIDMarker *string
+	)
+
+	for {
+		resp, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+			Bucket:          aws.String(bucketName),
+			Prefix:          aws.String(prefix),
+			KeyMarker:       keyMarker,
+			VersionIdMarker: versionIDMarker,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list object versions: %w", err)
+		}
+
+		for _, version := range resp.Versions {
+			if aws.ToBool(version.IsLatest) {
+				current = append(current, version)
+			} else {
+				hasOlder[aws.ToString(version.Key)] = true
+			}
+		}
+
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+
+		keyMarker, versionIDMarker = resp.NextKeyMarker, resp.NextVersionIdMarker
+	}
+
+	failed := make([]string, 0)
+
+	for _, version := range current {
+		key := aws.ToString(version.Key)
+		if !shouldRemove(o, prefix, key) {
+			continue
+		}
+
+		if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:    aws.String(bucketName),
+			Key:       version.Key,
+			VersionId: version.VersionId,
+		}); err != nil {
+			failed = append(failed, fmt.Sprintf("%s version %s (%s)",
+				key, aws.ToString(version.VersionId), s3ErrorCode(err)))
+
+			continue
+		}
+
+		if !hasOlder[key] {
+			continue
+		}
+
+		// The previous version became current, it is hidden as by a delete without the version id.
+		if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    version.Key,
+		}); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", key, s3ErrorCode(err)))
+		}
+	}
+
+	return newRemoveError(failed)
+}
+
+// removeAzureFiles removes files from the backup directory the same way the backup-go writer does,
+// but doesn't stop on the first failure, so all blobs that can't be removed are reported.
+func removeAzureFiles(ctx context.Context, client *azblob.Client, containerName string, opts []options.Opt) error {
+	o := applyOptions(opts)
+	if !o.IsRemovingFiles || !o.IsDir {
+		return nil
+	}
+
+	prefix := common.CleanPath(o.PathList[0], false)
+	failed := make([]string, 0)
+
+	pager := client.NewListBlobsFlatPager(containerName, &azblob.ListBlobsFlatOptions{
+		Prefix: &prefix,
+	})
+
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list blobs: %w", err)
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			name := common.Deref(item.Name)
+			if !shouldRemove(o, prefix, name) {
+				continue
+			}
+
+			_, err = client.DeleteBlob(ctx, containerName, name, nil)
+			if err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+				failed = append(failed, fmt.Sprintf("%s (%s)", name, azureErrorCode(err)))
+			}
+		}
+	}
+
+	return newRemoveError(failed)
+}
+
+func applyOptions(opts []options.Opt) *options.Options {
+	o := &options.Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// shouldRemove returns true if the file is removed by the backup-go writer.
+func shouldRemove(o *options.Options, prefix, name string) bool {
+	if name == "" || common.IsDirectory(prefix, name) && !o.WithNestedDir {
+		return false
+	}
+
+	return o.Validator == nil || o.Validator.Run(name) == nil
+}
+
+func newRemoveError(failed []string) error {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("failed to remove %d files, they may be protected by retention, legal hold "+
+		"or access policy: %s", len(failed), strings.Join(failed, ", "))
+}
+
+func s3ErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+
+	return err.Error()
+}
+
+func azureErrorCode(err error) string {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.ErrorCode != "" {
+		return respErr.ErrorCode
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,353 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package storage
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetentionNow = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testObjectLock(mode string, days int, legalHold bool) *objectLock {
+	lock := newObjectLock(&config.BackupServiceConfig{
+		Backup: &models.Backup{
+			RetentionMode: mode,
+			RetentionDays: days,
+			LegalHold:     legalHold,
+		},
+	})
+	lock.now = func() time.Time { return testRetentionNow }
+
+	return lock
+}
+
+// transportFunc captures requests of the Azure pipeline.
+type transportFunc func(req *http.Request) (*http.Response, error)
+
+func (f transportFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewObjectLock(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newObjectLock(&config.BackupServiceConfig{Backup: &models.Backup{}}))
+	require.Nil(t, newObjectLock(&config.BackupServiceConfig{}))
+
+	lock := testObjectLock(models.RetentionModeGovernance, 30, false)
+	require.True(t, lock.match("backup/test_1.asb"))
+	require.False(t, lock.match("backup/backup.state"))
+	require.Equal(t, testRetentionNow.Add(30*24*time.Hour), lock.retainUntil())
+
+	lock = newObjectLock(&config.BackupServiceConfig{
+		Backup: &models.Backup{LegalHold: true, OutputFile: "backup.bak"},
+	})
+	require.True(t, lock.match("backup.bak"))
+
+	// Nil lock doesn't change clients.
+	lock = nil
+	require.Nil(t, lock.s3ClientOptions())
+	require.Nil(t, lock.azurePolicies())
+	require.NoError(t, lock.checkS3Bucket(context.Background(), nil, testBucket))
+	require.NoError(t, lock.checkAzureContainer(context.Background(), nil, testBucket))
+}
+
+func TestObjectLock_handleS3(t *testing.T) {
+	t.Parallel()
+
+	var params any
+
+	next := middleware.InitializeHandlerFunc(func(_ context.Context, in middleware.InitializeInput,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		params = in.Parameters
+		return middleware.InitializeOutput{}, middleware.Metadata{}, nil
+	})
+
+	lock := testObjectLock(models.RetentionModeCompliance, 30, true)
+
+	input := &s3.CreateMultipartUploadInput{Key: aws.String("backup/test_1.asb")}
+	_, _, err := lock.handleS3(context.Background(), middleware.InitializeInput{Parameters: input}, next)
+	require.NoError(t, err)
+
+	upload := params.(*s3.CreateMultipartUploadInput)
+	require.Equal(t, types.ObjectLockModeCompliance, upload.ObjectLockMode)
+	require.Equal(t, testRetentionNow.Add(30*24*time.Hour), aws.ToTime(upload.ObjectLockRetainUntilDate))
+	require.Equal(t, types.ObjectLockLegalHoldStatusOn, upload.ObjectLockLegalHoldStatus)
+	require.Empty(t, input.ObjectLockMode)
+
+	// State files are not locked.
+	input = &s3.CreateMultipartUploadInput{Key: aws.String("backup/backup.state")}
+	_, _, err = lock.handleS3(context.Background(), middleware.InitializeInput{Parameters: input}, next)
+	require.NoError(t, err)
+	require.Same(t, input, params)
+
+	// Legal hold only.
+	lock = testObjectLock(models.RetentionModeGovernance, 0, true)
+
+	_, _, err = lock.handleS3(context.Background(),
+		middleware.InitializeInput{Parameters: &s3.PutObjectInput{Key: aws.String("test_1.asb")}}, next)
+	require.NoError(t, err)
+
+	put := params.(*s3.PutObjectInput)
+	require.Empty(t, put.ObjectLockMode)
+	require.Nil(t, put.ObjectLockRetainUntilDate)
+	require.Equal(t, types.ObjectLockLegalHoldStatusOn, put.ObjectLockLegalHoldStatus)
+}
+
+func TestAzureImmutabilityPolicy(t *testing.T) {
+	t.Parallel()
+
+	var captured *http.Request
+
+	transport := transportFunc(func(req *http.Request) (*http.Response, error) {
+		captured = req
+		return &http.Response{StatusCode: http.StatusCreated, Body: http.NoBody, Request: req}, nil
+	})
+
+	lock := testObjectLock(models.RetentionModeGovernance, 30, true)
+	pl := runtime.NewPipeline("test", "v1", runtime.PipelineOptions{},
+		&policy.ClientOptions{Transport: transport, PerCallPolicies: lock.azurePolicies()})
+
+	do := func(url string) {
+		req, err := runtime.NewRequest(context.Background(), http.MethodPut, url)
+		require.NoError(t, err)
+
+		_, err = pl.Do(req)
+		require.NoError(t, err)
+	}
+
+	do("https://account.blob.core.windows.net/container/backup/test_1.asb?comp=blocklist")
+	require.Equal(t, []string{azureImmutabilityModeUnlocked}, captured.Header["x-ms-immutability-policy-mode"])
+	require.Equal(t, []string{"Sat, 31 Jan 2026 00:00:00 GMT"},
+		captured.Header["x-ms-immutability-policy-until-date"])
+	require.Equal(t, []string{"true"}, captured.Header["x-ms-legal-hold"])
+
+	// Blocks are committed later, only the block list is locked.
+	do("https://account.blob.core.windows.net/container/backup/test_1.asb?comp=block&blockid=1")
+	require.Empty(t, captured.Header["x-ms-immutability-policy-mode"])
+
+	do("https://account.blob.core.windows.net/container/backup/backup.state?comp=blocklist")
+	require.Empty(t, captured.Header["x-ms-legal-hold"])
+}
+
+func TestShouldRemove(t *testing.T) {
+	t.Parallel()
+
+	o := applyOptions([]options.Opt{
+		options.WithDir("backup"),
+		options.WithRemoveFiles(),
+		options.WithValidator(asb.NewValidator()),
+	})
+	require.True(t, o.IsRemovingFiles)
+
+	require.True(t, shouldRemove(o, "backup/", "backup/test_1.asb"))
+	require.False(t, shouldRemove(o, "backup/", "backup/backup.state"))
+	require.False(t, shouldRemove(o, "backup/", "backup/nested/test_1.asb"))
+}
+
+func TestNewRemoveError(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, newRemoveError(nil))
+	require.EqualError(t, newRemoveError([]string{"test_1.asb (BlobImmutableDueToPolicy)", "test_2.asb (AccessDenied)"}),
+		"failed to remove 2 files, they may be protected by retention, legal hold or access policy: "+
+			"test_1.asb (BlobImmutableDueToPolicy), test_2.asb (AccessDenied)")
+}
+
+// newTestVersionedBucket creates a versioned bucket, that is removed with all versions after the test.
+func newTestVersionedBucket(t *testing.T, client *s3.Client, objectLock bool) string {
+	t.Helper()
+
+	ctx := context.Background()
+	bucket := "versioned-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	// Object lock enables versioning of the bucket.
+	_, err := client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket:                     aws.String(bucket),
+		ObjectLockEnabledForBucket: aws.Bool(objectLock),
+	})
+	require.NoError(t, err)
+
+	if !objectLock {
+		_, err = client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+			Bucket:                  aws.String(bucket),
+			VersioningConfiguration: &types.VersioningConfiguration{Status: types.BucketVersioningStatusEnabled},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Cleanup(func() {
+		versions, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{Bucket: aws.String(bucket)})
+		require.NoError(t, err)
+
+		remove := func(key, versionID *string) {
+			_, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket: aws.String(bucket), Key: key, VersionId: versionID,
+			})
+			require.NoError(t, err)
+		}
+
+		for _, v := range versions.Versions {
+			remove(v.Key, v.VersionId)
+		}
+
+		for _, m := range versions.DeleteMarkers {
+			remove(m.Key, m.VersionId)
+		}
+
+		_, err = client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+		require.NoError(t, err)
+	})
+
+	return bucket
+}
+
+func putTestObject(t *testing.T, client *s3.Client, bucket, key string, legalHold types.ObjectLockLegalHoldStatus) {
+	t.Helper()
+
+	_, err := client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:                    aws.String(bucket),
+		Key:                       aws.String(key),
+		Body:                      strings.NewReader("data"),
+		ObjectLockLegalHoldStatus: legalHold,
+	})
+	require.NoError(t, err)
+}
+
+func TestRemoveS3Files_Versioned(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, createAwsCredentials())
+
+	ctx := context.Background()
+
+	client, err := newS3Client(ctx, &models.AwsS3{
+		Region:   testS3Region,
+		Profile:  testS3Profile,
+		Endpoint: testS3Endpoint,
+	})
+	require.NoError(t, err)
+
+	bucket := newTestVersionedBucket(t, client, true)
+
+	// Two versions of a removable file, a file under legal hold and a removable file.
+	putTestObject(t, client, bucket, "backup/test_1.asb", "")
+	putTestObject(t, client, bucket, "backup/test_1.asb", "")
+	putTestObject(t, client, bucket, "backup/test_2.asb", types.ObjectLockLegalHoldStatusOn)
+	putTestObject(t, client, bucket, "backup/test_3.asb", "")
+
+	opts := []options.Opt{
+		options.WithDir("backup"),
+		options.WithRemoveFiles(),
+		options.WithValidator(asb.NewValidator()),
+	}
+
+	err = removeS3Files(ctx, client, bucket, opts)
+	require.ErrorContains(t, err, "failed to remove 1 files")
+	require.ErrorContains(t, err, "backup/test_2.asb version")
+
+	objects, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String("backup/"),
+	})
+	require.NoError(t, err)
+	require.Len(t, objects.Contents, 1, "Only the file under legal hold should be left")
+	require.Equal(t, "backup/test_2.asb", aws.ToString(objects.Contents[0].Key))
+
+	versions, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String("backup/"),
+	})
+	require.NoError(t, err)
+	require.Len(t, versions.Versions, 2, "The older version and the version under legal hold should be kept")
+	require.Len(t, versions.DeleteMarkers, 1, "The file with an older version should be hidden")
+	require.Equal(t, "backup/test_1.asb", aws.ToString(versions.DeleteMarkers[0].Key))
+
+	// Release the legal hold, so the bucket can be cleaned.
+	for _, v := range versions.Versions {
+		if aws.ToString(v.Key) != "backup/test_2.asb" {
+			continue
+		}
+
+		_, err = client.PutObjectLegalHold(ctx, &s3.PutObjectLegalHoldInput{
+			Bucket:    aws.String(bucket),
+			Key:       v.Key,
+			VersionId: v.VersionId,
+			LegalHold: &types.ObjectLockLegalHold{Status: types.ObjectLockLegalHoldStatusOff},
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, removeS3Files(ctx, client, bucket, opts))
+}
+
+func TestNewS3Writer_VersionedBucketKeepsVersions(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, createAwsCredentials())
+
+	ctx := context.Background()
+	a := &models.AwsS3{
+		Region:   testS3Region,
+		Profile:  testS3Profile,
+		Endpoint: testS3Endpoint,
+	}
+
+	client, err := newS3Client(ctx, a)
+	require.NoError(t, err)
+
+	a.BucketName = newTestVersionedBucket(t, client, false)
+
+	putTestObject(t, client, a.BucketName, "backup/test_1.asb", "")
+	putTestObject(t, client, a.BucketName, "backup/test_1.asb", "")
+
+	// Without retention, files are removed by the backup-go writer.
+	_, err = newS3Writer(ctx, a, []options.Opt{
+		options.WithDir("backup"),
+		options.WithRemoveFiles(),
+		options.WithValidator(asb.NewValidator()),
+	}, nil)
+	require.NoError(t, err)
+
+	objects, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(a.BucketName),
+		Prefix: aws.String("backup/"),
+	})
+	require.NoError(t, err)
+	require.Empty(t, objects.Contents)
+
+	versions, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+		Bucket: aws.String(a.BucketName),
+		Prefix: aws.String("backup/"),
+	})
+	require.NoError(t, err)
+	require.Len(t, versions.Versions, 2, "Older versions should be kept")
+}
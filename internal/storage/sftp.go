@@ -0,0 +1,508 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/aerospike/backup-go/io/storage/common"
+	"github.com/aerospike/backup-go/io/storage/options"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/pkg/sftp"
+)
+
+const (
+	sftpType = "sftp"
+	// sftpDefaultBufferSize is used when the buffer size is not set in options.
+	sftpDefaultBufferSize = 4 * 1024 * 1024
+)
+
+// sftpWriter writes backup files to a remote host over SFTP.
+// It follows the behavior of the local storage writer, paths are resolved on the remote host.
+type sftpWriter struct {
+	// Optional parameters.
+	options.Options
+
+	client *sftpClient
+}
+
+// newSftpStorageWriter creates a new SFTP writer.
+// Must be called with WithDir(path string) or WithFile(path string).
+func newSftpStorageWriter(ctx context.Context, client *sftpClient, opts ...options.Opt) (*sftpWriter, error) {
+	w := &sftpWriter{client: client}
+
+	for _, opt := range opts {
+		opt(&w.Options)
+	}
+
+	if len(w.PathList) != 1 {
+		return nil, fmt.Errorf("one path is required, use WithDir(path string) or WithFile(path string) to set")
+	}
+
+	if w.ChunkSize == 0 {
+		w.ChunkSize = sftpDefaultBufferSize
+	}
+
+	// If directory does not exist, we don't need to check it for emptiness.
+	if _, err := client.get().Stat(w.dir()); errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+
+	if w.IsDir && !w.SkipDirCheck {
+		entries, err := client.get().ReadDir(w.PathList[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if directory is empty: failed to read path %s: %w",
+				w.PathList[0], err)
+		}
+
+		if len(entries) > 0 && !w.IsRemovingFiles {
+			return nil, fmt.Errorf("backup folder must be empty or set RemoveFiles = true")
+		}
+	}
+
+	if w.IsRemovingFiles {
+		if err := w.RemoveFiles(ctx); err != nil {
+			return nil, fmt.Errorf("failed to remove files: %w", err)
+		}
+	}
+
+	return w, nil
+}
+
+// dir returns the directory where files are written.
+func (w *sftpWriter) dir() string {
+	if w.IsDir {
+		return w.PathList[0]
+	}
+
+	return path.Dir(w.PathList[0])
+}
+
+// NewWriter creates a new backup file in the backup directory.
+// Each file is opened on the next connection of the pool, so files are uploaded in parallel.
+func (w *sftpWriter) NewWriter(ctx context.Context, filename string) (io.WriteCloser, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	client := w.client.get()
+
+	// Create directory only if we have something to back up to this directory.
+	if err := client.MkdirAll(w.dir()); err != nil {
+		return nil, fmt.Errorf("failed to prepare backup directory: %w", err)
+	}
+
+	// We ignore filename if writer was initialized with WithFile().
+	var filePath string
+
+	switch {
+	case w.IsDir:
+		filePath = path.Join(w.PathList[0], filename)
+	case filename != "":
+		// If it is metadata file and we back up to one file.
+		filePath = path.Join(path.Dir(w.PathList[0]), filename)
+	default:
+		filePath = w.PathList[0]
+	}
+
+	file, err := client.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
+	}
+
+	return &sftpBufferedFile{Writer: bufio.NewWriterSize(file, w.ChunkSize), file: file}, nil
+}
+
+// RemoveFiles removes a backup file or files from directory.
+func (w *sftpWriter) RemoveFiles(ctx context.Context) error {
+	return w.Remove(ctx, w.PathList[0])
+}
+
+// Remove deletes the file or directory contents specified by targetPath.
+// Without nested directories, only files accepted by the validator are removed.
+func (w *sftpWriter) Remove(ctx context.Context, targetPath string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	client := w.client.get()
+
+	info, err := client.Stat(targetPath)
+
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		return nil
+	default:
+		return fmt.Errorf("failed to stat path %s: %w", targetPath, err)
+	}
+
+	if !info.IsDir() {
+		if err = client.Remove(targetPath); err != nil {
+			return fmt.Errorf("failed to remove file %s: %w", targetPath, err)
+		}
+
+		return nil
+	}
+
+	if w.WithNestedDir {
+		if err = client.RemoveAll(targetPath); err != nil {
+			return fmt.Errorf("failed to remove path %s: %w", targetPath, err)
+		}
+
+		return nil
+	}
+
+	entries, err := client.ReadDir(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", targetPath, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		filePath := path.Join(targetPath, entry.Name())
+
+		if w.Validator != nil {
+			if err = w.Validator.Run(filePath); err != nil {
+				continue
+			}
+		}
+
+		if err = client.Remove(filePath); err != nil {
+			return fmt.Errorf("failed to remove file %s: %w", filePath, err)
+		}
+	}
+
+	return nil
+}
+
+// GetType returns the type of storage. Used in logging.
+func (w *sftpWriter) GetType() string {
+	return sftpType
+}
+
+// GetOptions returns initialized options for the writer.
+func (w *sftpWriter) GetOptions() options.Options {
+	return w.Options
+}
+
+// sftpBufferedFile buffers writes to a remote file, so data is sent in large requests.
+type sftpBufferedFile struct {
+	*bufio.Writer
+	file *sftp.File
+}
+
+// Close flushes the buffer and closes the remote file.
+func (f *sftpBufferedFile) Close() error {
+	flushErr := f.Flush()
+	closeErr := f.file.Close()
+
+	return errors.Join(flushErr, closeErr)
+}
+
+// sftpReader reads backup files from a remote host over SFTP.
+// It follows the behavior of the local storage reader, paths are resolved on the remote host.
+type sftpReader struct {
+	// Optional parameters.
+	options.Options
+
+	client *sftpClient
+
+	// objectsToStream is a predefined list of objects to read, set on files sorting.
+	objectsToStream []string
+
+	totalSize   atomic.Int64
+	totalNumber atomic.Int64
+
+	// If skipPrefixes are set on StreamFiles, skipped file names are stored here.
+	skipped *common.SkippedFiles
+}
+
+// newSftpStorageReader creates a new SFTP reader.
+// Must be called with WithDir(path string) or WithFile(path string).
+func newSftpStorageReader(ctx context.Context, client *sftpClient, opts ...options.Opt) (*sftpReader, error) {
+	r := &sftpReader{client: client}
+
+	for _, opt := range opts {
+		opt(&r.Options)
+	}
+
+	if len(r.PathList) == 0 {
+		return nil, fmt.Errorf("path is required, use WithDir(path string) or WithFile(path string) to set")
+	}
+
+	if r.IsDir {
+		if !r.SkipDirCheck {
+			for _, p := range r.PathList {
+				if err := r.checkRestoreDirectory(p); err != nil {
+					return nil, fmt.Errorf("%w: %w", common.ErrEmptyStorage, err)
+				}
+			}
+		}
+
+		if r.SortFiles && len(r.PathList) == 1 {
+			if err := common.PreSort(ctx, r, r.PathList[0]); err != nil {
+				return nil, fmt.Errorf("failed to pre sort: %w", err)
+			}
+		}
+	}
+
+	if r.CalculateTotalSize {
+		// The total size is calculated lazily, for estimates only.
+		go r.calculateTotalSize(ctx)
+	}
+
+	return r, nil
+}
+
+// StreamFiles sends readers of remote files to readersCh.
+// In case of an error, it is sent to errorsCh.
+func (r *sftpReader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	defer close(readersCh)
+
+	if len(r.objectsToStream) > 0 {
+		for _, p := range r.objectsToStream {
+			r.StreamFile(ctx, p, readersCh, errorsCh)
+		}
+
+		return
+	}
+
+	if len(skipPrefixes) > 0 {
+		r.skipped = common.NewSkippedFiles(skipPrefixes)
+	}
+
+	for _, p := range r.PathList {
+		if !r.IsDir {
+			r.StreamFile(ctx, p, readersCh, errorsCh)
+			continue
+		}
+
+		if !r.SkipDirCheck {
+			if err := r.checkRestoreDirectory(p); err != nil {
+				common.ErrToChan(ctx, errorsCh, err)
+				return
+			}
+		}
+
+		err := r.walk(ctx, p, func(filePath string, info os.FileInfo) error {
+			// Skip empty files.
+			if info.Size() == 0 || r.skipped.Skip(filePath) {
+				return nil
+			}
+
+			file, err := r.client.get().Open(filePath)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", filePath, err)
+			}
+
+			readersCh <- bModels.File{Reader: file, Name: info.Name()}
+
+			return nil
+		})
+		if err != nil {
+			common.ErrToChan(ctx, errorsCh, err)
+			return
+		}
+	}
+}
+
+// StreamFile opens a single remote file and sends its reader to readersCh.
+// In case of an error, it is sent to errorsCh.
+func (r *sftpReader) StreamFile(
+	ctx context.Context, filename string, readersCh chan<- bModels.File, errorsCh chan<- error,
+) {
+	if ctx.Err() != nil {
+		common.ErrToChan(ctx, errorsCh, ctx.Err())
+		return
+	}
+
+	file, err := r.client.get().Open(filename)
+	if err != nil {
+		common.ErrToChan(ctx, errorsCh, fmt.Errorf("failed to open %s: %w", filename, err))
+		return
+	}
+
+	readersCh <- bModels.File{Reader: file, Name: path.Base(filename)}
+}
+
+// walk calls fn for each file in dir accepted by the validator, nested directories are walked if enabled.
+func (r *sftpReader) walk(ctx context.Context, dir string, fn func(filePath string, info os.FileInfo) error) error {
+	entries, err := r.client.get().ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read path %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		filePath := path.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			if r.WithNestedDir {
+				if err = r.walk(ctx, filePath, fn); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if r.Validator != nil {
+			if err = r.Validator.Run(filePath); err != nil {
+				continue
+			}
+		}
+
+		if err = fn(filePath, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// errSftpFileFound stops the directory walk on the first file.
+var errSftpFileFound = errors.New("file found")
+
+// checkRestoreDirectory checks that the restore directory exists and contains backup files.
+func (r *sftpReader) checkRestoreDirectory(dir string) error {
+	info, err := r.client.get().Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get path info %s: %w", dir, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	err = r.walk(context.Background(), dir, func(string, os.FileInfo) error {
+		return errSftpFileFound
+	})
+
+	switch {
+	case errors.Is(err, errSftpFileFound):
+		return nil
+	case err != nil:
+		return err
+	default:
+		return fmt.Errorf("%s is empty", dir)
+	}
+}
+
+// ListObjects returns full paths of all files in the path accepted by the validator.
+func (r *sftpReader) ListObjects(ctx context.Context, dir string) ([]string, error) {
+	result := make([]string, 0)
+
+	err := r.walk(ctx, dir, func(filePath string, _ os.FileInfo) error {
+		result = append(result, filePath)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && r.SkipDirCheck {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SetObjectsToStream sets objects to stream.
+func (r *sftpReader) SetObjectsToStream(list []string) {
+	r.objectsToStream = list
+}
+
+// calculateTotalSize calculates the total size and number of files in all paths.
+// On error, -1 is stored to signal that estimates are not available.
+func (r *sftpReader) calculateTotalSize(ctx context.Context) {
+	var totalSize, totalNum int64
+
+	for _, p := range r.PathList {
+		err := r.sizeOfPath(ctx, p, func(size int64) {
+			totalSize += size
+			totalNum++
+		})
+		if err != nil {
+			if r.Logger != nil {
+				r.Logger.Warn("failed to calculate stats for path",
+					slog.String("path", p),
+					slog.Any("error", err),
+				)
+			}
+
+			r.totalSize.Store(-1)
+			r.totalNumber.Store(-1)
+
+			return
+		}
+	}
+
+	r.totalSize.Store(totalSize)
+	r.totalNumber.Store(totalNum)
+}
+
+func (r *sftpReader) sizeOfPath(ctx context.Context, p string, add func(size int64)) error {
+	info, err := r.client.get().Stat(p)
+	if err != nil {
+		return fmt.Errorf("failed to get path info %s: %w", p, err)
+	}
+
+	if !info.IsDir() {
+		add(info.Size())
+		return nil
+	}
+
+	return r.walk(ctx, p, func(_ string, info os.FileInfo) error {
+		add(info.Size())
+		return nil
+	})
+}
+
+// GetType returns the type of storage. Used in logging.
+func (r *sftpReader) GetType() string {
+	return sftpType
+}
+
+// GetSize returns the total size of backup files.
+func (r *sftpReader) GetSize() int64 {
+	return r.totalSize.Load()
+}
+
+// GetNumber returns the number of backup files.
+func (r *sftpReader) GetNumber() int64 {
+	return r.totalNumber.Load()
+}
+
+// GetSkipped returns a list of file paths that were skipped during StreamFiles with skipPrefixes.
+func (r *sftpReader) GetSkipped() []string {
+	return r.skipped.GetSkipped()
+}
@@ -0,0 +1,449 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/config"
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/aerospike/backup-go/io/storage/common"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	testSftpType     = "sftp"
+	testSftpUser     = "backup"
+	testSftpPassword = "secret"
+)
+
+// testSftpServer is an in-process SSH server with the SFTP subsystem.
+type testSftpServer struct {
+	addr           string
+	knownHostsFile string
+	keyFile        string
+	clientKey      ed25519.PrivateKey
+}
+
+func newTestSftpServer(t *testing.T) *testSftpServer {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	clientPub, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	clientSSHPub, err := ssh.NewPublicKey(clientPub)
+	require.NoError(t, err)
+
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if c.User() == testSftpUser && string(password) == testSftpPassword {
+				return nil, nil
+			}
+
+			return nil, assert.AnError
+		},
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if c.User() == testSftpUser && string(key.Marshal()) == string(clientSSHPub.Marshal()) {
+				return nil, nil
+			}
+
+			return nil, assert.AnError
+		},
+	}
+	serverConfig.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveTestSftpConn(conn, serverConfig)
+		}
+	}()
+
+	s := &testSftpServer{
+		addr:           listener.Addr().String(),
+		knownHostsFile: filepath.Join(dir, "known_hosts"),
+		keyFile:        filepath.Join(dir, "id_ed25519"),
+		clientKey:      clientKey,
+	}
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(s.addr)}, hostSigner.PublicKey())
+	require.NoError(t, os.WriteFile(s.knownHostsFile, []byte(line+"\n"), 0o600))
+
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(s.keyFile, pem.EncodeToMemory(block), 0o600))
+
+	return s
+}
+
+func serveTestSftpConn(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			for req := range requests {
+				// Payload of the subsystem request is a length prefixed subsystem name.
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(ok, nil)
+
+				if ok {
+					server, err := sftp.NewServer(channel)
+					if err != nil {
+						return
+					}
+
+					_ = server.Serve()
+					_ = server.Close()
+				}
+			}
+		}()
+	}
+}
+
+func (s *testSftpServer) model() *models.Sftp {
+	return &models.Sftp{
+		Host:           s.addr,
+		User:           testSftpUser,
+		KeyFile:        s.keyFile,
+		KnownHostsFile: s.knownHostsFile,
+		Connections:    2,
+		ConnectTimeout: 5000,
+		BufferSize:     1,
+	}
+}
+
+func TestSftp_WriterReader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newTestSftpServer(t)
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	backupParams := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: dir,
+			},
+		},
+		Sftp: server.model(),
+	}
+
+	writer, err := newWriter(ctx, backupParams, nil, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, testSftpType, writer.GetType())
+
+	files := map[string]string{
+		"ns_1.asb": "first file",
+		"ns_2.asb": "second file",
+		"ns_3.asb": "",
+	}
+
+	for name, content := range files {
+		w, err := writer.NewWriter(ctx, name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+
+	for name, content := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+
+	restoreParams := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common: models.Common{
+				Directory: dir,
+			},
+		},
+		Sftp: server.model(),
+	}
+
+	reader, err := newReader(ctx, restoreParams, nil, false, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, testSftpType, reader.GetType())
+
+	objects, err := reader.ListObjects(ctx, dir)
+	require.NoError(t, err)
+	sort.Strings(objects)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "ns_1.asb"),
+		filepath.Join(dir, "ns_2.asb"),
+		filepath.Join(dir, "ns_3.asb"),
+	}, objects)
+
+	readersCh := make(chan bModels.File)
+	errorsCh := make(chan error, 1)
+
+	go reader.StreamFiles(ctx, readersCh, errorsCh, nil)
+
+	read := make(map[string]string)
+
+	for file := range readersCh {
+		data, err := io.ReadAll(file.Reader)
+		require.NoError(t, err)
+		require.NoError(t, file.Reader.Close())
+
+		read[file.Name] = string(data)
+	}
+
+	assert.Empty(t, errorsCh)
+	// Empty files are skipped.
+	assert.Equal(t, map[string]string{
+		"ns_1.asb": "first file",
+		"ns_2.asb": "second file",
+	}, read)
+
+	// Not empty directory requires removing files.
+	_, err = newWriter(ctx, backupParams, nil, slog.Default())
+	assert.ErrorContains(t, err, "backup folder must be empty or set RemoveFiles = true")
+
+	backupParams.Backup.RemoveFiles = true
+	_, err = newWriter(ctx, backupParams, nil, slog.Default())
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = newReader(ctx, restoreParams, nil, false, slog.Default())
+	assert.ErrorIs(t, err, common.ErrEmptyStorage)
+}
+
+func TestSftp_DirectoryList(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newTestSftpServer(t)
+	parent := t.TempDir()
+
+	for _, dir := range []string{"dir1", "dir2"} {
+		require.NoError(t, os.Mkdir(filepath.Join(parent, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(parent, dir, dir+".asb"), []byte(dir), 0o600))
+	}
+
+	params := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			ParentDirectory: parent,
+			DirectoryList:   "dir1,dir2",
+		},
+		Sftp: server.model(),
+	}
+
+	reader, err := newReader(ctx, params, nil, false, slog.Default())
+	require.NoError(t, err)
+
+	readersCh := make(chan bModels.File)
+	errorsCh := make(chan error, 1)
+
+	go reader.StreamFiles(ctx, readersCh, errorsCh, nil)
+
+	names := make([]string, 0)
+
+	for file := range readersCh {
+		names = append(names, file.Name)
+		require.NoError(t, file.Reader.Close())
+	}
+
+	assert.Empty(t, errorsCh)
+	assert.ElementsMatch(t, []string{"dir1.asb", "dir2.asb"}, names)
+}
+
+func TestSftp_OutputFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newTestSftpServer(t)
+	outputFile := filepath.Join(t.TempDir(), "nested", "backup.asb")
+
+	params := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			OutputFile: outputFile,
+		},
+		Sftp: server.model(),
+	}
+
+	writer, err := newWriter(ctx, params, nil, slog.Default())
+	require.NoError(t, err)
+
+	w, err := writer.NewWriter(ctx, "")
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "data")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+}
+
+func TestSftp_newSftpClientAuth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newTestSftpServer(t)
+
+	// Password authorization.
+	s := server.model()
+	s.KeyFile = ""
+	s.Password = testSftpPassword
+	client, err := newSftpClient(ctx, s)
+	require.NoError(t, err)
+	assert.Len(t, client.clients, 2)
+	assert.NoError(t, client.Close())
+
+	// Wrong password.
+	s.Password = "wrong"
+	_, err = newSftpClient(ctx, s)
+	assert.ErrorContains(t, err, "unable to authenticate")
+
+	// Key file protected with a passphrase.
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(server.clientKey, "", []byte("passphrase"))
+	require.NoError(t, err)
+
+	s = server.model()
+	s.KeyFile = filepath.Join(t.TempDir(), "id_ed25519")
+	s.KeyPassphrase = "passphrase"
+	require.NoError(t, os.WriteFile(s.KeyFile, pem.EncodeToMemory(block), 0o600))
+	client, err = newSftpClient(ctx, s)
+	require.NoError(t, err)
+	assert.NoError(t, client.Close())
+
+	// Unknown host key.
+	s = server.model()
+	s.KnownHostsFile = filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(s.KnownHostsFile, nil, 0o600))
+	_, err = newSftpClient(ctx, s)
+	assert.ErrorContains(t, err, "key is unknown")
+}
+
+//nolint:paralleltest // The test sets the SSH_AUTH_SOCK environment variable.
+func TestSftp_newSftpClientAgent(t *testing.T) {
+	ctx := context.Background()
+	server := newTestSftpServer(t)
+
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: server.clientKey}))
+
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	// ServeAgent returns when the client closes the connection.
+	served := make(chan struct{}, 1)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				_ = agent.ServeAgent(keyring, conn)
+				served <- struct{}{}
+			}()
+		}
+	}()
+
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	s := server.model()
+	s.KeyFile = ""
+	s.UseAgent = true
+	client, err := newSftpClient(ctx, s)
+	require.NoError(t, err)
+
+	select {
+	case <-served:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection to the SSH agent is not closed")
+	}
+
+	assert.NoError(t, client.Close())
+}
+
+func TestSftp_getSftpClient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newTestSftpServer(t).model()
+
+	client, err := getSftpClient(ctx, s)
+	require.NoError(t, err)
+
+	// Writers and readers with the same params share the pool.
+	cached, err := getSftpClient(ctx, s)
+	require.NoError(t, err)
+	assert.Same(t, client, cached)
+
+	// A broken pool is replaced.
+	require.NoError(t, client.Close())
+	require.Eventually(t, client.closed.Load, 5*time.Second, 10*time.Millisecond)
+
+	reconnected, err := getSftpClient(ctx, s)
+	require.NoError(t, err)
+	assert.NotSame(t, client, reconnected)
+	assert.NoError(t, reconnected.Close())
+}
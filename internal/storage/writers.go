@@ -71,7 +71,9 @@ func newWriter(
 
 	directory, outputFile := getDirectoryOutputFile(params)
 	shouldClearTarget, continueBackup := getShouldCleanContinue(params)
-	opts := newWriterOpts(directory, outputFile, shouldClearTarget, continueBackup, params.IsXDR(), logger)
+	// Shards of a distributed backup write to the same directory, each shard checks only its own files.
+	skipDirCheck := continueBackup || params.IsShard()
+	opts := newWriterOpts(directory, outputFile, shouldClearTarget, skipDirCheck, params.IsXDR(), logger)
 
 	logger.Info("initializing storage for writer",
 		slog.String("directory", directory),
@@ -80,6 +82,83 @@ func newWriter(
 		slog.Bool("continue_backup", continueBackup),
 	)
 
+	return newStorageWriter(ctx, params, sa, opts, newObjectLock(params), logger)
+}
+
+// NewCheckpointWriter initializes a writer for the XDR checkpoint in the backup directory.
+// Segments are written by separate writers, so the directory is not checked for emptiness.
+// If removeFiles is set, the directory is cleaned with all segments.
+func NewCheckpointWriter(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	removeFiles bool,
+	logger *slog.Logger,
+) (backup.Writer, error) {
+	opts := []options.Opt{
+		options.WithDir(params.BackupXDR.Directory),
+		options.WithSkipDirCheck(),
+		options.WithLogger(logger),
+	}
+
+	if removeFiles {
+		opts = append(opts, options.WithRemoveFiles(), options.WithNestedDir())
+	}
+
+	return newStorageWriter(ctx, params, sa, opts, nil, logger)
+}
+
+// NewShardMarkerWriter initializes a writer for the completion marker of a shard in the backup directory.
+// The directory is shared by all shards, so it is not checked for emptiness.
+func NewShardMarkerWriter(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) (backup.Writer, error) {
+	opts := []options.Opt{
+		options.WithDir(params.Backup.Directory),
+		options.WithSkipDirCheck(),
+		options.WithLogger(logger),
+	}
+
+	return newStorageWriter(ctx, params, sa, opts, nil, logger)
+}
+
+// NewRestoreStateWriter initializes a writer for the state file of a restore in the restore directory.
+func NewRestoreStateWriter(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	sa *backup.SecretAgentConfig,
+	logger *slog.Logger,
+) (backup.Writer, error) {
+	opts := []options.Opt{
+		options.WithDir(params.Restore.Directory),
+		options.WithSkipDirCheck(),
+		options.WithLogger(logger),
+	}
+
+	storageParams := &config.BackupServiceConfig{
+		AwsS3:      params.AwsS3,
+		GcpStorage: params.GcpStorage,
+		AzureBlob:  params.AzureBlob,
+		Sftp:       params.Sftp,
+		Local:      params.Local,
+	}
+
+	return newStorageWriter(ctx, storageParams, sa, opts, nil, logger)
+}
+
+// newStorageWriter initializes a writer for the configured storage.
+// If lock is not nil, the retention is set on backup files written by the writer.
+func newStorageWriter(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	sa *backup.SecretAgentConfig,
+	opts []options.Opt,
+	lock *objectLock,
+	logger *slog.Logger,
+) (backup.Writer, error) {
 	switch {
 	case params.AwsS3 != nil && params.AwsS3.BucketName != "":
 		defer logger.Info("initialized AWS storage writer",
@@ -93,7 +172,7 @@ func newWriter(
 			return nil, fmt.Errorf("failed to load AWS secrets: %w", err)
 		}
 
-		return newS3Writer(ctx, params.AwsS3, opts)
+		return newS3Writer(ctx, params.AwsS3, opts, lock)
 	case params.GcpStorage != nil && params.GcpStorage.BucketName != "":
 		defer logger.Info("initialized GCP storage writer",
 			slog.String("bucket", params.GcpStorage.BucketName),
@@ -118,7 +197,20 @@ func newWriter(
 			return nil, fmt.Errorf("failed to load azure secrets: %w", err)
 		}
 
-		return newAzureWriter(ctx, params.AzureBlob, opts)
+		return newAzureWriter(ctx, params.AzureBlob, opts, lock)
+	case params.Sftp != nil && params.Sftp.Host != "":
+		defer logger.Info("initialized SFTP storage writer",
+			slog.String("host", params.Sftp.Host),
+			slog.String("user", params.Sftp.User),
+			slog.Int("connections", params.Sftp.Connections),
+			slog.Int("buffer_size", params.Sftp.BufferSize),
+		)
+
+		if err := params.Sftp.LoadSecrets(sa); err != nil {
+			return nil, fmt.Errorf("failed to load SFTP secrets: %w", err)
+		}
+
+		return newSftpWriter(ctx, params.Sftp, opts)
 	case params.IsStdout():
 		defer logger.Info("initialized standard output writer")
 		return newStdWriter(ctx, params.Backup.StdBufferSize)
@@ -149,7 +241,7 @@ func newWriterOpts(
 	directory,
 	outputFile string,
 	shouldClearTarget,
-	continueBackup,
+	skipDirCheck,
 	isXDR bool,
 	logger *slog.Logger,
 ) []options.Opt {
@@ -167,7 +259,7 @@ func newWriterOpts(
 		opts = append(opts, options.WithRemoveFiles())
 	}
 
-	if continueBackup {
+	if skipDirCheck {
 		opts = append(opts, options.WithSkipDirCheck())
 	}
 
@@ -199,12 +291,24 @@ func newS3Writer(
 	ctx context.Context,
 	a *models.AwsS3,
 	opts []options.Opt,
+	lock *objectLock,
 ) (backup.Writer, error) {
-	client, err := newS3Client(ctx, a)
+	client, err := newS3Client(ctx, a, lock.s3ClientOptions()...)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = lock.checkS3Bucket(ctx, client, a.BucketName); err != nil {
+		return nil, err
+	}
+
+	// Without retention, files are removed by the backup-go writer.
+	if lock != nil {
+		if err = removeS3Files(ctx, client, a.BucketName, opts); err != nil {
+			return nil, err
+		}
+	}
+
 	if a.StorageClass != "" {
 		opts = append(opts, options.WithStorageClass(a.StorageClass))
 	}
@@ -243,12 +347,21 @@ func newAzureWriter(
 	ctx context.Context,
 	a *models.AzureBlob,
 	opts []options.Opt,
+	lock *objectLock,
 ) (backup.Writer, error) {
-	client, err := newAzureClient(a)
+	client, err := newAzureClient(a, lock.azurePolicies()...)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = lock.checkAzureContainer(ctx, client, a.ContainerName); err != nil {
+		return nil, err
+	}
+
+	if err = removeAzureFiles(ctx, client, a.ContainerName, opts); err != nil {
+		return nil, err
+	}
+
 	if a.AccessTier != "" {
 		opts = append(opts, options.WithStorageClass(a.AccessTier))
 	}
@@ -262,3 +375,19 @@ func newAzureWriter(
 
 	return blob.NewWriter(ctx, client, a.ContainerName, opts...)
 }
+
+func newSftpWriter(
+	ctx context.Context,
+	s *models.Sftp,
+	opts []options.Opt,
+) (backup.Writer, error) {
+	client, err := getSftpClient(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	bufferSize := s.BufferSize * 1024 * 1024
+	opts = append(opts, options.WithChunkSize(bufferSize))
+
+	return newSftpStorageWriter(ctx, client, opts...)
+}
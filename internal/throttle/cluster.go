@@ -0,0 +1,210 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+)
+
+const (
+	infoStatistics = "statistics"
+	statCPU        = "system_total_cpu_pct"
+)
+
+// Namespace statistics that are counted as client errors.
+var errorStats = []string{
+	"client_read_error",
+	"client_read_timeout",
+	"client_write_error",
+	"client_write_timeout",
+}
+
+// NodeStats contains load statistics of a node.
+type NodeStats struct {
+	Name string
+	// Latency histograms of reads and writes. Value i is the percentage of operations slower than 2^i ms.
+	ReadLatency  []float64
+	WriteLatency []float64
+	CPUPct       int64
+	// Errors is the number of client errors and timeouts in the namespace since the node start.
+	Errors uint64
+}
+
+// StatsGetter returns statistics of cluster nodes.
+type StatsGetter interface {
+	GetNodeStats(ctx context.Context) ([]NodeStats, error)
+}
+
+// infoNode is the part of *aerospike.Node used to request statistics.
+type infoNode interface {
+	GetName() string
+	IsActive() bool
+	RequestInfo(policy *aerospike.InfoPolicy, name ...string) (map[string]string, aerospike.Error)
+}
+
+// InfoStats gets node statistics with info commands.
+type InfoStats struct {
+	nodes     func() []infoNode
+	policy    *aerospike.InfoPolicy
+	namespace string
+}
+
+// NewInfoStats returns a new InfoStats for the namespace.
+func NewInfoStats(client *aerospike.Client, policy *aerospike.InfoPolicy, namespace string) *InfoStats {
+	return &InfoStats{
+		nodes: func() []infoNode {
+			nodes := client.GetNodes()
+			result := make([]infoNode, 0, len(nodes))
+
+			for _, node := range nodes {
+				result = append(result, node)
+			}
+
+			return result
+		},
+		policy:    policy,
+		namespace: namespace,
+	}
+}
+
+// GetNodeStats requests statistics from each active node.
+func (s *InfoStats) GetNodeStats(ctx context.Context) ([]NodeStats, error) {
+	readHist := fmt.Sprintf("{%s}-read", s.namespace)
+	writeHist := fmt.Sprintf("{%s}-write", s.namespace)
+	nsCmd := "namespace/" + s.namespace
+	// Buckets for each power of two, by default only every third bucket is returned.
+	readCmd := fmt.Sprintf("latencies:hist=%s;exponent-increment=1;max-bucket=16", readHist)
+	writeCmd := fmt.Sprintf("latencies:hist=%s;exponent-increment=1;max-bucket=16", writeHist)
+
+	nodes := s.nodes()
+	result := make([]NodeStats, 0, len(nodes))
+
+	for _, node := range nodes {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if !node.IsActive() {
+			continue
+		}
+
+		resp, err := node.RequestInfo(s.policy, infoStatistics, nsCmd, readCmd, writeCmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statistics of node %s: %w", node.GetName(), err)
+		}
+
+		stats, parseErr := parseNodeStats(resp[infoStatistics], resp[nsCmd],
+			resp[readCmd], resp[writeCmd], readHist, writeHist)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse statistics of node %s: %w", node.GetName(), parseErr)
+		}
+
+		stats.Name = node.GetName()
+		result = append(result, stats)
+	}
+
+	return result, nil
+}
+
+func parseNodeStats(statistics, namespace, readLatency, writeLatency, readHist, writeHist string,
+) (NodeStats, error) {
+	var (
+		result NodeStats
+		err    error
+	)
+
+	stats := parseStats(statistics)
+	if v, ok := stats[statCPU]; ok {
+		if result.CPUPct, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return NodeStats{}, fmt.Errorf("invalid %s %q: %w", statCPU, v, err)
+		}
+	}
+
+	nsStats := parseStats(namespace)
+	for _, name := range errorStats {
+		v, ok := nsStats[name]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return NodeStats{}, fmt.Errorf("invalid %s %q: %w", name, v, err)
+		}
+
+		result.Errors += n
+	}
+
+	if result.ReadLatency, err = parseLatencies(readLatency, readHist); err != nil {
+		return NodeStats{}, err
+	}
+
+	if result.WriteLatency, err = parseLatencies(writeLatency, writeHist); err != nil {
+		return NodeStats{}, err
+	}
+
+	return result, nil
+}
+
+// parseStats parses a response in the key1=value1;key2=value2 format.
+func parseStats(resp string) map[string]string {
+	result := make(map[string]string)
+
+	for _, pair := range strings.Split(resp, ";") {
+		key, value, ok := strings.Cut(pair, "=")
+		if ok {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// parseLatencies parses a histogram in the {ns}-read:msec,ops/sec,pct1,pct2,... format.
+// The histogram is empty if there were no operations.
+func parseLatencies(resp, hist string) ([]float64, error) {
+	for _, entry := range strings.Split(resp, ";") {
+		name, value, ok := strings.Cut(entry, ":")
+		if !ok || name != hist {
+			continue
+		}
+
+		fields := strings.Split(value, ",")
+		// Units and ops/sec go before buckets.
+		if len(fields) < 2 {
+			return nil, nil
+		}
+
+		result := make([]float64, 0, len(fields)-2)
+
+		for _, f := range fields[2:] {
+			pct, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid latency of %s %q: %w", hist, f, err)
+			}
+
+			result = append(result, pct)
+		}
+
+		return result, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNode struct {
+	name   string
+	active bool
+	resp   map[string]string
+	err    aerospike.Error
+}
+
+func (n *fakeNode) GetName() string { return n.name }
+
+func (n *fakeNode) IsActive() bool { return n.active }
+
+func (n *fakeNode) RequestInfo(_ *aerospike.InfoPolicy, names ...string) (map[string]string, aerospike.Error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+
+	result := make(map[string]string, len(names))
+	for _, name := range names {
+		result[name] = n.resp[name]
+	}
+
+	return result, nil
+}
+
+func TestInfoStats_GetNodeStats(t *testing.T) {
+	t.Parallel()
+
+	readCmd := "latencies:hist={test}-read;exponent-increment=1;max-bucket=16"
+	writeCmd := "latencies:hist={test}-write;exponent-increment=1;max-bucket=16"
+
+	active := &fakeNode{name: "A", active: true, resp: map[string]string{
+		"statistics": "cluster_size=2;system_total_cpu_pct=42;uptime=100",
+		"namespace/test": "objects=10;client_read_error=3;client_read_timeout=2;" +
+			"client_write_error=1;client_write_timeout=4",
+		readCmd:  "{test}-read:msec,120.5,10.00,5.50,1.25",
+		writeCmd: "{test}-write:",
+	}}
+	inactive := &fakeNode{name: "B"}
+
+	s := &InfoStats{
+		nodes:     func() []infoNode { return []infoNode{active, inactive} },
+		namespace: "test",
+	}
+
+	stats, err := s.GetNodeStats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []NodeStats{{
+		Name:        "A",
+		ReadLatency: []float64{10, 5.5, 1.25},
+		CPUPct:      42,
+		Errors:      10,
+	}}, stats)
+
+	active.resp["statistics"] = "system_total_cpu_pct=high"
+
+	_, err = s.GetNodeStats(context.Background())
+	require.ErrorContains(t, err, "failed to parse statistics of node A")
+
+	active.err = aerospike.ErrNetTimeout
+
+	_, err = s.GetNodeStats(context.Background())
+	require.ErrorContains(t, err, "failed to get statistics of node A")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = s.GetNodeStats(ctx)
+	require.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"context"
+	"io"
+
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// writer limits every file writer created by the wrapped backup.Writer.
+type writer struct {
+	backup.Writer
+	controller *Controller
+}
+
+// NewWriter wraps w, so that all files are written within the controller limits.
+// If c is nil, w is returned as is.
+func NewWriter(w backup.Writer, c *Controller) backup.Writer {
+	if c == nil || w == nil {
+		return w
+	}
+
+	return &writer{Writer: w, controller: c}
+}
+
+func (w *writer) NewWriter(ctx context.Context, filename string) (io.WriteCloser, error) {
+	wc, err := w.Writer.NewWriter(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &writeCloser{WriteCloser: wc, ctx: ctx, controller: w.controller}, nil
+}
+
+type writeCloser struct {
+	io.WriteCloser
+	ctx        context.Context
+	controller *Controller
+}
+
+func (w *writeCloser) Write(p []byte) (int, error) {
+	if err := w.controller.waitRecords(w.ctx); err != nil {
+		return 0, err
+	}
+
+	if err := w.controller.waitBytes(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+
+	return w.WriteCloser.Write(p)
+}
+
+// reader limits every file streamed by the wrapped backup.StreamingReader.
+type reader struct {
+	backup.StreamingReader
+	controller *Controller
+}
+
+// NewReader wraps r, so that all files are read within the controller limits.
+// If c is nil, r is returned as is.
+func NewReader(r backup.StreamingReader, c *Controller) backup.StreamingReader {
+	if c == nil || r == nil {
+		return r
+	}
+
+	return &reader{StreamingReader: r, controller: c}
+}
+
+func (r *reader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	filesCh := make(chan bModels.File)
+
+	go r.StreamingReader.StreamFiles(ctx, filesCh, errorsCh, skipPrefixes)
+
+	defer close(readersCh)
+
+	for file := range filesCh {
+		readersCh <- r.wrapFile(ctx, file)
+	}
+}
+
+func (r *reader) StreamFile(
+	ctx context.Context, filename string, readersCh chan<- bModels.File, errorsCh chan<- error,
+) {
+	filesCh := make(chan bModels.File, 1)
+
+	r.StreamingReader.StreamFile(ctx, filename, filesCh, errorsCh)
+	close(filesCh)
+
+	for file := range filesCh {
+		readersCh <- r.wrapFile(ctx, file)
+	}
+}
+
+func (r *reader) wrapFile(ctx context.Context, file bModels.File) bModels.File {
+	file.Reader = &readCloser{ReadCloser: file.Reader, ctx: ctx, controller: r.controller}
+
+	return file
+}
+
+type readCloser struct {
+	io.ReadCloser
+	ctx        context.Context
+	controller *Controller
+}
+
+func (r *readCloser) Read(p []byte) (int, error) {
+	if err := r.controller.waitRecords(r.ctx); err != nil {
+		return 0, err
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := r.controller.waitBytes(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,460 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// checkInterval is how often the schedule is checked for a new window.
+	checkInterval = 10 * time.Second
+	// fullRatePct means that limits are not reduced by adaptive throttling.
+	fullRatePct = 100
+	// recoveryStepPct is how much the rate is increased after each check without pressure.
+	recoveryStepPct = 10
+)
+
+// Controller applies time-window throttle profiles during a run.
+// backup-go limiters are fixed when the handler starts, so when a schedule is set they are disabled,
+// and the Controller limits the storage streams instead:
+//   - bandwidth is limited by the number of bytes written or read.
+//   - records per second are limited by the number of records processed by the handler,
+//     stopping the stream creates backpressure on the whole pipeline.
+//
+// With adaptive throttling, the Controller also polls cluster statistics and halves the rate
+// each time a threshold is exceeded, then increases it step by step while the cluster is healthy.
+// If a limit is not set, the throughput observed before the first back-off is reduced instead.
+type Controller struct {
+	schedule *models.Throttle
+	// Values used outside of schedule windows.
+	defaultRPS       int64
+	defaultBandwidth int64
+
+	rps       *rate.Limiter
+	bandwidth *rate.Limiter
+
+	mu sync.Mutex
+	// counters return the number of processed records for each running handler.
+	counters []func() uint64
+	// consumed is the number of records already taken from the rps limiter.
+	consumed uint64
+
+	currentRPS       int64
+	currentBandwidth int64
+	// Limits set to limiters after adaptive reduction, bandwidth is in bytes.
+	limitRPS       int64
+	limitBandwidth int64
+
+	adaptive *models.AdaptiveThrottle
+	stats    StatsGetter
+	// ratePct is the percentage of limits that is applied.
+	ratePct    int64
+	minRatePct int64
+	backoffs   int
+	// nodeErrors are error counters of each node from the previous check.
+	nodeErrors map[string]uint64
+	lastCheck  time.Time
+	// bytes is the number of bytes passed through the controller.
+	bytes atomic.Uint64
+	// Observed throughput, it is used as a base when a limit is not set.
+	lastRecords uint64
+	lastBytes   uint64
+	recordsRate float64
+	bytesRate   float64
+	baseRecords float64
+	baseBytes   float64
+
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+// NewController returns a new Controller. If schedule is empty, nil is returned,
+// nil Controller doesn't limit anything.
+// rps and bandwidth (in MiB/s) are applied outside of schedule windows.
+// Adaptive throttling starts only after a statistics source is set with WatchCluster.
+func NewController(schedule *models.Throttle, rps int, bandwidth int64, logger *slog.Logger) *Controller {
+	if schedule.IsEmpty() {
+		return nil
+	}
+
+	c := &Controller{
+		schedule:         schedule,
+		defaultRPS:       int64(rps),
+		defaultBandwidth: bandwidth,
+		rps:              rate.NewLimiter(rate.Inf, 0),
+		bandwidth:        rate.NewLimiter(rate.Inf, 0),
+		currentRPS:       -1,
+		currentBandwidth: -1,
+		limitRPS:         -1,
+		limitBandwidth:   -1,
+		adaptive:         schedule.Adaptive,
+		ratePct:          fullRatePct,
+		minRatePct:       fullRatePct,
+		nodeErrors:       make(map[string]uint64),
+		now:              time.Now,
+		logger:           logger,
+	}
+
+	c.apply()
+
+	return c
+}
+
+// WatchCluster sets the source of cluster statistics for adaptive throttling.
+// It does nothing if adaptive throttling is not configured.
+func (c *Controller) WatchCluster(stats StatsGetter) {
+	if c == nil || c.adaptive == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.stats = stats
+	c.mu.Unlock()
+}
+
+// Run checks the schedule and the cluster load periodically and updates limits until ctx is done.
+func (c *Controller) Run(ctx context.Context) {
+	if c == nil {
+		return
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	var adaptiveCh <-chan time.Time
+
+	c.mu.Lock()
+	if c.stats != nil {
+		c.lastCheck = c.now()
+		adaptiveTicker := time.NewTicker(c.adaptive.CheckInterval)
+
+		defer adaptiveTicker.Stop()
+
+		adaptiveCh = adaptiveTicker.C
+	}
+	c.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.apply()
+		case <-adaptiveCh:
+			c.check(ctx)
+		}
+	}
+}
+
+// Report logs adaptive throttling decisions made during the run.
+func (c *Controller) Report() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats == nil {
+		return
+	}
+
+	c.logger.Info("adaptive throttle report",
+		slog.Int("backoffs", c.backoffs),
+		slog.Int64("min_rate_pct", c.minRatePct),
+		slog.Int64("rate_pct", c.ratePct),
+	)
+}
+
+// AddRecordCounter registers a function that returns the number of records processed by a handler.
+func (c *Controller) AddRecordCounter(counter func() uint64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.counters = append(c.counters, counter)
+	c.mu.Unlock()
+}
+
+// apply sets limits for the current time and logs each change.
+func (c *Controller) apply() {
+	now := c.now()
+
+	rps, ok := models.ThrottleValueAt(c.schedule.RecordsPerSecond, now)
+	if !ok {
+		rps = c.defaultRPS
+	}
+
+	bandwidth, ok := models.ThrottleValueAt(c.schedule.Bandwidth, now)
+	if !ok {
+		bandwidth = c.defaultBandwidth
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if rps != c.currentRPS {
+		c.logger.Info("throttle records per second changed",
+			slog.Int64("from", max(c.currentRPS, 0)),
+			slog.Int64("to", rps),
+		)
+
+		c.currentRPS = rps
+	}
+
+	if bandwidth != c.currentBandwidth {
+		c.logger.Info("throttle bandwidth changed",
+			slog.Int64("from", max(c.currentBandwidth, 0)),
+			slog.Int64("to", bandwidth),
+		)
+
+		c.currentBandwidth = bandwidth
+	}
+
+	c.setLimits(now)
+}
+
+// setLimits applies current limits reduced by the adaptive rate. Must be called under lock.
+func (c *Controller) setLimits(now time.Time) {
+	rps := c.reduce(c.currentRPS, c.baseRecords)
+	if rps != c.limitRPS {
+		setLimit(c.rps, rps, now)
+		c.limitRPS = rps
+	}
+
+	bandwidth := c.reduce(c.currentBandwidth*1024*1024, c.baseBytes)
+	if bandwidth != c.limitBandwidth {
+		setLimit(c.bandwidth, bandwidth, now)
+		c.limitBandwidth = bandwidth
+	}
+}
+
+// reduce applies the adaptive rate to limit. If limit is not set, observed throughput is reduced.
+func (c *Controller) reduce(limit int64, observed float64) int64 {
+	if c.ratePct >= fullRatePct {
+		return limit
+	}
+
+	base := float64(limit)
+	if limit == 0 {
+		base = observed
+	}
+
+	// Nothing was processed yet, so there is nothing to reduce.
+	if base == 0 {
+		return limit
+	}
+
+	return max(int64(base*float64(c.ratePct)/fullRatePct), 1)
+}
+
+// check polls cluster statistics and changes the adaptive rate.
+func (c *Controller) check(ctx context.Context) {
+	stats, err := c.stats.GetNodeStats(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.logger.Warn("failed to get cluster statistics for adaptive throttle", slog.Any("error", err))
+		}
+
+		return
+	}
+
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := now.Sub(c.lastCheck).Seconds()
+	c.lastCheck = now
+
+	c.observe(elapsed)
+
+	node, reason := c.overloaded(stats, elapsed)
+
+	ratePct := c.ratePct
+
+	switch {
+	case reason != "":
+		ratePct = max(c.ratePct/2, c.adaptive.MinRatePct)
+	case c.ratePct < fullRatePct:
+		ratePct = min(c.ratePct+recoveryStepPct, fullRatePct)
+	}
+
+	if ratePct == c.ratePct {
+		return
+	}
+
+	if c.ratePct == fullRatePct {
+		// Remember throughput before the back-off, to reduce it if limits are not set.
+		c.baseRecords = c.recordsRate
+		c.baseBytes = c.bytesRate
+	}
+
+	if reason != "" {
+		c.backoffs++
+		c.logger.Warn("throttle backing off, cluster is under pressure",
+			slog.String("node", node),
+			slog.String("reason", reason),
+			slog.Int64("from_pct", c.ratePct),
+			slog.Int64("to_pct", ratePct),
+		)
+	} else {
+		c.logger.Info("throttle recovering",
+			slog.Int64("from_pct", c.ratePct),
+			slog.Int64("to_pct", ratePct),
+		)
+	}
+
+	c.ratePct = ratePct
+	c.minRatePct = min(c.minRatePct, ratePct)
+
+	c.setLimits(now)
+}
+
+// observe updates throughput since the previous check. Must be called under lock.
+func (c *Controller) observe(elapsed float64) {
+	var records uint64
+	for _, counter := range c.counters {
+		records += counter()
+	}
+
+	bytes := c.bytes.Load()
+
+	if elapsed > 0 {
+		c.recordsRate = float64(records-min(c.lastRecords, records)) / elapsed
+		c.bytesRate = float64(bytes-min(c.lastBytes, bytes)) / elapsed
+	}
+
+	c.lastRecords = records
+	c.lastBytes = bytes
+}
+
+// overloaded returns the first node that exceeds a threshold and the reason.
+// Error counters of all nodes are updated. Must be called under lock.
+func (c *Controller) overloaded(stats []NodeStats, elapsed float64) (node, reason string) {
+	for _, s := range stats {
+		prevErrors, seen := c.nodeErrors[s.Name]
+		c.nodeErrors[s.Name] = s.Errors
+
+		if reason != "" {
+			continue
+		}
+
+		switch {
+		case c.adaptive.MaxLatencyPct > 0 && latencyPct(s, c.adaptive.LatencyThreshold) > c.adaptive.MaxLatencyPct:
+			reason = fmt.Sprintf("%.2f%% of operations are slower than %s",
+				latencyPct(s, c.adaptive.LatencyThreshold), c.adaptive.LatencyThreshold)
+		case c.adaptive.MaxCPUPct > 0 && s.CPUPct > c.adaptive.MaxCPUPct:
+			reason = fmt.Sprintf("cpu usage is %d%%", s.CPUPct)
+		case c.adaptive.MaxErrorsPerSecond > 0 && seen && elapsed > 0 && s.Errors > prevErrors &&
+			float64(s.Errors-prevErrors)/elapsed > float64(c.adaptive.MaxErrorsPerSecond):
+			reason = fmt.Sprintf("%.0f client errors per second", float64(s.Errors-prevErrors)/elapsed)
+		}
+
+		if reason != "" {
+			node = s.Name
+		}
+	}
+
+	return node, reason
+}
+
+// latencyPct returns the max percentage of reads or writes slower than threshold.
+// If threshold is not a power of two, the closest smaller bucket is used.
+func latencyPct(s NodeStats, threshold time.Duration) float64 {
+	bucket := int(math.Log2(float64(threshold / time.Millisecond)))
+
+	var result float64
+
+	for _, hist := range [][]float64{s.ReadLatency, s.WriteLatency} {
+		if bucket < len(hist) {
+			result = max(result, hist[bucket])
+		}
+	}
+
+	return result
+}
+
+// setLimit updates the limiter, 0 means no limit.
+func setLimit(l *rate.Limiter, limit int64, now time.Time) {
+	if limit == 0 {
+		l.SetLimitAt(now, rate.Inf)
+		return
+	}
+
+	l.SetLimitAt(now, rate.Limit(limit))
+	l.SetBurstAt(now, int(limit))
+}
+
+// waitBytes blocks until n bytes are allowed by the bandwidth limit.
+func (c *Controller) waitBytes(ctx context.Context, n int) error {
+	c.bytes.Add(uint64(n))
+
+	return waitN(ctx, c.bandwidth, int64(n))
+}
+
+// waitRecords blocks until records processed since the previous call are allowed by the rps limit.
+func (c *Controller) waitRecords(ctx context.Context) error {
+	c.mu.Lock()
+
+	var total uint64
+	for _, counter := range c.counters {
+		total += counter()
+	}
+
+	var n uint64
+	if total > c.consumed {
+		n = total - c.consumed
+		c.consumed = total
+	}
+
+	c.mu.Unlock()
+
+	return waitN(ctx, c.rps, int64(n))
+}
+
+// waitN waits for n tokens, splitting the request by the limiter burst.
+func waitN(ctx context.Context, l *rate.Limiter, n int64) error {
+	for n > 0 {
+		if l.Limit() == rate.Inf {
+			return nil
+		}
+
+		chunk := min(n, int64(max(l.Burst(), 1)))
+		if err := l.WaitN(ctx, int(chunk)); err != nil {
+			// The limit could be lowered by the schedule after the burst was read.
+			if ctx.Err() == nil && chunk > int64(l.Burst()) {
+				continue
+			}
+
+			return err
+		}
+
+		n -= chunk
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-backup-cli/internal/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func testSchedule(t *testing.T) *models.Throttle {
+	t.Helper()
+
+	rps, err := models.ParseThrottleWindows(map[string]int64{
+		"00:00-06:00": 0,
+		"06:00-23:00": 100,
+	})
+	require.NoError(t, err)
+
+	bandwidth, err := models.ParseThrottleWindows(map[string]int64{
+		"06:00-23:00": 1,
+	})
+	require.NoError(t, err)
+
+	return &models.Throttle{RecordsPerSecond: rps, Bandwidth: bandwidth}
+}
+
+func TestNewController_Empty(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewController(nil, 10, 10, slog.Default()))
+	require.Nil(t, NewController(&models.Throttle{}, 10, 10, slog.Default()))
+
+	// Nil controller is a no-op.
+	var c *Controller
+	c.AddRecordCounter(func() uint64 { return 0 })
+	c.Run(context.Background())
+}
+
+func TestController_Apply(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	c := NewController(testSchedule(t), 50, 2, logger)
+
+	at := func(hour int) time.Time {
+		return time.Date(2024, 1, 1, hour, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		rps       rate.Limit
+		bandwidth rate.Limit
+	}{
+		{"night window", at(1), rate.Inf, 2 * 1024 * 1024},
+		{"day window", at(12), 100, 1024 * 1024},
+		{"outside windows", at(23), 50, 2 * 1024 * 1024},
+	}
+
+	for _, tt := range tests {
+		c.now = func() time.Time { return tt.now }
+		c.apply()
+
+		require.Equal(t, tt.rps, c.rps.Limit(), tt.name)
+		require.Equal(t, tt.bandwidth, c.bandwidth.Limit(), tt.name)
+	}
+
+	require.Contains(t, buf.String(), "throttle records per second changed")
+	require.Contains(t, buf.String(), "from=100 to=50")
+	require.Contains(t, buf.String(), "throttle bandwidth changed")
+}
+
+func TestController_WaitRecords(t *testing.T) {
+	t.Parallel()
+
+	c := NewController(testSchedule(t), 0, 0, slog.Default())
+	c.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local) }
+	c.apply()
+
+	var records atomic.Uint64
+
+	c.AddRecordCounter(records.Load)
+
+	ctx := context.Background()
+
+	// The first burst is free.
+	records.Store(100)
+	require.NoError(t, c.waitRecords(ctx))
+
+	// The next 20 records must wait about 200ms at 100 rps.
+	records.Store(120)
+
+	start := time.Now()
+	require.NoError(t, c.waitRecords(ctx))
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+
+	// Canceled context stops waiting.
+	records.Store(1000)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Error(t, c.waitRecords(cancelCtx))
+}
+
+func TestReadCloser_Read(t *testing.T) {
+	t.Parallel()
+
+	c := NewController(testSchedule(t), 0, 0, slog.Default())
+	c.now = func() time.Time { return time.Date(2024, 1, 1, 1, 0, 0, 0, time.Local) }
+	c.apply()
+
+	content := bytes.Repeat([]byte("a"), 1024)
+	r := &readCloser{ReadCloser: io.NopCloser(bytes.NewReader(content)), ctx: context.Background(), controller: c}
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+type fakeStats struct {
+	stats []NodeStats
+	err   error
+}
+
+func (f *fakeStats) GetNodeStats(context.Context) ([]NodeStats, error) {
+	return f.stats, f.err
+}
+
+func TestController_Check(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	schedule := &models.Throttle{Adaptive: &models.AdaptiveThrottle{
+		CheckInterval:      time.Second,
+		LatencyThreshold:   8 * time.Millisecond,
+		MaxLatencyPct:      5,
+		MaxCPUPct:          90,
+		MaxErrorsPerSecond: 10,
+		MinRatePct:         30,
+	}}
+
+	// Bandwidth is not limited, so the observed throughput is reduced.
+	c := NewController(schedule, 1000, 0, logger)
+	require.Equal(t, rate.Limit(1000), c.rps.Limit())
+	require.Equal(t, rate.Inf, c.bandwidth.Limit())
+
+	stats := &fakeStats{}
+	c.WatchCluster(stats)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	c.now = func() time.Time { return now }
+	c.lastCheck = now
+
+	check := func(nodeStats ...NodeStats) {
+		now = now.Add(time.Second)
+		stats.stats = nodeStats
+
+		c.check(context.Background())
+	}
+
+	healthy := NodeStats{Name: "A", ReadLatency: []float64{10, 5, 2, 1}, CPUPct: 50, Errors: 100}
+	check(healthy)
+	require.Equal(t, int64(fullRatePct), c.ratePct)
+
+	// 1 MiB was written during the second before the back-off.
+	c.bytes.Store(1024 * 1024)
+
+	slow := healthy
+	slow.WriteLatency = []float64{50, 40, 30, 20}
+	check(slow)
+	require.Equal(t, int64(50), c.ratePct)
+	require.Equal(t, rate.Limit(500), c.rps.Limit())
+	require.Equal(t, rate.Limit(512*1024), c.bandwidth.Limit())
+
+	busy := healthy
+	busy.CPUPct = 95
+	check(busy)
+	// Rate can't be lower than the min rate.
+	require.Equal(t, int64(30), c.ratePct)
+
+	check(healthy)
+	require.Equal(t, int64(40), c.ratePct)
+
+	// 100 errors per second.
+	failing := healthy
+	failing.Errors = 200
+	check(failing)
+	require.Equal(t, int64(30), c.ratePct)
+
+	for range 7 {
+		check(failing)
+	}
+
+	require.Equal(t, int64(fullRatePct), c.ratePct)
+	require.Equal(t, rate.Limit(1000), c.rps.Limit())
+	require.Equal(t, rate.Inf, c.bandwidth.Limit())
+
+	// Errors of the statistics source don't change limits.
+	stats.err = errors.New("timeout")
+	check(busy)
+	require.Equal(t, int64(fullRatePct), c.ratePct)
+
+	c.Report()
+
+	out := buf.String()
+	require.Contains(t, out, "throttle backing off, cluster is under pressure")
+	require.Contains(t, out, "reason=\"20.00% of operations are slower than 8ms\"")
+	require.Contains(t, out, "reason=\"cpu usage is 95%\"")
+	require.Contains(t, out, "reason=\"100 client errors per second\"")
+	require.Contains(t, out, "throttle recovering")
+	require.Contains(t, out, "failed to get cluster statistics for adaptive throttle")
+	require.Contains(t, out, "backoffs=3 min_rate_pct=30 rate_pct=100")
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xdrmsg parses XDR messages, that are stored in payloads of .asbx records.
+package xdrmsg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+)
+
+const (
+	// FieldTypeLUT is the message field with the last update time of the record, it is sent only by XDR.
+	FieldTypeLUT = 14
+	// CitrusleafEpoch is the Aerospike epoch 2010-01-01T00:00:00Z in unix seconds.
+	// The last update time is counted in milliseconds from it.
+	CitrusleafEpoch = 1262304000
+)
+
+// Parse parses the XDR message stored in the record payload.
+func Parse(payload []byte) (*xdr.AerospikeMessage, error) {
+	if len(payload) < xdr.LenProtoHeader {
+		return nil, fmt.Errorf("payload is too short: %d bytes", len(payload))
+	}
+
+	return xdr.ParseAerospikeMessage(payload[xdr.LenProtoHeader:])
+}
+
+// LUT returns the last update time of the record from the message fields.
+// Returns false if the message doesn't contain it.
+func LUT(fields []*xdr.Field) (time.Time, bool) {
+	for _, field := range fields {
+		if field != nil && field.Type == FieldTypeLUT && len(field.Data) == 8 {
+			return lutToTime(binary.BigEndian.Uint64(field.Data)), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// lutToTime converts the last update time in milliseconds since the Aerospike epoch to time.
+func lutToTime(lut uint64) time.Time {
+	//nolint:gosec // The last update time fits in 40 bits.
+	return time.UnixMilli(CitrusleafEpoch*1000 + int64(lut)).UTC()
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xdrmsg
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse(make([]byte, xdr.LenProtoHeader-1))
+	require.ErrorContains(t, err, "payload is too short")
+
+	header := make([]byte, xdr.LenMessageHeader)
+	header[0] = xdr.LenMessageHeader
+	header[2] = xdr.MsgInfo2Delete
+
+	msg, err := Parse(xdr.NewPayload(header))
+	require.NoError(t, err)
+	require.NotZero(t, msg.Info2&xdr.MsgInfo2Delete)
+}
+
+func TestLUT(t *testing.T) {
+	t.Parallel()
+
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, 1500)
+
+	lut, ok := LUT([]*xdr.Field{nil, {Type: xdr.FieldTypeSet, Data: []byte("demo")}, {Type: FieldTypeLUT, Data: data}})
+	require.True(t, ok)
+	require.Equal(t, time.Date(2010, 1, 1, 0, 0, 1, 500_000_000, time.UTC), lut)
+
+	_, ok = LUT([]*xdr.Field{{Type: xdr.FieldTypeSet, Data: []byte("demo")}})
+	require.False(t, ok)
+
+	// Invalid field size.
+	_, ok = LUT([]*xdr.Field{{Type: FieldTypeLUT, Data: data[:4]}})
+	require.False(t, ok)
+}
+
+func TestLUTToTime(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), lutToTime(0))
+	require.Equal(t, time.Date(2010, 1, 1, 0, 0, 1, 500_000_000, time.UTC), lutToTime(1500))
+}